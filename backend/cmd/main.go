@@ -5,17 +5,24 @@ import (
 	"net/http"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "github.com/DEVunderdog/transcript-generator-backend/docs"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/api"
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectgc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/reconcile"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 )
 
+// shutdownTimeout bounds how long in-flight requests get to finish once a shutdown signal arrives.
+const shutdownTimeout = 30 * time.Second
+
 // @title Transcript Generator API
 // @version 1.0
 // @description API for generating transcript from audio files using OpenAI Whisper Model, please note that you will receive the transcript.pdf file on your registered email address.
@@ -54,6 +61,31 @@ func main() {
 		baseLogger.Fatal().Err(err).Msg("error creating server")
 	}
 
+	// the reconciler stops with ctx, reconcilerDone lets shutdown wait for the pass in progress
+	reconcilerDone := make(chan struct{})
+	var reconcilerStore objectstore.ObjectStore
+
+	if config.ReconcilerEnabled {
+		reconcilerStore, err = objectstore.NewObjectStore(ctx, config)
+		if err != nil {
+			baseLogger.Fatal().Err(err).Msg("error creating object store for reconciler")
+		}
+
+		var collector *objectgc.Collector
+		if config.OrphanGCEnabled {
+			collector = objectgc.NewCollector(store, reconcilerStore, baseLogger, objectgc.OptionsFromConfig(*config))
+		}
+
+		reconciler := reconcile.NewReconciler(store, reconcilerStore, collector, baseLogger, reconcile.OptionsFromConfig(*config))
+
+		go func() {
+			defer close(reconcilerDone)
+			reconciler.Run(ctx)
+		}()
+	} else {
+		close(reconcilerDone)
+	}
+
 	srv := server.Start()
 
 	go func() {
@@ -66,8 +98,21 @@ func main() {
 
 	stop()
 
-	if err := server.ServerShutdown(ctx, srv); err != nil {
-		baseLogger.Fatal().Err(err).Msg("error while server is shutting down")
+	// ctx is cancelled by now, the drain gets a deadline of its own
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// the reconciler is still waited for below, a failed drain must not skip it
+	if err := server.ServerShutdown(shutdownCtx, srv); err != nil {
+		baseLogger.Error().Err(err).Msg("error while server is shutting down")
+	}
+
+	<-reconcilerDone
+
+	if reconcilerStore != nil {
+		if err := reconcilerStore.Close(); err != nil {
+			baseLogger.Error().Err(err).Msg("error closing reconciler object store")
+		}
 	}
 
 	baseLogger.Info().Msg("Bye :)")
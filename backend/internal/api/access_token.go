@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+)
+
+const jwksMaxAge = 5 * time.Minute
+
+type accessTokenRequest struct {
+	Scopes []string `json:"scopes"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+type jwksResponse struct {
+	Keys []token.JWK `json:"keys"`
+}
+
+// @Summary Exchange API Key
+// @Description Exchanges the api key for a short lived jwt access token, send it as "Bearer <token>" in the Authorization header.
+// @Description The token never outlives the api key's expiry, and stops working within the auth cache ttl once the key is revoked.
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body accessTokenRequest false "Optionally narrow the scopes of the token"
+// @Success 201 {object} standardResponse{response=responseData{data=accessTokenResponse}} "access token issued"
+// @Failure 400 {object} standardResponse "invalid request"
+// @Failure 403 {object} standardResponse "scopes exceed the api key or request authenticated by an access token"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/token [POST]
+func (server *Server) createAccessToken(ctx *gin.Context) {
+	var req accessTokenRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+			return
+		}
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	// otherwise an access token could keep renewing itself past the api key's revocation
+	if payload.AccessToken {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "access tokens can only be exchanged for the api key itself", nil)
+		return
+	}
+
+	if len(req.Scopes) > 0 {
+		if !token.HasScopes(payload.Scopes, req.Scopes) {
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, "cannot grant scopes the api key does not hold", nil)
+			return
+		}
+		payload.Scopes = req.Scopes
+	}
+
+	opts := token.AccessTokenOptionsFromConfig(*server.config)
+
+	if !payload.KeyExpiresAt.IsZero() {
+		opts.Duration = min(opts.Duration, time.Until(payload.KeyExpiresAt).Truncate(time.Second))
+	}
+
+	accessToken, claims, err := server.tokenMaker.CreateAccessToken(ctx, payload, opts)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while creating access token")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating access token", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "access token issued", accessTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(opts.Duration.Seconds()),
+		Scope:       claims.Scope,
+	})
+}
+
+// jwks serves the public keys which verify access tokens at /.well-known/jwks.json, outside the /server
+// base path so it stays out of the swagger docs, retired signing keys are left out.
+func (server *Server) jwks(ctx *gin.Context) {
+	keys, err := server.store.ListVerificationKeys(ctx, server.config.KeysPurpose)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing signing keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing signing keys", nil)
+		return
+	}
+
+	response := jwksResponse{Keys: make([]token.JWK, 0, len(keys))}
+
+	for _, key := range keys {
+		publicKey, err := token.GetPublicKey([]byte(key.PublicKey))
+		if err != nil {
+			server.baseLogger.Error().Err(err).Int32("key_id", key.ID).Msg("error while parsing signing key")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while parsing signing key", nil)
+			return
+		}
+
+		response.Keys = append(response.Keys, token.NewJWK(key.ID, publicKey))
+	}
+
+	ctx.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(jwksMaxAge.Seconds())))
+	ctx.JSON(http.StatusOK, response)
+}
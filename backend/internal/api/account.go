@@ -0,0 +1,237 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/transcriptformat"
+	"github.com/gin-gonic/gin"
+)
+
+type accountDeletionResponse struct {
+	DeletionID  int32     `json:"deletion_id"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+type accountExport struct {
+	ID              int32               `json:"id"`
+	Email           string              `json:"email"`
+	Role            string              `json:"role"`
+	Plan            string              `json:"plan"`
+	EmailVerifiedAt *time.Time          `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	APIKeys         []apiKeyDetails     `json:"api_keys"`
+	Collections     []collectionDetails `json:"collections"`
+	Tags            []tagDetails        `json:"tags"`
+	ExportedAt      time.Time           `json:"exported_at"`
+}
+
+// @Summary Delete Account
+// @Description Deletes the account: every api key stops working right away, audio files, transcripts, jobs and keys are purged in the background.
+// @Description The calling key must hold every default scope.
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 202 {object} standardResponse{response=responseData{data=accountDeletionResponse}} "account scheduled for deletion"
+// @Failure 403 {object} standardResponse "calling key lacks scopes"
+// @Failure 409 {object} standardResponse "account already scheduled for deletion"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/account [DELETE]
+func (server *Server) deleteAccount(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	// a narrowly scoped key handed to some integration must not be able to wipe the account
+	if !token.HasScopes(payload.Scopes, token.DefaultScopes) {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "deleting the account requires a key holding every default scope", nil)
+		return
+	}
+
+	deletion, err := server.store.RequestAccountDeletionTx(ctx, int32(payload.UserID))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrResourceConflict) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "account already scheduled for deletion", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while requesting account deletion")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting account", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(int32(payload.UserID))
+	server.purger.Wake()
+
+	server.enhanceHTTPResponse(ctx, http.StatusAccepted, "account scheduled for deletion", accountDeletionResponse{
+		DeletionID:  deletion.ID,
+		Status:      deletion.Status,
+		RequestedAt: deletion.RequestedAt,
+	})
+}
+
+// @Summary Export Account
+// @Description Downloads a ZIP archive of the account, its api key metadata, collections and tags, the file registry, transcript jobs and every finished transcript as JSON and plain text
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Produce application/zip
+// @Success 200 {file} file "ZIP archive"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/account/export [GET]
+func (server *Server) exportAccount(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	userID := int32(payload.UserID)
+
+	user, err := server.store.GetUserDetails(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while fetching user for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	keys, err := server.store.ListAPIKeys(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing api keys for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	collections, err := server.store.ListCollections(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing collections for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	tags, err := server.store.ListTags(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing tags for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	files, err := server.store.ListExportFiles(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing files for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	jobs, err := server.store.ListExportTranscriptJobs(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing transcript jobs for export")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while exporting account", nil)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("account-%d-%s.zip", userID, now.Format("20060102"))))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Status(http.StatusOK)
+
+	// transcripts are streamed one job at a time, once the archive has started an error can only cut it short
+	archive := zip.NewWriter(ctx.Writer)
+
+	err = writeExportArchive(ctx, server.store, archive, accountExport{
+		ID:              user.ID,
+		Email:           user.Email,
+		Role:            user.Role,
+		Plan:            user.Plan,
+		EmailVerifiedAt: optionalTime(user.EmailVerifiedAt),
+		CreatedAt:       user.CreatedAt.Time,
+		APIKeys:         newAPIKeyDetails(keys),
+		Collections:     newCollectionSummaries(collections),
+		Tags:            newTagSummaries(tags),
+		ExportedAt:      now,
+	}, files, jobs)
+	if err == nil {
+		err = archive.Close()
+	}
+
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while writing account export")
+		ctx.Abort()
+	}
+}
+
+func writeExportArchive(ctx *gin.Context, store database.Store, archive *zip.Writer, account accountExport, files []database.ListExportFilesRow, jobs []database.ListExportTranscriptJobsRow) error {
+	if err := writeJSONEntry(archive, "account.json", account); err != nil {
+		return err
+	}
+
+	if err := writeJSONEntry(archive, "files.json", files); err != nil {
+		return err
+	}
+
+	if err := writeJSONEntry(archive, "transcript_jobs.json", jobs); err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.Status != database.JobSucceeded {
+			continue
+		}
+
+		rows, err := store.ListTranscriptSegments(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+
+		response := transcriptResponse{
+			FileID:   job.FileID,
+			FileName: job.FileName,
+			JobID:    job.ID,
+			Segments: make([]transcriptSegmentResponse, 0, len(rows)),
+		}
+		segments := make([]transcriptformat.Segment, 0, len(rows))
+
+		for _, row := range rows {
+			response.Segments = append(response.Segments, transcriptSegmentResponse{
+				Start:   float64(row.StartMs) / 1000,
+				End:     float64(row.EndMs) / 1000,
+				Speaker: row.Speaker.String,
+				Text:    row.Content,
+			})
+			segments = append(segments, transcriptformat.Segment{
+				Start:   time.Duration(row.StartMs) * time.Millisecond,
+				End:     time.Duration(row.EndMs) * time.Millisecond,
+				Speaker: row.Speaker.String,
+				Text:    row.Content,
+			})
+		}
+
+		if err := writeJSONEntry(archive, fmt.Sprintf("transcripts/%d.json", job.ID), response); err != nil {
+			return err
+		}
+
+		entry, err := archive.Create(fmt.Sprintf("transcripts/%d.txt", job.ID))
+		if err != nil {
+			return err
+		}
+
+		if _, err := entry.Write([]byte(transcriptformat.RenderText(segments))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeJSONEntry(archive *zip.Writer, name string, value any) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
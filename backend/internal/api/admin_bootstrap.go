@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+)
+
+// promoteConfiguredAdmins grants the admin role to the users listed in ADMIN_EMAILS, which is the only way to get
+// the first admin since the admin api itself needs one. Emails are matched regardless of case, users that have not
+// registered yet are promoted on the first start after they do. Removing an email never takes the role away, that
+// is done through the admin api.
+func promoteConfiguredAdmins(ctx context.Context, store database.Store, emails []string, baseLogger *logger.Logger) error {
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" {
+			normalized = append(normalized, email)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	promoted, err := store.PromoteUsersByEmail(ctx, database.PromoteUsersByEmailParams{
+		Role:   database.RoleAdmin,
+		Emails: normalized,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, user := range promoted {
+		baseLogger.Info().Int32("user_id", user.ID).Msgf("promoted %s to admin from ADMIN_EMAILS", user.Email)
+	}
+
+	return nil
+}
@@ -0,0 +1,497 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultUsersPageLimit = 50
+
+type listUsersQuery struct {
+	Email     string `form:"email" binding:"omitempty,max=255"`
+	Role      string `form:"role" binding:"omitempty,oneof=USER ADMIN"`
+	Suspended *bool  `form:"suspended"`
+	Limit     int32  `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset    int32  `form:"offset" binding:"omitempty,min=0"`
+}
+
+type userSummary struct {
+	ID              int32      `json:"id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	Plan            string     `json:"plan"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type userDetails struct {
+	userSummary
+	SuspensionReason string    `json:"suspension_reason,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type suspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=USER ADMIN"`
+}
+
+type revokeUserAPIKeysResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// adminTargetUser parses the :id of the user an admin acts on and makes sure the user exists.
+func (server *Server) adminTargetUser(ctx *gin.Context) (int32, bool) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return 0, false
+	}
+
+	if _, err := server.store.GetUserStatus(ctx, int32(userID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "user not found", nil)
+			return 0, false
+		}
+		server.baseLogger.Error().Err(err).Msg("error while fetching user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching user", nil)
+		return 0, false
+	}
+
+	return int32(userID), true
+}
+
+// @Summary List Users
+// @Description Lists users ordered by id, optionally searching by email and filtering by role or suspension
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param email query string false "Case insensitive substring of the email"
+// @Param role query string false "Filter by role" Enums(USER, ADMIN)
+// @Param suspended query bool false "Only suspended or only active users"
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param offset query int false "Number of users to skip"
+// @Success 200 {object} standardResponse{response=responseData{data=[]userSummary}} "users fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users [GET]
+func (server *Server) listUsers(ctx *gin.Context) {
+	var query listUsersQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request query")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultUsersPageLimit
+	}
+
+	params := database.ListUsersParams{
+		Email: pgtype.Text{
+			Valid:  query.Email != "",
+			String: escapeLikePattern(query.Email),
+		},
+		Role: pgtype.Text{
+			Valid:  query.Role != "",
+			String: query.Role,
+		},
+		PageLimit:  query.Limit,
+		PageOffset: query.Offset,
+	}
+
+	if query.Suspended != nil {
+		params.Suspended = pgtype.Bool{
+			Valid: true,
+			Bool:  *query.Suspended,
+		}
+	}
+
+	users, err := server.store.ListUsers(ctx, params)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing users")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing users", nil)
+		return
+	}
+
+	summaries := make([]userSummary, 0, len(users))
+	for _, user := range users {
+		summaries = append(summaries, userSummary{
+			ID:              user.ID,
+			Email:           user.Email,
+			Role:            user.Role,
+			Plan:            user.Plan,
+			EmailVerifiedAt: optionalTime(user.EmailVerifiedAt),
+			SuspendedAt:     optionalTime(user.SuspendedAt),
+			CreatedAt:       user.CreatedAt.Time,
+		})
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "users fetched successfully", summaries)
+}
+
+// @Summary Get User
+// @Description Get a user along with the reason of a suspension
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} standardResponse{response=responseData{data=userDetails}} "user fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id} [GET]
+func (server *Server) getUserDetails(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return
+	}
+
+	user, err := server.store.GetUserDetails(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "user not found", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while fetching user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching user", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "user fetched successfully", userDetails{
+		userSummary: userSummary{
+			ID:              user.ID,
+			Email:           user.Email,
+			Role:            user.Role,
+			Plan:            user.Plan,
+			EmailVerifiedAt: optionalTime(user.EmailVerifiedAt),
+			SuspendedAt:     optionalTime(user.SuspendedAt),
+			CreatedAt:       user.CreatedAt.Time,
+		},
+		SuspensionReason: user.SuspensionReason.String,
+		UpdatedAt:        user.UpdatedAt.Time,
+	})
+}
+
+// @Summary Suspend User
+// @Description Suspends an account, every api key and access token of the user is refused until it is unsuspended
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body suspendUserRequest false "Reason recorded with the suspension"
+// @Success 200 {object} standardResponse "user suspended"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/suspend [POST]
+func (server *Server) suspendUser(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return
+	}
+
+	var req suspendUserRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+			return
+		}
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	if int32(userID) == int32(payload.UserID) {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "cannot suspend your own account", nil)
+		return
+	}
+
+	rows, err := server.store.SuspendUser(ctx, database.SuspendUserParams{
+		ID: int32(userID),
+		Reason: pgtype.Text{
+			Valid:  req.Reason != "",
+			String: req.Reason,
+		},
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while suspending user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while suspending user", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "user not found", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(int32(userID))
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "user suspended", nil)
+}
+
+// @Summary Unsuspend User
+// @Description Lifts the suspension of an account
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} standardResponse "user unsuspended"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/unsuspend [POST]
+func (server *Server) unsuspendUser(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return
+	}
+
+	rows, err := server.store.UnsuspendUser(ctx, int32(userID))
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while unsuspending user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while unsuspending user", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "user not found", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(int32(userID))
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "user unsuspended", nil)
+}
+
+// @Summary Update User Role
+// @Description Grants or takes away the admin role, admins cannot change their own role.
+// @Description The first admin is bootstrapped by listing their email in ADMIN_EMAILS, which is applied at startup.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body updateUserRoleRequest true "New role"
+// @Success 200 {object} standardResponse "user role updated"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/role [PUT]
+func (server *Server) updateUserRole(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	if int32(userID) == int32(payload.UserID) {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "cannot change your own role", nil)
+		return
+	}
+
+	rows, err := server.store.UpdateUserRole(ctx, database.UpdateUserRoleParams{
+		ID:   int32(userID),
+		Role: req.Role,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while updating user role")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while updating user role", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "user not found", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(int32(userID))
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "user role updated", nil)
+}
+
+// @Summary List User API Keys
+// @Description Lists the api keys of any user, the secrets themselves are never returned
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} standardResponse{response=responseData{data=[]apiKeyDetails}} "api keys fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/keys [GET]
+func (server *Server) listUserAPIKeys(ctx *gin.Context) {
+	userID, ok := server.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	keys, err := server.store.ListAPIKeys(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing api keys", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api keys fetched successfully", newAPIKeyDetails(keys))
+}
+
+// @Summary Revoke User API Key
+// @Description Revokes one api key of any user
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Param key_id path int true "API Key ID"
+// @Success 200 {object} standardResponse "api key revoked successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "api key not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/keys/{key_id} [DELETE]
+func (server *Server) revokeUserAPIKey(ctx *gin.Context) {
+	userID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid user id", nil)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(ctx.Param("key_id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid key id", nil)
+		return
+	}
+
+	rows, err := server.store.DeleteAPIKeyByID(ctx, database.DeleteAPIKeyByIDParams{
+		ID:     int32(keyID),
+		UserID: int32(userID),
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while revoking api key")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while revoking api key", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "api key not found", nil)
+		return
+	}
+
+	server.keyCache.InvalidateAPIKey(int32(keyID))
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api key revoked successfully", nil)
+}
+
+// @Summary Revoke All User API Keys
+// @Description Revokes every api key of any user, the user can regain access through recovery unless suspended
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} standardResponse{response=responseData{data=revokeUserAPIKeysResponse}} "api keys revoked successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/keys [DELETE]
+func (server *Server) revokeUserAPIKeys(ctx *gin.Context) {
+	userID, ok := server.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	revoked, err := server.store.DeleteAPIKeysByUser(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while revoking api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while revoking api keys", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(userID)
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api keys revoked successfully", revokeUserAPIKeysResponse{
+		Revoked: revoked,
+	})
+}
+
+// @Summary List User Files
+// @Description Lists the file registry of any user with the same filters as the user's own listing, pass upload_status to see pending or failed rows
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param name_prefix query string false "Only files whose name starts with this prefix"
+// @Param upload_status query string false "Filter by upload status" Enums(PENDING, SUCCESS, FAILED)
+// @Param sort_by query string false "Sort field, defaults to created_at" Enums(created_at, file_name, size)
+// @Param order query string false "Sort order, defaults to desc" Enums(asc, desc)
+// @Param collection_id query int false "Only files inside this collection"
+// @Param recursive query bool false "With collection_id, also files of collections nested inside it"
+// @Param tag query []string false "Only files carrying every one of these tags, matched regardless of case" collectionFormat(multi)
+// @Success 200 {object} standardResponse "files fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user or collection not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/files [GET]
+func (server *Server) listUserFiles(ctx *gin.Context) {
+	userID, ok := server.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	server.listFilesOfUser(ctx, userID)
+}
+
+// @Summary List User Transcript Jobs
+// @Description Lists the transcript jobs of any user, newest first
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Param status query string false "Filter by job status" Enums(QUEUED, PROCESSING, SUCCEEDED, FAILED)
+// @Param limit query int false "Page size, defaults to 20 and at most 100"
+// @Param offset query int false "Number of jobs to skip"
+// @Success 200 {object} standardResponse "jobs fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 404 {object} standardResponse "user not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/users/{id}/jobs [GET]
+func (server *Server) listUserTranscriptJobs(ctx *gin.Context) {
+	userID, ok := server.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	server.listJobsOfUser(ctx, userID)
+}
@@ -1,90 +1,300 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type apiKeyRequest struct {
-	Email string `json:"email" binding:"required"`
+const defaultAPIKeyName = "default"
+
+// newSignedAPIKey issues a key signed by whichever signer is active right now.
+func (server *Server) newSignedAPIKey(ctx context.Context) (*token.GeneratedAPIKey, int32, error) {
+	signingKeyID, privateKey, err := server.tokenMaker.Signer(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	apiKey, err := token.GenerateAndSignAPIKey(privateKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return apiKey, signingKeyID, nil
+}
+
+func optionalTime(value pgtype.Timestamptz) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
 }
 
 type apiKeyResponse struct {
 	APIKey string `json:"api_key"`
 }
 
-// @Summary Generate API Key
-// @Description Registers a user and generates an API Key
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type apiKeyDetails struct {
+	ID         int32      `json:"id"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAPIKeyDetails(keys []database.ListAPIKeysRow) []apiKeyDetails {
+	details := make([]apiKeyDetails, 0, len(keys))
+	for _, key := range keys {
+		prefix := ""
+		if key.KeyID.Valid {
+			prefix = token.DisplayPrefix(key.KeyID.String)
+		}
+
+		details = append(details, apiKeyDetails{
+			ID:         key.ID,
+			Prefix:     prefix,
+			Name:       key.Name,
+			Scopes:     key.Scopes,
+			ExpiresAt:  optionalTime(key.ExpiresAt),
+			LastUsedAt: optionalTime(key.LastUsedAt),
+			CreatedAt:  key.CreatedAt.Time,
+		})
+	}
+
+	return details
+}
+
+type createAPIKeyResponse struct {
+	ID        int32      `json:"id"`
+	Prefix    string     `json:"prefix"`
+	Name      string     `json:"name"`
+	APIKey    string     `json:"api_key"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// @Summary Delete API Key
+// @Description Request to delete the API Key
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse "api key deleted successfully"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/api/delete [DELETE]
+func (server *Server) deleteAPIKey(ctx *gin.Context) {
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	_, err := server.store.DeleteAPIKeyByID(ctx, database.DeleteAPIKeyByIDParams{
+		ID:     int32(payload.KeyID),
+		UserID: int32(payload.UserID),
+	})
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting api keys from database", err.Error())
+		return
+	}
+
+	server.keyCache.InvalidateAPIKey(int32(payload.KeyID))
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api key deleted successfully", nil)
+}
+
+// @Summary List API Keys
+// @Description Lists every api key of the user, the secrets themselves are never returned
+// @Tags Authentication
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse{response=responseData{data=[]apiKeyDetails}} "api keys fetched successfully"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/api/keys [GET]
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	keys, err := server.store.ListAPIKeys(ctx, int32(payload.UserID))
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing api keys", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api keys fetched successfully", newAPIKeyDetails(keys))
+}
+
+// @Summary Create API Key
+// @Description Creates an additional named api key, its scopes and expiry must be covered by the calling key. Users with the admin role may also grant the admin scope
 // @Tags Authentication
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
-// @Param request body apiKeyRequest true "User Email"
-// @Success 201 {object} apiKeyResponse "api keys created"
+// @Param request body createAPIKeyRequest true "Key name, scopes and optional expiry"
+// @Success 201 {object} standardResponse{response=responseData{data=createAPIKeyResponse}} "api key created"
 // @Failure 400 {object} standardResponse "invalid request"
-// @Failure 403 {object} standardResponse "user already present"
+// @Failure 403 {object} standardResponse "scopes or expiry exceed the calling key"
+// @Failure 409 {object} standardResponse "api key name already in use"
 // @Failure 500 {object} standardResponse "Internal Server Error"
-// @Router /api/register [POST]
-func (server *Server) generateAPIKey(ctx *gin.Context) {
-
-	var req apiKeyRequest
+// @Router /auth/api/keys [POST]
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
 		return
 	}
 
-	user, err := server.store.CreateUsers(ctx, req.Email)
-	if err != nil {
-		if database.ErrorCode(err) == database.UniqueViolation {
-			server.enhanceHTTPResponse(ctx, http.StatusForbidden, "user already present", err.Error())
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = token.DefaultScopes
+	}
+
+	for _, scope := range scopes {
+		if !token.IsValidScope(scope) {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "unknown scope: "+scope, nil)
+			return
+		}
+	}
+
+	// the admin scope is the user's to hand out once they hold the admin role, every other scope must be held by the calling key
+	required := scopes
+	if payload.Role == database.RoleAdmin {
+		required = slices.DeleteFunc(slices.Clone(scopes), func(scope string) bool {
+			return scope == token.ScopeAdmin
+		})
+	}
+
+	if !token.HasScopes(payload.Scopes, required) {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "cannot grant scopes the calling api key does not hold", nil)
+		return
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "expires_at must be in the future", nil)
 			return
 		}
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating user in database", err.Error())
+		expiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	// like its scopes, a key's lifetime must be covered by the calling key, otherwise an expiring key mints a permanent one
+	if !payload.KeyExpiresAt.IsZero() && (req.ExpiresAt == nil || req.ExpiresAt.After(payload.KeyExpiresAt)) {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "expires_at cannot be later than the expiry of the calling api key", gin.H{
+			"max_expires_at": payload.KeyExpiresAt,
+		})
 		return
 	}
 
-	apiKey, signature, err := token.GenerateAndSignAPIKey(server.tokenMaker.PrivateKey)
+	apiKey, signingKeyID, err := server.newSignedAPIKey(ctx)
 	if err != nil {
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error creating api keys", err.Error())
+		server.baseLogger.Error().Err(err).Msg("error creating api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error creating api keys", nil)
 		return
 	}
 
-	_, err = server.store.CreateAPIKey(ctx, database.CreateAPIKeyParams{
-		UserID:     user.ID,
-		Credential: []byte(apiKey),
-		Signature:  signature,
+	created, err := server.store.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		UserID:       int32(payload.UserID),
+		KeyID:        pgtype.Text{String: apiKey.KeyID, Valid: true},
+		SecretHash:   apiKey.SecretHash,
+		Signature:    apiKey.Signature,
+		SigningKeyID: signingKeyID,
+		Name:         req.Name,
+		Scopes:       scopes,
+		ExpiresAt:    expiresAt,
 	})
-
 	if err != nil {
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating api keys in database", err.Error())
+		if database.ErrorCode(err) == database.UniqueViolation {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "api key name already in use", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while creating api keys in database")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating api keys in database", nil)
 		return
 	}
 
-	server.enhanceHTTPResponse(ctx, http.StatusCreated, "api keys created", apiKeyResponse{
-		APIKey: apiKey,
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "api key created", createAPIKeyResponse{
+		ID:        created.ID,
+		Name:      created.Name,
+		Prefix:    token.DisplayPrefix(apiKey.KeyID),
+		APIKey:    apiKey.Key,
+		Scopes:    created.Scopes,
+		ExpiresAt: req.ExpiresAt,
 	})
 }
 
-// @Summary Delete API Key
-// @Description Request to delete the API Key
+// @Summary Revoke API Key
+// @Description Revokes one of the user's api keys by id, a key cannot revoke a key holding scopes it lacks
 // @Tags Authentication
 // @Security ApiKeyAuth
 // @Produce json
-// @Success 200 {object} standardResponse "api key deleted successfully"
+// @Param id path int true "API Key ID"
+// @Success 200 {object} standardResponse "api key revoked successfully"
+// @Failure 400 {object} standardResponse "invalid key id"
+// @Failure 403 {object} standardResponse "key holds scopes the calling key lacks"
+// @Failure 404 {object} standardResponse "api key not found"
 // @Failure 500 {object} standardResponse "Internal Server Error"
-// @Router /auth/api/delete [DELETE]
-func (server *Server) deleteAPIKey(ctx *gin.Context) {
+// @Router /auth/api/keys/{id} [DELETE]
+func (server *Server) revokeAPIKey(ctx *gin.Context) {
+	keyID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid key id", nil)
+		return
+	}
 
 	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
 
-	err := server.store.DeleteAPIKey(ctx, []byte(payload.APIKey))
+	key, err := server.store.GetAPIKeyByID(ctx, database.GetAPIKeyByIDParams{
+		ID:     int32(keyID),
+		UserID: int32(payload.UserID),
+	})
 	if err != nil {
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting api keys from database", err.Error())
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "api key not found", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while fetching api key")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching api key", nil)
 		return
 	}
 
-	server.enhanceHTTPResponse(ctx, http.StatusOK, "api key deleted successfully", nil)
+	if !token.HasScopes(payload.Scopes, key.Scopes) {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "cannot revoke an api key holding scopes the calling api key does not hold", nil)
+		return
+	}
+
+	rows, err := server.store.DeleteAPIKeyByID(ctx, database.DeleteAPIKeyByIDParams{
+		ID:     key.ID,
+		UserID: int32(payload.UserID),
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while revoking api key")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while revoking api key", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "api key not found", nil)
+		return
+	}
+
+	server.keyCache.InvalidateAPIKey(key.ID)
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "api key revoked successfully", nil)
 }
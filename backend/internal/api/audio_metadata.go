@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/audio"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const unsupportedAudioMessage = "file is not a supported audio file, accepted formats are wav, mp3, flac, ogg, m4a and webm"
+
+func isInvalidAudio(err error) bool {
+	return errors.Is(err, audio.ErrUnsupportedFormat) || errors.Is(err, audio.ErrMalformed)
+}
+
+func audioMetadataParams(metadata *audio.Metadata) database.UpdateFileAudioMetadataParams {
+	return database.UpdateFileAudioMetadataParams{
+		AudioFormat: pgtype.Text{
+			Valid:  true,
+			String: metadata.Format,
+		},
+		Codec: pgtype.Text{
+			Valid:  metadata.Codec != "",
+			String: metadata.Codec,
+		},
+		DurationMs: pgtype.Int8{
+			Valid: metadata.Duration > 0,
+			Int64: metadata.Duration.Milliseconds(),
+		},
+		SampleRate: pgtype.Int4{
+			Valid: metadata.SampleRate > 0,
+			Int32: int32(metadata.SampleRate),
+		},
+		Channels: pgtype.Int4{
+			Valid: metadata.Channels > 0,
+			Int32: int32(metadata.Channels),
+		},
+	}
+}
+
+// probeStoredAudio parses the headers of an object already in storage through range reads.
+func (server *Server) probeStoredAudio(ctx context.Context, objectKey string, size int64) (*audio.Metadata, error) {
+	return audio.Probe(objectstore.NewSeekableReader(ctx, server.objectStore, objectKey, size), size)
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/middleware"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/ratelimit"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxBulkTranscripts bounds the jobs one bulk request creates, larger collections are worked off by repeating it.
+// The transcript rate limit lowers it further, since every requested file takes a token of its own.
+const maxBulkTranscripts = 100
+
+type collectionTranscriptsQuery struct {
+	Recursive          bool `form:"recursive"`
+	IncludeTranscribed bool `form:"include_transcribed"`
+}
+
+type requestedTranscript struct {
+	FileID   int32  `json:"file_id"`
+	FileName string `json:"file_name"`
+	JobID    int32  `json:"job_id"`
+}
+
+type skippedTranscript struct {
+	FileID   int32  `json:"file_id"`
+	FileName string `json:"file_name"`
+	Reason   string `json:"reason"`
+}
+
+type collectionTranscriptsResponse struct {
+	Requested []requestedTranscript `json:"requested"`
+	Skipped   []skippedTranscript   `json:"skipped"`
+	// HasMore tells that files were left for another request, either past the batch size or after the quota ran out
+	HasMore bool `json:"has_more"`
+}
+
+// @Summary Request Collection Transcripts
+// @Description Requests transcripts for every uploaded file in a collection, at most 100 per call. Files with a job in progress are left out,
+// @Description as are files with a finished transcript unless include_transcribed is set, so repeating the call works through larger collections.
+// @Description With include_transcribed a repeated call only skips files whose jobs are still running.
+// @Description Requesting stops at the first file beyond the transcription quota.
+// @Description Every requested file takes a token from the transcript rate limit, a call requests at most as many files as that limit allows per minute.
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param recursive query bool false "Also files of collections nested inside it"
+// @Param include_transcribed query bool false "Transcribe files again that already have a transcript"
+// @Success 200 {object} standardResponse{response=responseData{data=collectionTranscriptsResponse}} "transcripts requested"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Collection not found"
+// @Failure 429 {object} standardResponse "Rate limit exceeded"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/collections/{id}/transcripts [POST]
+func (server *Server) requestCollectionTranscripts(ctx *gin.Context) {
+	collectionID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid collection id", nil)
+		return
+	}
+
+	var query collectionTranscriptsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	userID := int32(payload.UserID)
+
+	collectionIDs, err := server.collectionIDsOf(ctx, userID, int32(collectionID), query.Recursive)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while resolving collection")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while requesting transcripts", nil)
+		return
+	}
+
+	if len(collectionIDs) == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection with provided id", nil)
+		return
+	}
+
+	email, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find user", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while fetching email for the user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching email for the user", nil)
+		return
+	}
+
+	limit := ratelimit.PerMinute(server.config.RateLimitTranscript)
+
+	batchSize := int32(maxBulkTranscripts)
+	if limit.Enabled() {
+		batchSize = min(batchSize, int32(limit.Requests))
+	}
+
+	files, err := server.store.ListCollectionFilesForTranscript(ctx, database.ListCollectionFilesForTranscriptParams{
+		UserID:             userID,
+		CollectionIds:      collectionIDs,
+		UploadStatus:       database.Success,
+		LockStatus:         database.Unlocked,
+		QueuedStatus:       database.JobQueued,
+		ProcessingStatus:   database.JobProcessing,
+		IncludeTranscribed: query.IncludeTranscribed,
+		SucceededStatus:    database.JobSucceeded,
+		// one extra row tells whether another call is needed
+		PageLimit: batchSize + 1,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing collection files for transcript")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while requesting transcripts", nil)
+		return
+	}
+
+	response := collectionTranscriptsResponse{
+		Requested: make([]requestedTranscript, 0, len(files)),
+		Skipped:   make([]skippedTranscript, 0),
+	}
+
+	if len(files) > int(batchSize) {
+		files = files[:batchSize]
+		response.HasMore = true
+	}
+
+	// an empty collection still costs a token, so calling it in a loop stays throttled
+	if !middleware.TakeRateLimit(ctx, server.limiter, "transcript", limit, middleware.ByUser, max(1, len(files))) {
+		return
+	}
+
+	for _, file := range files {
+		job, err := server.enqueueTranscript(ctx, userID, email, file.ID, file.ObjectKey.String)
+		if err == nil {
+			response.Requested = append(response.Requested, requestedTranscript{
+				FileID:   file.ID,
+				FileName: file.FileName,
+				JobID:    job.ID,
+			})
+			continue
+		}
+
+		skipped := skippedTranscript{
+			FileID:   file.ID,
+			FileName: file.FileName,
+		}
+
+		switch {
+		// the file changed between listing and requesting
+		case errors.Is(err, custom_errors.ErrNoRecordFound),
+			errors.Is(err, custom_errors.ErrResourceLocked),
+			errors.Is(err, custom_errors.ErrUploadIssue),
+			errors.Is(err, custom_errors.ErrJobInProgress):
+			skipped.Reason = err.Error()
+		case errors.Is(err, custom_errors.ErrQuotaExceeded):
+			skipped.Reason = err.Error()
+			response.Skipped = append(response.Skipped, skipped)
+			response.HasMore = true
+			server.enhanceHTTPResponse(ctx, http.StatusOK, "transcripts requested until the quota ran out", response)
+			return
+		default:
+			server.baseLogger.Error().Err(err).Int32("file_id", file.ID).Msg("error while requesting transcript for collection file")
+			skipped.Reason = "error requesting transcript, please try later"
+		}
+
+		response.Skipped = append(response.Skipped, skipped)
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "transcripts requested", response)
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type collectionRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// ParentID places the collection inside another one, without it the collection sits at the root
+	ParentID *int32 `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+type collectionDetails struct {
+	ID        int32      `json:"id"`
+	ParentID  *int32     `json:"parent_id,omitempty"`
+	Name      string     `json:"name"`
+	FileCount *int64     `json:"file_count,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type fileCollectionRequest struct {
+	// CollectionID moves the file into the collection, without it the file goes back to the root
+	CollectionID *int32 `json:"collection_id" binding:"omitempty,min=1"`
+}
+
+type fileCollectionResponse struct {
+	ID           int32  `json:"id"`
+	FileName     string `json:"file_name"`
+	CollectionID *int32 `json:"collection_id,omitempty"`
+}
+
+func optionalInt32(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+func newCollectionDetails(collection *database.Collection) collectionDetails {
+	return collectionDetails{
+		ID:        collection.ID,
+		ParentID:  optionalInt32(collection.ParentID),
+		Name:      collection.Name,
+		CreatedAt: optionalTime(collection.CreatedAt),
+		UpdatedAt: optionalTime(collection.UpdatedAt),
+	}
+}
+
+func newCollectionSummaries(collections []database.ListCollectionsRow) []collectionDetails {
+	details := make([]collectionDetails, 0, len(collections))
+	for _, collection := range collections {
+		details = append(details, collectionDetails{
+			ID:        collection.ID,
+			ParentID:  optionalInt32(collection.ParentID),
+			Name:      collection.Name,
+			FileCount: &collection.FileCount,
+			CreatedAt: optionalTime(collection.CreatedAt),
+			UpdatedAt: optionalTime(collection.UpdatedAt),
+		})
+	}
+
+	return details
+}
+
+func (req collectionRequest) parent() pgtype.Int4 {
+	if req.ParentID == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Valid: true, Int32: *req.ParentID}
+}
+
+// collectionErrorResponse maps the errors shared by creating and updating a collection, it reports whether it responded.
+func (server *Server) collectionErrorResponse(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, custom_errors.ErrNoRecordFound):
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection or parent collection with provided id", nil)
+	case errors.Is(err, custom_errors.ErrDuplicateData):
+		server.enhanceHTTPResponse(ctx, http.StatusConflict, "a collection with the same name exists at this place", nil)
+	case errors.Is(err, custom_errors.ErrCollectionCycle):
+		server.enhanceHTTPResponse(ctx, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, custom_errors.ErrCollectionTooDeep):
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "collections can be nested at most "+strconv.Itoa(database.MaxCollectionDepth)+" levels deep", nil)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// @Summary List Collections
+// @Description Lists every collection of the user with its parent and the number of files directly inside it, root collections first
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse{response=responseData{data=[]collectionDetails}} "collections fetched successfully"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/collections [GET]
+func (server *Server) listCollections(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	collections, err := server.store.ListCollections(ctx, int32(payload.UserID))
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing collections")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing collections", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "collections fetched successfully", newCollectionSummaries(collections))
+}
+
+// @Summary Create Collection
+// @Description Creates a collection at the root or inside another collection, names are unique among siblings
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body collectionRequest true "Collection"
+// @Success 201 {object} standardResponse{response=responseData{data=collectionDetails}} "collection created"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Parent collection not found"
+// @Failure 409 {object} standardResponse "Name taken"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/collections [POST]
+func (server *Server) createCollection(ctx *gin.Context) {
+	var req collectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide a collection name", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	collection, err := server.store.CreateCollectionTx(ctx, int32(payload.UserID), req.parent(), req.Name)
+	if err != nil {
+		if server.collectionErrorResponse(ctx, err) {
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while creating collection")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating collection", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "collection created", newCollectionDetails(collection))
+}
+
+// @Summary Update Collection
+// @Description Renames a collection and moves it, along with everything inside it, under parent_id or to the root when parent_id is left out
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Param request body collectionRequest true "Collection"
+// @Success 200 {object} standardResponse{response=responseData{data=collectionDetails}} "collection updated"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Collection not found"
+// @Failure 409 {object} standardResponse "Name taken or move into itself"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/collections/{id} [PUT]
+func (server *Server) updateCollection(ctx *gin.Context) {
+	collectionID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid collection id", nil)
+		return
+	}
+
+	var req collectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide a collection name", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	collection, err := server.store.UpdateCollectionTx(ctx, int32(payload.UserID), int32(collectionID), req.parent(), req.Name)
+	if err != nil {
+		if server.collectionErrorResponse(ctx, err) {
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while updating collection")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while updating collection", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "collection updated", newCollectionDetails(collection))
+}
+
+// @Summary Delete Collection
+// @Description Deletes a collection without sub collections, the files inside it are kept and move back to the root
+// @Tags Collections
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Collection ID"
+// @Success 200 {object} standardResponse "collection deleted"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Collection not found"
+// @Failure 409 {object} standardResponse "Collection has sub collections"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/collections/{id} [DELETE]
+func (server *Server) deleteCollection(ctx *gin.Context) {
+	collectionID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid collection id", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	err = server.store.DeleteCollectionTx(ctx, int32(payload.UserID), int32(collectionID))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection with provided id", nil)
+			return
+		}
+		if errors.Is(err, custom_errors.ErrCollectionNotEmpty) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "collection still has sub collections, delete or move them first", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while deleting collection")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting collection", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "collection deleted", nil)
+}
+
+// @Summary Move File to Collection
+// @Description Puts a file into a collection, leaving out collection_id moves it back to the root
+// @Tags Files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "File ID"
+// @Param request body fileCollectionRequest true "Target collection"
+// @Success 200 {object} standardResponse{response=responseData{data=fileCollectionResponse}} "file moved"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "File or collection not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/{id}/collection [PUT]
+func (server *Server) setFileCollection(ctx *gin.Context) {
+	fileID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid file id", nil)
+		return
+	}
+
+	var req fileCollectionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	userID := int32(payload.UserID)
+
+	var collectionID pgtype.Int4
+	if req.CollectionID != nil {
+		collection, err := server.store.GetCollection(ctx, database.GetCollectionParams{
+			ID:     *req.CollectionID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection with provided id", nil)
+				return
+			}
+			server.baseLogger.Error().Err(err).Msg("error while fetching collection")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while moving file", nil)
+			return
+		}
+
+		collectionID = pgtype.Int4{Valid: true, Int32: collection.ID}
+	}
+
+	file, err := server.store.SetFileCollection(ctx, database.SetFileCollectionParams{
+		ID:           int32(fileID),
+		UserID:       userID,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find file with provided id", nil)
+			return
+		}
+		// the collection was deleted since it was looked up
+		if database.ErrorCode(err) == database.ForeignKeyViolation {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection with provided id", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while moving file to collection")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while moving file", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "file moved", fileCollectionResponse{
+		ID:           file.ID,
+		FileName:     file.FileName,
+		CollectionID: optionalInt32(file.CollectionID),
+	})
+}
+
+// collectionIDsOf resolves the collection to itself or, with its sub collections, to its whole subtree.
+// An empty result means the user has no such collection.
+func (server *Server) collectionIDsOf(ctx *gin.Context, userID, collectionID int32, recursive bool) ([]int32, error) {
+	subtree, err := server.store.ListCollectionSubtree(ctx, database.ListCollectionSubtreeParams{
+		ID:     collectionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !recursive && len(subtree) > 0 {
+		subtree = subtree[:1]
+	}
+
+	ids := make([]int32, 0, len(subtree))
+	for _, collection := range subtree {
+		ids = append(ids, collection.ID)
+	}
+
+	return ids, nil
+}
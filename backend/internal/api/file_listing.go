@@ -0,0 +1,280 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultFilesPageLimit = 50
+
+	sortByCreatedAt = "created_at"
+	sortByFileName  = "file_name"
+	sortBySize      = "size"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type listFilesQuery struct {
+	Cursor        string    `form:"cursor"`
+	Limit         int32     `form:"limit" binding:"omitempty,min=1,max=200"`
+	NamePrefix    string    `form:"name_prefix" binding:"omitempty,max=100"`
+	CreatedAfter  time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	UploadStatus  string    `form:"upload_status" binding:"omitempty,oneof=PENDING SUCCESS FAILED"`
+	HasTranscript *bool     `form:"has_transcript"`
+	CollectionID  int32     `form:"collection_id" binding:"omitempty,min=1"`
+	Recursive     bool      `form:"recursive"`
+	Tags          []string  `form:"tag" binding:"omitempty,max=20"`
+	SortBy        string    `form:"sort_by" binding:"omitempty,oneof=created_at file_name size"`
+	Order         string    `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// filesCursor is the position after the last row of a page, it carries the sort it was issued for so a
+// cursor cannot silently be replayed against a different ordering.
+type filesCursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Value  string `json:"v"`
+	ID     int32  `json:"i"`
+}
+
+type listFilesResponse struct {
+	Files      []database.ListFilesRow `json:"files"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+func encodeFilesCursor(cursor filesCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeFilesCursor(encoded string) (*filesCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var cursor filesCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// escapeLikePattern makes a user supplied prefix match literally, % and _ would otherwise act as wildcards.
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// cursorFor builds the cursor pointing just past row for the given ordering.
+func cursorFor(row database.ListFilesRow, sortBy, order string) filesCursor {
+	cursor := filesCursor{
+		SortBy: sortBy,
+		Order:  order,
+		ID:     row.ID,
+	}
+
+	switch sortBy {
+	case sortByFileName:
+		cursor.Value = row.FileName
+	case sortBySize:
+		cursor.Value = strconv.FormatInt(row.SizeBytes.Int64, 10)
+	default:
+		cursor.Value = row.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
+
+// applyCursor sets the keyset parameters of the query from a decoded cursor.
+func applyCursor(params *database.ListFilesParams, cursor *filesCursor) error {
+	params.CursorID = pgtype.Int4{
+		Valid: true,
+		Int32: cursor.ID,
+	}
+
+	switch cursor.SortBy {
+	case sortByFileName:
+		params.CursorFileName = pgtype.Text{
+			Valid:  true,
+			String: cursor.Value,
+		}
+	case sortBySize:
+		size, err := strconv.ParseInt(cursor.Value, 10, 64)
+		if err != nil {
+			return errInvalidCursor
+		}
+		params.CursorSize = pgtype.Int8{
+			Valid: true,
+			Int64: size,
+		}
+	default:
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return errInvalidCursor
+		}
+		params.CursorCreatedAt = pgtype.Timestamptz{
+			Valid: true,
+			Time:  createdAt,
+		}
+	}
+
+	return nil
+}
+
+// @Summary List Files
+// @Description List files page by page along with size, audio metadata and the status of the latest transcript job.
+// @Description Without upload_status only unlocked, successfully uploaded files are returned.
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param name_prefix query string false "Only files whose name starts with this prefix"
+// @Param created_after query string false "RFC 3339 timestamp, inclusive"
+// @Param created_before query string false "RFC 3339 timestamp, exclusive"
+// @Param upload_status query string false "Filter by upload status" Enums(PENDING, SUCCESS, FAILED)
+// @Param has_transcript query bool false "Only files with or without a succeeded transcript"
+// @Param collection_id query int false "Only files inside this collection"
+// @Param recursive query bool false "With collection_id, also files of collections nested inside it"
+// @Param tag query []string false "Only files carrying every one of these tags, matched regardless of case" collectionFormat(multi)
+// @Param sort_by query string false "Sort field, defaults to created_at" Enums(created_at, file_name, size)
+// @Param order query string false "Sort order, defaults to desc" Enums(asc, desc)
+// @Success 200 {object} standardResponse "files fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Collection not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/list [GET]
+func (server *Server) listAllFiles(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	server.listFilesOfUser(ctx, int32(payload.UserID))
+}
+
+// listFilesOfUser serves a page of the registry of any user, admins reach it for users other than themselves.
+func (server *Server) listFilesOfUser(ctx *gin.Context, userID int32) {
+	var query listFilesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request query")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultFilesPageLimit
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = sortByCreatedAt
+	}
+
+	if query.Order == "" {
+		query.Order = "desc"
+	}
+
+	params := database.ListFilesParams{
+		UserID: userID,
+		UploadStatus: pgtype.Text{
+			Valid:  query.UploadStatus != "",
+			String: query.UploadStatus,
+		},
+		DefaultUploadStatus: database.Success,
+		DefaultLockStatus:   database.Unlocked,
+		NamePrefix: pgtype.Text{
+			Valid:  query.NamePrefix != "",
+			String: escapeLikePattern(query.NamePrefix),
+		},
+		CreatedAfter: pgtype.Timestamptz{
+			Valid: !query.CreatedAfter.IsZero(),
+			Time:  query.CreatedAfter,
+		},
+		CreatedBefore: pgtype.Timestamptz{
+			Valid: !query.CreatedBefore.IsZero(),
+			Time:  query.CreatedBefore,
+		},
+		SucceededStatus: database.JobSucceeded,
+		SortBy:          query.SortBy,
+		SortDesc:        query.Order == "desc",
+		// one extra row tells whether another page follows
+		PageLimit: query.Limit + 1,
+	}
+
+	if query.HasTranscript != nil {
+		params.HasTranscript = pgtype.Bool{
+			Valid: true,
+			Bool:  *query.HasTranscript,
+		}
+	}
+
+	if query.CollectionID != 0 {
+		collectionIDs, err := server.collectionIDsOf(ctx, userID, query.CollectionID, query.Recursive)
+		if err != nil {
+			server.baseLogger.Error().Err(err).Msg("error while resolving collection")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing files", nil)
+			return
+		}
+
+		if len(collectionIDs) == 0 {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find collection with provided id", nil)
+			return
+		}
+
+		params.CollectionIds = collectionIDs
+	}
+
+	if len(query.Tags) != 0 {
+		tags, err := normalizeTagNames(query.Tags)
+		if err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+
+		params.Tags = tags
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeFilesCursor(query.Cursor)
+		if err == nil && (cursor.SortBy != query.SortBy || cursor.Order != query.Order) {
+			err = errInvalidCursor
+		}
+		if err == nil {
+			err = applyCursor(&params, cursor)
+		}
+
+		if err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "invalid cursor, it must come from a page with the same sort_by and order", nil)
+			return
+		}
+	}
+
+	files, err := server.store.ListFiles(ctx, params)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing files")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing files", nil)
+		return
+	}
+
+	response := listFilesResponse{
+		Files: files,
+	}
+
+	if len(files) > int(query.Limit) {
+		response.Files = files[:query.Limit]
+		response.NextCursor = encodeFilesCursor(cursorFor(response.Files[len(response.Files)-1], query.SortBy, query.Order))
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "files fetched successfully", response)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultTrashPageLimit = 50
+
+type listTrashQuery struct {
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+type trashedFileDetails struct {
+	ID         int32      `json:"id"`
+	FileName   string     `json:"file_name"`
+	SizeBytes  *int64     `json:"size_bytes,omitempty"`
+	DurationMs *int64     `json:"duration_ms,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	PurgeAfter *time.Time `json:"purge_after,omitempty"`
+}
+
+type restoredFileResponse struct {
+	ID       int32  `json:"id"`
+	FileName string `json:"file_name"`
+}
+
+func (server *Server) newTrashedFileDetails(id int32, fileName string, sizeBytes, durationMs pgtype.Int8, createdAt, deletedAt pgtype.Timestamptz) trashedFileDetails {
+	details := trashedFileDetails{
+		ID:        id,
+		FileName:  fileName,
+		CreatedAt: optionalTime(createdAt),
+		DeletedAt: optionalTime(deletedAt),
+	}
+
+	if sizeBytes.Valid {
+		details.SizeBytes = &sizeBytes.Int64
+	}
+
+	if durationMs.Valid {
+		details.DurationMs = &durationMs.Int64
+	}
+
+	if details.DeletedAt != nil {
+		purgeAfter := details.DeletedAt.Add(time.Duration(server.config.TrashRetention) * time.Hour)
+		details.PurgeAfter = &purgeAfter
+	}
+
+	return details
+}
+
+// @Summary List Trash
+// @Description Lists deleted files that can still be restored, most recently deleted first, with the time each is purged after
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param offset query int false "Number of files to skip"
+// @Success 200 {object} standardResponse{response=responseData{data=[]trashedFileDetails}} "trash fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/trash [GET]
+func (server *Server) listTrashedFiles(ctx *gin.Context) {
+	var query listTrashQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultTrashPageLimit
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	files, err := server.store.ListTrashedFiles(ctx, database.ListTrashedFilesParams{
+		UserID:     int32(payload.UserID),
+		PageLimit:  query.Limit,
+		PageOffset: query.Offset,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing trashed files")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing trash", nil)
+		return
+	}
+
+	details := make([]trashedFileDetails, 0, len(files))
+	for _, file := range files {
+		details = append(details, server.newTrashedFileDetails(file.ID, file.FileName, file.SizeBytes, file.DurationMs, file.CreatedAt, file.DeletedAt))
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "trash fetched successfully", details)
+}
+
+// @Summary Restore a file
+// @Description Takes a file back out of the trash, a live file with the same name has to be renamed or deleted first
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "File ID"
+// @Success 200 {object} standardResponse{response=responseData{data=restoredFileResponse}} "file restored successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "File not in trash"
+// @Failure 409 {object} standardResponse "File name taken"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/{id}/restore [POST]
+func (server *Server) restoreFile(ctx *gin.Context) {
+	fileID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid file id", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	file, err := server.store.RestoreFileTx(ctx, int32(payload.UserID), int32(fileID))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find file with provided id in trash", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrDuplicateData) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "a file with the same name exists, rename it before restoring", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while restoring file")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while restoring file", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "file restored successfully", restoredFileResponse{
+		ID:       file.ID,
+		FileName: file.FileName,
+	})
+}
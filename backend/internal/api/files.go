@@ -1,15 +1,18 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"path/filepath"
 
+	"github.com/DEVunderdog/transcript-generator-backend/internal/audio"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
 	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,6 +22,13 @@ import (
 
 const maxFileSize = 50 * 1024 * 1024
 
+// deleteObject removes an object nothing references anymore, a failure only leaves an orphan behind so it is logged.
+func (server *Server) deleteObject(ctx *gin.Context, objectKey string) {
+	if err := server.objectStore.Delete(ctx, objectKey); err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+		server.baseLogger.Error().Err(err).Msgf("error deleting unreferenced object %s", objectKey)
+	}
+}
+
 type uploadedFileResponse struct {
 	ID       int32  `json:"id" binding:"true"`
 	FileName string `json:"filenmae" binding:"true"`
@@ -38,7 +48,9 @@ type updateFileRequest struct {
 // @Param file formData file true "File to upload"
 // @Success 200 {object} standardResponse "File uploaded successfully"
 // @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "Plan quota exceeded"
 // @Failure 409 {object} standardResponse "Status Conflict"
+// @Failure 415 {object} standardResponse "Not a supported audio file"
 // @Failure 500 {object} standardResponse "Internal Server Error"
 // @Router /auth/files/upload [POST]
 func (server *Server) uploadFileToBucket(ctx *gin.Context) {
@@ -51,17 +63,12 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 	}
 
 	if file.Size > maxFileSize {
-		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "file size exceeded than 50 mb", nil)
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "file size exceeded than 50 mb, please use resumable uploads for larger files", nil)
 		return
 	}
 
 	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
 
-	extension := filepath.Ext(file.Filename)
-
-	newFileName := uuid.New().String() + extension
-	objectKey := fmt.Sprintf("%d/%s", payload.UserID, newFileName)
-
 	src, err := file.Open()
 	if err != nil {
 		server.baseLogger.Error().Err(err).Msg("error reading uploaded file")
@@ -70,11 +77,45 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 	}
 	defer src.Close()
 
+	metadata, err := audio.Probe(src, file.Size)
+	if err != nil {
+		if isInvalidAudio(err) {
+			server.baseLogger.Error().Err(err).Msg("uploaded file is not valid audio")
+			server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, err.Error())
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while probing uploaded file")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error reading uploaded file", nil)
+		return
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		server.baseLogger.Error().Err(err).Msg("error rewinding uploaded file")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error reading uploaded file", nil)
+		return
+	}
+
+	// the extension comes from the sniffed format, the client supplied name says nothing about the content
+	newFileName := uuid.New().String() + audio.Extension(metadata.Format)
+	objectKey := fmt.Sprintf("%d/%s", payload.UserID, newFileName)
+
+	audioParams := audioMetadataParams(metadata)
+
 	newFile, err := server.store.CreateEmptyFileTx(ctx, database.CreateEmptyFileParams{
 		UserID:       int32(payload.UserID),
 		FileName:     file.Filename,
 		LockStatus:   database.Locked,
 		UploadStatus: database.Pending,
+		AudioFormat:  audioParams.AudioFormat,
+		Codec:        audioParams.Codec,
+		DurationMs:   audioParams.DurationMs,
+		SampleRate:   audioParams.SampleRate,
+		Channels:     audioParams.Channels,
+		SizeBytes: pgtype.Int8{
+			Valid: true,
+			Int64: file.Size,
+		},
 	})
 
 	if err != nil {
@@ -84,23 +125,30 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, custom_errors.ErrQuotaExceeded) {
+			server.baseLogger.Error().Err(err).Msg("storage quota exceeded")
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+
 		server.baseLogger.Error().Err(err).Msg("error while creating empty file in registry")
 		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating file in registry", nil)
 		return
 	}
 
-	object := server.storageClient.StorageClient.Bucket(server.storageClient.BucketName).Object(objectKey)
+	hasher := sha256.New()
 
-	writer := object.NewWriter(ctx)
-	if _, err := io.Copy(writer, src); err != nil {
-		rollbackErr := server.store.DeleteFileTx(ctx, int32(payload.UserID), newFile.ID, newFile.UpdatedAt)
+	writer := server.objectStore.NewWriter(ctx, objectKey)
+	if _, err := io.Copy(io.MultiWriter(writer, hasher), src); err != nil {
+		writer.Abort()
+		_, rollbackErr := server.store.DeleteFileTx(ctx, int32(payload.UserID), newFile.ID, newFile.UpdatedAt)
 		if rollbackErr != nil {
 			server.baseLogger.Error().Err(rollbackErr).Msgf("error while rollbacking file by deleting it because writer got failed: %s", err.Error())
-			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while uploading the file, try later and sync up", nil)
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while uploading the file, please try later", nil)
 			return
 		}
-		server.baseLogger.Error().Err(err).Msg("error while copying source of file to cloud storage bucket writer")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while copying source of file to cloud storage bucket writer", nil)
+		server.baseLogger.Error().Err(err).Msg("error while copying source of file to object store writer")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while copying source of file to object store writer", nil)
 		return
 	}
 
@@ -117,11 +165,11 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 		})
 		if rollbackErr != nil {
 			server.baseLogger.Error().Err(rollbackErr).Msgf("error while rollbacking file by updating its status to failed because writer got error whle closing: %s", err.Error())
-			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while uploading the file, try later and sync up", nil)
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while uploading the file, please try later", nil)
 			return
 		}
-		server.baseLogger.Error().Err(err).Msg("error while closing the cloud storage bucket writer")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while closing cloud storage bucket writer", nil)
+		server.baseLogger.Error().Err(err).Msg("error while closing the object store writer")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while closing object store writer", nil)
 		return
 	}
 
@@ -134,9 +182,23 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 		UpdatedAt:  newFile.UpdatedAt,
 		UserID:     int32(payload.UserID),
 		FileStatus: database.Success,
+		ContentHash: pgtype.Text{
+			Valid:  true,
+			String: hex.EncodeToString(hasher.Sum(nil)),
+		},
+		DuplicatePolicy: server.config.DuplicateUploadPolicy,
 	})
 
 	if err != nil {
+		if errors.Is(err, custom_errors.ErrDuplicateContent) {
+			server.deleteObject(ctx, objectKey)
+			if _, rollbackErr := server.store.DeleteFileTx(ctx, int32(payload.UserID), newFile.ID, newFile.UpdatedAt); rollbackErr != nil {
+				server.baseLogger.Error().Err(rollbackErr).Msg("error while rollbacking duplicate file")
+			}
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file with identical content already exists", nil)
+			return
+		}
+
 		if errors.Is(err, custom_errors.ErrNoRecordFound) {
 			server.baseLogger.Error().Err(err).Msg("cannot find the empty file which was created earlier")
 			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating file in registry, please try later", nil)
@@ -145,48 +207,27 @@ func (server *Server) uploadFileToBucket(ctx *gin.Context) {
 
 		if errors.Is(err, custom_errors.ErrResourceConflict) {
 			server.baseLogger.Error().Err(err).Msg("resource concurrently got tampered")
-			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "resource conflicts, please try again later or maybe sync up", nil)
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "resource conflicts, please try again later", nil)
 			return
 		}
 
 		server.baseLogger.Error().Err(err).Msg("error while updating metadata of empty file")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating file in registry, sync up or try later", nil)
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating file in registry, please try later", nil)
 		return
 
 	}
 
+	// the row now shares the object of an identical earlier upload, so the copy just written is redundant
+	if updatedFile.ObjectKey.String != objectKey {
+		server.deleteObject(ctx, objectKey)
+	}
+
 	server.enhanceHTTPResponse(ctx, http.StatusCreated, "file uploaded successfully", uploadedFileResponse{
 		ID:       updatedFile.ID,
 		FileName: updatedFile.FileName,
 	})
 }
 
-// @Summary List Files
-// @Description List all files
-// @Tags Files
-// @Security ApiKeyAuth
-// @Produce json
-// @Success 200 {object} standardResponse "file fetched successfully"
-// @Failure 500 {object} standardResponse "Internal Server Error"
-// @Router /auth/files/list [GET]
-func (server *Server) listAllFiles(ctx *gin.Context) {
-	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
-
-	files, err := server.store.ListAllFiles(ctx, database.ListAllFilesParams{
-		UserID:       int32(payload.UserID),
-		UploadStatus: database.Success,
-		LockStatus:   database.Unlocked,
-	})
-
-	if err != nil {
-		server.baseLogger.Error().Err(err).Msg("error while listing all files")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing all files", nil)
-		return
-	}
-
-	server.enhanceHTTPResponse(ctx, http.StatusOK, "files fetched successfully", files)
-}
-
 // @Summary Update File
 // @Description Update file name
 // @Tags Files
@@ -234,14 +275,16 @@ func (server *Server) updateFile(ctx *gin.Context) {
 }
 
 // @Summary Delete a file
-// @Description Deletes a file from storage
+// @Description Moves a file to the trash, where it can be restored until the trash retention has passed and it is purged from storage.
+// @Description A trashed file keeps counting against the plan until it is purged.
 // @Tags Files
 // @Security ApiKeyAuth
 // @Produce json
 // @Param filename path string true "Filename to delete"
-// @Success 200 {object} standardResponse "File deleted successfully"
+// @Success 200 {object} standardResponse{response=responseData{data=trashedFileDetails}} "File moved to trash"
 // @Failure 400 {object} standardResponse "Bad Request"
 // @Failure 404 {object} standardResponse "File not found"
+// @Failure 409 {object} standardResponse "Transcript job in progress"
 // @Failure 500 {object} standardResponse "Internal Server Error"
 // @Router /auth/files/delete/{filename} [DELETE]
 func (server *Server) deleteFile(ctx *gin.Context) {
@@ -253,7 +296,7 @@ func (server *Server) deleteFile(ctx *gin.Context) {
 
 	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
 
-	lockFile, err := server.store.LockFileTx(ctx, int32(payload.UserID), fileName)
+	file, err := server.store.TrashFileTx(ctx, int32(payload.UserID), fileName)
 
 	if err != nil {
 		if errors.Is(err, custom_errors.ErrNoRecordFound) {
@@ -268,43 +311,15 @@ func (server *Server) deleteFile(ctx *gin.Context) {
 			return
 		}
 
-		server.baseLogger.Error().Err(err).Msg("error while locking the file")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting the file, please sync up or try later", nil)
-		return
-	}
-
-	object := server.storageClient.StorageClient.Bucket(server.storageClient.BucketName).Object(lockFile.ObjectKey.String)
-	if err := object.Delete(ctx); err != nil {
-		_, rollbackErr := server.store.UnlockAndLockFile(ctx, database.UnlockAndLockFileParams{
-			ID:         lockFile.ID,
-			UserID:     int32(payload.UserID),
-			LockStatus: database.Unlocked,
-			Status:     database.Success,
-		})
-
-		if rollbackErr != nil {
-			server.baseLogger.Error().Err(rollbackErr).Msgf("error while rollbacking by unlocking the file due failed deletion in cloud storage bucked: %s", err.Error())
-			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting file, please try later", nil)
-			return
-		}
-
-		server.baseLogger.Error().Err(err).Msg("error while deleting object from bucket")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting file, please try again", nil)
-		return
-	}
-
-	err = server.store.DeleteFileTx(ctx, int32(payload.UserID), lockFile.ID, lockFile.UpdatedAt)
-	if err != nil {
-		if errors.Is(err, custom_errors.ErrResourceConflict) {
-			server.baseLogger.Error().Err(err).Msg("conflicting resource")
-			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "conflicting resource, please try later or sync up", nil)
+		if errors.Is(err, custom_errors.ErrJobInProgress) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "a transcript job for this file is in progress, try again once it finished", nil)
 			return
 		}
 
-		server.baseLogger.Error().Err(err).Msg("error while deleting files")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting file please try later", nil)
+		server.baseLogger.Error().Err(err).Msg("error while moving the file to trash")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting the file, please try later", nil)
 		return
 	}
 
-	server.enhanceHTTPResponse(ctx, http.StatusOK, "file deleted successfully", nil)
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "file moved to trash", server.newTrashedFileDetails(file.ID, file.FileName, file.SizeBytes, file.DurationMs, file.CreatedAt, file.DeletedAt))
 }
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	workerStatusStarted   = "started"
+	workerStatusProgress  = "progress"
+	workerStatusCompleted = "completed"
+	workerStatusFailed    = "failed"
+)
+
+type transcriptSegmentRequest struct {
+	Start   float64 `json:"start" binding:"min=0"`
+	End     float64 `json:"end" binding:"gtefield=Start"`
+	Speaker string  `json:"speaker" binding:"max=100"`
+	Text    string  `json:"text"`
+}
+
+type jobStatusUpdateRequest struct {
+	Status              string                     `json:"status" binding:"required,oneof=started progress completed failed"`
+	Progress            int32                      `json:"progress" binding:"min=0,max=100"`
+	TranscriptObjectKey string                     `json:"transcript_object_key"`
+	Reason              string                     `json:"reason"`
+	Segments            []transcriptSegmentRequest `json:"segments" binding:"dive"`
+}
+
+type jobStatusUpdateResponse struct {
+	JobID    int32  `json:"job_id"`
+	Status   string `json:"status"`
+	Progress int32  `json:"progress"`
+}
+
+// @Summary Update Transcript Job Status
+// @Description Callback used by the transcription service to report progress and results of a job, completed updates carry the transcript segments. Requests must be signed with the shared worker secret
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path int true "Job ID"
+// @Param X-Worker-Timestamp header string true "Unix timestamp the request was signed at"
+// @Param X-Worker-Signature header string true "Hex encoded HMAC-SHA256 of timestamp.method.path.body"
+// @Param request body jobStatusUpdateRequest true "Status Update"
+// @Success 200 {object} jobStatusUpdateResponse "job status updated"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 401 {object} standardResponse "Unauthorized"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 409 {object} standardResponse "Status Conflict"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /internal/jobs/{id}/status [POST]
+func (server *Server) updateJobStatus(ctx *gin.Context) {
+	jobID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid job id", nil)
+		return
+	}
+
+	var req jobStatusUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request body")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	params := database.UpdateTranscriptJobStatusTxParams{
+		ID: int32(jobID),
+	}
+
+	switch req.Status {
+	case workerStatusStarted:
+		params.Status = database.JobProcessing
+		params.Progress = pgtype.Int4{Valid: true, Int32: 0}
+	case workerStatusProgress:
+		params.Status = database.JobProcessing
+		params.Progress = pgtype.Int4{Valid: true, Int32: req.Progress}
+	case workerStatusCompleted:
+		if req.TranscriptObjectKey == "" {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide transcript object key for completed job", nil)
+			return
+		}
+		params.Status = database.JobSucceeded
+		params.TranscriptObjectKey = pgtype.Text{Valid: true, String: req.TranscriptObjectKey}
+		params.Segments = make([]database.CreateTranscriptSegmentsParams, 0, len(req.Segments))
+		for i, segment := range req.Segments {
+			params.Segments = append(params.Segments, database.CreateTranscriptSegmentsParams{
+				SegmentIndex: int32(i),
+				StartMs:      int64(math.Round(segment.Start * 1000)),
+				EndMs:        int64(math.Round(segment.End * 1000)),
+				Speaker: pgtype.Text{
+					Valid:  segment.Speaker != "",
+					String: segment.Speaker,
+				},
+				Content: segment.Text,
+			})
+		}
+	case workerStatusFailed:
+		if req.Reason == "" {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide reason for failed job", nil)
+			return
+		}
+		params.Status = database.JobFailed
+		params.ErrorMessage = pgtype.Text{Valid: true, String: req.Reason}
+	}
+
+	job, err := server.store.UpdateTranscriptJobStatusTx(ctx, params)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.baseLogger.Error().Err(err).Msg("cannot find transcript job")
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find transcript job", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrInvalidJobState) {
+			server.baseLogger.Error().Err(err).Msgf("worker reported %s for job %d", req.Status, jobID)
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "job cannot move to the reported status", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while updating transcript job status")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while updating transcript job status", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "job status updated", jobStatusUpdateResponse{
+		JobID:    job.ID,
+		Status:   job.Status,
+		Progress: job.Progress,
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultObjectGCPageLimit = 50
+
+type listObjectGCQuery struct {
+	UserID int32 `form:"user_id" binding:"omitempty,min=1"`
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset int32 `form:"offset" binding:"omitempty,min=0"`
+}
+
+type quarantinedObjectDetails struct {
+	ID            int32     `json:"id"`
+	UserID        int32     `json:"user_id"`
+	OriginalKey   string    `json:"original_key"`
+	QuarantineKey string    `json:"quarantine_key"`
+	SizeBytes     int64     `json:"size_bytes"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	PurgeAfter    time.Time `json:"purge_after"`
+}
+
+type gcAuditEntryDetails struct {
+	ID            int64     `json:"id"`
+	RunID         *int32    `json:"run_id,omitempty"`
+	Action        string    `json:"action"`
+	UserID        int32     `json:"user_id"`
+	ObjectKey     string    `json:"object_key"`
+	QuarantineKey string    `json:"quarantine_key,omitempty"`
+	SizeBytes     *int64    `json:"size_bytes,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (query listObjectGCQuery) userFilter() pgtype.Int4 {
+	return pgtype.Int4{
+		Valid: query.UserID != 0,
+		Int32: query.UserID,
+	}
+}
+
+// @Summary List Quarantined Objects
+// @Description Lists orphaned objects the garbage collector moved to quarantine and has not purged yet, newest first
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id query int false "Only objects of this user"
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param offset query int false "Number of objects to skip"
+// @Success 200 {object} standardResponse{response=responseData{data=[]quarantinedObjectDetails}} "quarantined objects fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/gc/quarantine [GET]
+func (server *Server) listQuarantinedObjects(ctx *gin.Context) {
+	var query listObjectGCQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultObjectGCPageLimit
+	}
+
+	objects, err := server.store.ListQuarantinedObjects(ctx, database.ListQuarantinedObjectsParams{
+		UserID:     query.userFilter(),
+		PageLimit:  query.Limit,
+		PageOffset: query.Offset,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing quarantined objects")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing quarantined objects", nil)
+		return
+	}
+
+	details := make([]quarantinedObjectDetails, 0, len(objects))
+	for _, object := range objects {
+		details = append(details, quarantinedObjectDetails{
+			ID:            object.ID,
+			UserID:        object.UserID,
+			OriginalKey:   object.OriginalKey,
+			QuarantineKey: object.QuarantineKey,
+			SizeBytes:     object.SizeBytes,
+			QuarantinedAt: object.QuarantinedAt,
+			PurgeAfter:    object.PurgeAfter,
+		})
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "quarantined objects fetched successfully", details)
+}
+
+// @Summary List Garbage Collection Audit
+// @Description Lists every quarantine and purge of an orphaned object, newest first
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id query int false "Only entries of this user"
+// @Param limit query int false "Page size, defaults to 50 and at most 200"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} standardResponse{response=responseData{data=[]gcAuditEntryDetails}} "audit entries fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/gc/audit [GET]
+func (server *Server) listGCAuditEntries(ctx *gin.Context) {
+	var query listObjectGCQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultObjectGCPageLimit
+	}
+
+	entries, err := server.store.ListGCAuditEntries(ctx, database.ListGCAuditEntriesParams{
+		UserID:     query.userFilter(),
+		PageLimit:  query.Limit,
+		PageOffset: query.Offset,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing gc audit entries")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing audit entries", nil)
+		return
+	}
+
+	details := make([]gcAuditEntryDetails, 0, len(entries))
+	for _, entry := range entries {
+		detail := gcAuditEntryDetails{
+			ID:            entry.ID,
+			Action:        entry.Action,
+			UserID:        entry.UserID,
+			ObjectKey:     entry.ObjectKey,
+			QuarantineKey: entry.QuarantineKey.String,
+			CreatedAt:     entry.CreatedAt,
+		}
+
+		if entry.RunID.Valid {
+			detail.RunID = &entry.RunID.Int32
+		}
+
+		if entry.SizeBytes.Valid {
+			detail.SizeBytes = &entry.SizeBytes.Int64
+		}
+
+		details = append(details, detail)
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "audit entries fetched successfully", details)
+}
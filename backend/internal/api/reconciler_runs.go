@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultReconcilerRunsLimit = 20
+
+type listReconcilerRunsQuery struct {
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type reconcilerRunDetails struct {
+	ID                 int32      `json:"id"`
+	DryRun             bool       `json:"dry_run"`
+	FilesScanned       int32      `json:"files_scanned"`
+	FilesRepaired      int32      `json:"files_repaired"`
+	FilesDeleted       int32      `json:"files_deleted"`
+	OrphansFound       int32      `json:"orphans_found"`
+	ObjectsQuarantined int32      `json:"objects_quarantined"`
+	ObjectsPurged      int32      `json:"objects_purged"`
+	Errors             int32      `json:"errors"`
+	StartedAt          time.Time  `json:"started_at"`
+	FinishedAt         *time.Time `json:"finished_at,omitempty"`
+}
+
+// @Summary List Reconciler Runs
+// @Description Lists the latest passes of the background reconciler with what each repaired, deleted, found orphaned, quarantined or purged, a run without finished_at is in progress or was interrupted
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Number of runs, defaults to 20 and at most 100"
+// @Success 200 {object} standardResponse{response=responseData{data=[]reconcilerRunDetails}} "reconciler runs fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "admin role required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/reconciler/runs [GET]
+func (server *Server) listReconcilerRuns(ctx *gin.Context) {
+	var query listReconcilerRunsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultReconcilerRunsLimit
+	}
+
+	runs, err := server.store.ListReconcilerRuns(ctx, query.Limit)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing reconciler runs")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing reconciler runs", nil)
+		return
+	}
+
+	details := make([]reconcilerRunDetails, 0, len(runs))
+	for _, run := range runs {
+		details = append(details, reconcilerRunDetails{
+			ID:                 run.ID,
+			DryRun:             run.DryRun,
+			FilesScanned:       run.FilesScanned,
+			FilesRepaired:      run.FilesRepaired,
+			FilesDeleted:       run.FilesDeleted,
+			OrphansFound:       run.OrphansFound,
+			ObjectsQuarantined: run.ObjectsQuarantined,
+			ObjectsPurged:      run.ObjectsPurged,
+			Errors:             run.Errors,
+			StartedAt:          run.StartedAt,
+			FinishedAt:         optionalTime(run.FinishedAt),
+		})
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "reconciler runs fetched successfully", details)
+}
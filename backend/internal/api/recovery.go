@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type recoverRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+type confirmRecoveryRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Code  string `json:"code" binding:"required"`
+}
+
+type recoveryResponse struct {
+	APIKey  string `json:"api_key"`
+	Revoked int64  `json:"revoked_api_keys"`
+}
+
+// @Summary Recover API Key
+// @Description Mails a one time recovery code to a registered address, the response is the same whether the address is registered or not
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body recoverRequest true "User Email"
+// @Success 202 {object} standardResponse "recovery code sent if the address is registered"
+// @Failure 400 {object} standardResponse "invalid request"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /api/recover [POST]
+func (server *Server) recoverAPIKey(ctx *gin.Context) {
+	var req recoverRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+
+	_, err := server.store.GetUsersID(ctx, email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		server.baseLogger.Error().Err(err).Msg("error while fetching user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching user", nil)
+		return
+	}
+
+	if err == nil {
+		err = server.sendVerificationCode(ctx, email, database.VerificationRecovery,
+			"Recover your api key",
+			"Use the code below to recover access. Confirming it revokes every api key of your account and issues a new one.")
+		if err != nil {
+			server.baseLogger.Error().Err(err).Msg("error while sending recovery code")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while sending recovery code", nil)
+			return
+		}
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusAccepted, "recovery code sent if the address is registered", nil)
+}
+
+// @Summary Confirm API Key Recovery
+// @Description Confirms the emailed recovery code, revokes every api key of the user and issues a new one
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body confirmRecoveryRequest true "User Email and recovery code"
+// @Success 201 {object} standardResponse{response=responseData{data=recoveryResponse}} "api key recovered"
+// @Failure 400 {object} standardResponse "invalid, expired or already used code"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /api/recover/confirm [POST]
+func (server *Server) confirmRecovery(ctx *gin.Context) {
+	var req confirmRecoveryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+
+	verification, err := server.checkVerificationCode(ctx, email, database.VerificationRecovery, strings.TrimSpace(req.Code))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrVerificationInvalid) {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "invalid, expired or already used recovery code", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while checking recovery code")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while checking recovery code", nil)
+		return
+	}
+
+	apiKey, signingKeyID, err := server.newSignedAPIKey(ctx)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error creating api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error creating api keys", nil)
+		return
+	}
+
+	result, err := server.store.RecoverAPIKeyTx(ctx, database.RecoverAPIKeyTxParams{
+		VerificationID: verification.ID,
+		Email:          email,
+		APIKey:         defaultAPIKeyParams(apiKey, signingKeyID),
+	})
+	if err != nil {
+		// a user removed after the code was mailed leaves nothing to recover
+		if errors.Is(err, custom_errors.ErrVerificationInvalid) || errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "invalid, expired or already used recovery code", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while recovering api key")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while recovering api key", nil)
+		return
+	}
+
+	server.keyCache.InvalidateUser(result.UserID)
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "api key recovered", recoveryResponse{
+		APIKey:  apiKey.Key,
+		Revoked: result.Revoked,
+	})
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/mailer"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type registerRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+type confirmRegistrationRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// defaultAPIKeyParams describes the key an account gets when it is created or recovered, UserID is left to the caller.
+func defaultAPIKeyParams(apiKey *token.GeneratedAPIKey, signingKeyID int32) database.CreateAPIKeyParams {
+	return database.CreateAPIKeyParams{
+		KeyID:        pgtype.Text{String: apiKey.KeyID, Valid: true},
+		SecretHash:   apiKey.SecretHash,
+		Signature:    apiKey.Signature,
+		SigningKeyID: signingKeyID,
+		Name:         defaultAPIKeyName,
+		Scopes:       token.DefaultScopes,
+	}
+}
+
+// sendVerificationCode replaces any pending code of the address for the purpose and mails a fresh one.
+func (server *Server) sendVerificationCode(ctx *gin.Context, email, purpose, subject, intro string) error {
+	code, err := token.GenerateVerificationCode()
+	if err != nil {
+		return err
+	}
+
+	err = server.store.DeletePendingEmailVerifications(ctx, database.DeletePendingEmailVerificationsParams{
+		Email:   email,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return err
+	}
+
+	expiry := time.Duration(server.config.VerificationExpiry) * time.Minute
+
+	_, err = server.store.CreateEmailVerification(ctx, database.CreateEmailVerificationParams{
+		Email:     email,
+		Purpose:   purpose,
+		CodeHash:  token.HashVerificationCode(email, purpose, code),
+		ExpiresAt: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return err
+	}
+
+	return server.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: subject,
+		Body:    fmt.Sprintf("%s\n\nYour code is %s, it expires in %d minutes.\n\nIf you did not ask for it you can ignore this email.\n", intro, code, server.config.VerificationExpiry),
+	})
+}
+
+// checkVerificationCode returns the pending verification when the code matches, a wrong code burns one attempt.
+func (server *Server) checkVerificationCode(ctx *gin.Context, email, purpose, code string) (*database.EmailVerification, error) {
+	verification, err := server.store.GetPendingEmailVerification(ctx, database.GetPendingEmailVerificationParams{
+		Email:   email,
+		Purpose: purpose,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, custom_errors.ErrVerificationInvalid
+		}
+		return nil, err
+	}
+
+	if verification.Attempts >= database.MaxVerificationAttempts {
+		return nil, custom_errors.ErrVerificationInvalid
+	}
+
+	if !token.VerifyVerificationCode(email, purpose, code, verification.CodeHash) {
+		if _, err := server.store.IncrementEmailVerificationAttempts(ctx, verification.ID); err != nil {
+			return nil, err
+		}
+		return nil, custom_errors.ErrVerificationInvalid
+	}
+
+	return &verification, nil
+}
+
+// @Summary Register
+// @Description Starts registration by mailing a one time code to the address, the api key is issued once the code is confirmed
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body registerRequest true "User Email"
+// @Success 202 {object} standardResponse "verification code sent"
+// @Failure 400 {object} standardResponse "invalid request"
+// @Failure 403 {object} standardResponse "user already present"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /api/register [POST]
+func (server *Server) registerUser(ctx *gin.Context) {
+	var req registerRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+
+	_, err := server.store.GetUsersID(ctx, email)
+	if err == nil {
+		server.enhanceHTTPResponse(ctx, http.StatusForbidden, "user already present", nil)
+		return
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		server.baseLogger.Error().Err(err).Msg("error while fetching user")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching user", nil)
+		return
+	}
+
+	err = server.sendVerificationCode(ctx, email, database.VerificationRegister,
+		"Confirm your email address",
+		"Use the code below to confirm your email address and receive your api key.")
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while sending verification code")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while sending verification code", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusAccepted, "verification code sent", nil)
+}
+
+// @Summary Confirm Registration
+// @Description Confirms the emailed code, creates the user and generates its API Key
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body confirmRegistrationRequest true "User Email and verification code"
+// @Success 201 {object} apiKeyResponse "api keys created"
+// @Failure 400 {object} standardResponse "invalid, expired or already used code"
+// @Failure 403 {object} standardResponse "user already present"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /api/register/confirm [POST]
+func (server *Server) confirmRegistration(ctx *gin.Context) {
+	var req confirmRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+		return
+	}
+
+	email := strings.TrimSpace(req.Email)
+
+	verification, err := server.checkVerificationCode(ctx, email, database.VerificationRegister, strings.TrimSpace(req.Code))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrVerificationInvalid) {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "invalid, expired or already used verification code", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while checking verification code")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while checking verification code", nil)
+		return
+	}
+
+	apiKey, signingKeyID, err := server.newSignedAPIKey(ctx)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error creating api keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error creating api keys", nil)
+		return
+	}
+
+	_, err = server.store.ConfirmRegistrationTx(ctx, database.ConfirmRegistrationTxParams{
+		VerificationID: verification.ID,
+		Email:          email,
+		APIKey:         defaultAPIKeyParams(apiKey, signingKeyID),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, custom_errors.ErrVerificationInvalid):
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "invalid, expired or already used verification code", nil)
+		case errors.Is(err, custom_errors.ErrDuplicateData):
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, "user already present", nil)
+		default:
+			server.baseLogger.Error().Err(err).Msg("error while creating user in database")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating user in database", nil)
+		}
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "api keys created", apiKeyResponse{
+		APIKey: apiKey.Key,
+	})
+}
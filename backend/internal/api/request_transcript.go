@@ -2,19 +2,30 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var errPublishTranscript = errors.New("error publishing transcript request")
+
 type transcriptRequestQuery struct {
 	Filename string `form:"filename" binding:"required"`
 }
 
+type transcriptRequestResponse struct {
+	JobID  int32  `json:"job_id"`
+	Status string `json:"status"`
+}
+
 // @Summary Request Transcript
 // @Description Request a transcript for a specific uploaded audio file by providing file name.
 // @Tags Transcript
@@ -22,9 +33,11 @@ type transcriptRequestQuery struct {
 // @Accept json
 // @Produce json
 // @Param filename query string true "Filename of the uploaded file"
-// @Success 200 {object} standardResponse "Transcript requested successfully"
+// @Success 200 {object} transcriptRequestResponse "Transcript requested successfully"
 // @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "Plan quota exceeded"
 // @Failure 404 {object} standardResponse "Not Found"
+// @Failure 409 {object} standardResponse "Status Conflict"
 // @Failure 500 {object} standardResponse "Internal Server Error"
 // @Router /auth/transcript/request [GET]
 func (server *Server) requestTranscript(ctx *gin.Context) {
@@ -67,13 +80,79 @@ func (server *Server) requestTranscript(ctx *gin.Context) {
 		return
 	}
 
-	err = server.pubsubClient.PublishMessage(ctx, email, file.ObjectKey.String, payload.UserID)
+	job, err := server.enqueueTranscript(ctx, int32(payload.UserID), email, file.ID, file.ObjectKey.String)
 	if err != nil {
-		server.baseLogger.Error().Err(err).Msg("error publishing message to topic")
-		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error requesting for transcript to service", nil)
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.baseLogger.Error().Err(err).Msg("cannot find record")
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find the file with provided name", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrResourceLocked) || errors.Is(err, custom_errors.ErrUploadIssue) {
+			server.baseLogger.Error().Err(err).Msg("file is not ready for transcript")
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file is locked or not uploaded successfully, please try later", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrJobInProgress) {
+			server.baseLogger.Error().Err(err).Msg("transcript job already active for file")
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "transcript already requested for this file and is in progress", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrQuotaExceeded) {
+			server.baseLogger.Error().Err(err).Msg("transcription quota exceeded")
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+
+		if errors.Is(err, errPublishTranscript) {
+			server.baseLogger.Error().Err(err).Msg("error publishing message to topic")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error requesting for transcript to service", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while creating transcript job")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating transcript job", nil)
 		return
 	}
 
-	server.enhanceHTTPResponse(ctx, http.StatusOK, "transcript requested successfully", nil)
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "transcript requested successfully", transcriptRequestResponse{
+		JobID:  job.ID,
+		Status: job.Status,
+	})
+
+}
+
+// enqueueTranscript creates a job for the file and publishes it to the transcription service. A job that cannot be
+// published is marked failed right away and errPublishTranscript is returned.
+func (server *Server) enqueueTranscript(ctx *gin.Context, userID int32, email string, fileID int32, objectKey string) (*database.TranscriptJob, error) {
+	job, err := server.store.CreateTranscriptJobTx(ctx, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.publisher.Publish(ctx, &pb.TopicMessage{
+		ObjectKey: objectKey,
+		UserId:    int64(userID),
+		UserEmail: email,
+		JobId:     int64(job.ID),
+	})
+	if err != nil {
+		_, rollbackErr := server.store.UpdateTranscriptJobStatusTx(ctx, database.UpdateTranscriptJobStatusTxParams{
+			ID:     job.ID,
+			Status: database.JobFailed,
+			ErrorMessage: pgtype.Text{
+				Valid:  true,
+				String: "error publishing transcript request to queue",
+			},
+		})
+		if rollbackErr != nil {
+			server.baseLogger.Error().Err(rollbackErr).Msgf("error while marking transcript job as failed because publishing failed: %s", err.Error())
+		}
+
+		return nil, fmt.Errorf("%w: %w", errPublishTranscript, err)
+	}
 
+	return job, nil
 }
@@ -0,0 +1,571 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/audio"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// the endpoints below follow the core tus 1.0.0 protocol with the creation and termination extensions,
+// so any tus client can resume an interrupted upload from the offset reported by HEAD.
+const (
+	tusVersion        = "1.0.0"
+	tusExtensions     = "creation,termination"
+	tusContentType    = "application/offset+octet-stream"
+	uploadPartsSuffix = ".parts/"
+	uploadsPath       = "/server/auth/files/uploads/"
+)
+
+type uploadSessionResponse struct {
+	UploadID     string    `json:"upload_id"`
+	FileID       int32     `json:"file_id"`
+	UploadOffset int64     `json:"upload_offset"`
+	UploadLength int64     `json:"upload_length"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func setTusHeaders(ctx *gin.Context) {
+	ctx.Header("Tus-Resumable", tusVersion)
+	ctx.Header("Tus-Version", tusVersion)
+	ctx.Header("Tus-Extension", tusExtensions)
+}
+
+// parseUploadMetadata decodes the tus Upload-Metadata header, "key base64value" pairs separated by commas.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata value for %s: %w", key, err)
+		}
+
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
+
+// uploadPartKey names the part of a chunk by its zero padded offset, the random suffix keeps requests racing
+// at the same offset from overwriting each other's part.
+func uploadPartKey(objectKey string, offset int64) string {
+	return fmt.Sprintf("%s%s%020d-%s", objectKey, uploadPartsSuffix, offset, uuid.New().String())
+}
+
+// partOffset reads back the offset an upload part was written at.
+func partOffset(objectKey, partKey string) (int64, error) {
+	name, ok := strings.CutPrefix(partKey, objectKey+uploadPartsSuffix)
+	if !ok {
+		return 0, fmt.Errorf("upload part %s does not belong to %s", partKey, objectKey)
+	}
+
+	offset, _, _ := strings.Cut(name, "-")
+	return strconv.ParseInt(offset, 10, 64)
+}
+
+func (server *Server) deleteUploadParts(ctx *gin.Context, objectKey string) {
+	parts, err := server.objectStore.List(ctx, objectKey+uploadPartsSuffix)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msgf("error listing upload parts of %s", objectKey)
+		return
+	}
+
+	for _, part := range parts {
+		if err := server.objectStore.Delete(ctx, part.Key); err != nil {
+			server.baseLogger.Error().Err(err).Msgf("error deleting upload part %s", part.Key)
+		}
+	}
+}
+
+// deleteUploadObjects removes the parts, the verified copy and, when it was already written, the object of a session.
+func (server *Server) deleteUploadObjects(ctx *gin.Context, objectKey string) {
+	objects, err := server.objectStore.List(ctx, objectKey+".")
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msgf("error listing objects of aborted upload %s", objectKey)
+	}
+
+	for _, object := range objects {
+		if err := server.objectStore.Delete(ctx, object.Key); err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+			server.baseLogger.Error().Err(err).Msgf("error deleting object of aborted upload %s", object.Key)
+		}
+	}
+
+	if err := server.objectStore.Delete(ctx, objectKey); err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+		server.baseLogger.Error().Err(err).Msgf("error deleting object of aborted upload %s", objectKey)
+	}
+}
+
+// discardUploadSession aborts a session whose content was rejected, failures are only logged since the
+// client is already being told the upload failed.
+func (server *Server) discardUploadSession(ctx *gin.Context, userID int32, id string) {
+	session, err := server.store.AbortUploadSessionTx(ctx, userID, id)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msgf("error while discarding upload session %s", id)
+		return
+	}
+
+	server.deleteUploadObjects(ctx, session.ObjectKey)
+}
+
+// sniffUploadParts tells the audio format from the leading bytes of the parts, which can be shorter than the
+// sniffed header when the client sent tiny chunks.
+func (server *Server) sniffUploadParts(ctx *gin.Context, partKeys []string) (string, error) {
+	header := make([]byte, 0, audio.SniffLen)
+
+	for _, partKey := range partKeys {
+		if len(header) == audio.SniffLen {
+			break
+		}
+
+		reader, err := server.objectStore.NewRangeReader(ctx, partKey, 0, int64(audio.SniffLen-len(header)))
+		if err != nil {
+			return "", fmt.Errorf("error opening upload part %s: %w", partKey, err)
+		}
+
+		read, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return "", fmt.Errorf("error reading upload part %s: %w", partKey, err)
+		}
+
+		header = append(header, read...)
+	}
+
+	return audio.Sniff(header)
+}
+
+// assembleUpload concatenates the parts recorded on the session, in the order their offsets advanced, into the
+// final object named after the sniffed format and returns its key and the hex sha256 of the assembled content.
+// Parts left behind by rejected requests are never read, and the assembly fails unless every part starts where
+// the previous one ended and the total matches.
+func (server *Server) assembleUpload(ctx *gin.Context, session database.UploadSession) (string, string, error) {
+	format, err := server.sniffUploadParts(ctx, session.PartKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	objectKey := session.ObjectKey + audio.Extension(format)
+
+	hasher := sha256.New()
+	writer := server.objectStore.NewWriter(ctx, objectKey)
+
+	var assembled int64
+	for _, partKey := range session.PartKeys {
+		offset, err := partOffset(session.ObjectKey, partKey)
+		if err != nil || offset != assembled {
+			writer.Abort()
+			return "", "", fmt.Errorf("upload part %s does not continue at offset %d", partKey, assembled)
+		}
+
+		reader, err := server.objectStore.NewReader(ctx, partKey)
+		if err != nil {
+			writer.Abort()
+			return "", "", fmt.Errorf("error opening upload part %s: %w", partKey, err)
+		}
+
+		written, err := io.Copy(io.MultiWriter(writer, hasher), reader)
+		reader.Close()
+		if err != nil {
+			writer.Abort()
+			return "", "", fmt.Errorf("error copying upload part %s: %w", partKey, err)
+		}
+
+		assembled += written
+	}
+
+	if assembled != session.TotalSize {
+		writer.Abort()
+		return "", "", fmt.Errorf("assembled %d bytes of an upload of %d bytes", assembled, session.TotalSize)
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", "", fmt.Errorf("error closing assembled object: %w", err)
+	}
+
+	return objectKey, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// @Summary Create Resumable Upload
+// @Description Open a tus resumable upload session, the file is registered as pending until every byte is received
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param Tus-Resumable header string true "tus protocol version, 1.0.0"
+// @Param Upload-Length header int true "Total size of the file in bytes"
+// @Param Upload-Metadata header string true "tus metadata, must contain filename"
+// @Success 201 {object} uploadSessionResponse "upload session created"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "Plan quota exceeded"
+// @Failure 409 {object} standardResponse "Status Conflict"
+// @Failure 413 {object} standardResponse "Request Entity Too Large"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/uploads [POST]
+func (server *Server) createResumableUpload(ctx *gin.Context) {
+	setTusHeaders(ctx)
+
+	uploadLength, err := strconv.ParseInt(ctx.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || uploadLength <= 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid Upload-Length header", nil)
+		return
+	}
+
+	if uploadLength > server.config.MaxUploadSize {
+		ctx.Header("Tus-Max-Size", strconv.FormatInt(server.config.MaxUploadSize, 10))
+		server.enhanceHTTPResponse(ctx, http.StatusRequestEntityTooLarge, "file size exceeded the allowed upload size", nil)
+		return
+	}
+
+	metadata, err := parseUploadMetadata(ctx.GetHeader("Upload-Metadata"))
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid Upload-Metadata header", err.Error())
+		return
+	}
+
+	fileName := metadata["filename"]
+	if fileName == "" || len(fileName) > 100 {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide filename of at most 100 characters in Upload-Metadata", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	// nothing is written to the key itself, the parts go under it and the assembled object is named after its format
+	objectKey := fmt.Sprintf("%d/%s", payload.UserID, uuid.New().String())
+
+	session, err := server.store.CreateUploadSessionTx(ctx, database.CreateUploadSessionTxParams{
+		ID:           uuid.New().String(),
+		UserID:       int32(payload.UserID),
+		FileName:     fileName,
+		ObjectKey:    objectKey,
+		TotalSize:    uploadLength,
+		ExpiresAt:    time.Now().Add(time.Duration(server.config.UploadExpiry) * time.Hour),
+		UploadMethod: database.UploadResumable,
+	})
+
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrDuplicateData) {
+			server.baseLogger.Error().Err(err).Msg("file with that name already exists")
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file with that name already exits", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrQuotaExceeded) {
+			server.baseLogger.Error().Err(err).Msg("storage quota exceeded")
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while creating upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating upload session", nil)
+		return
+	}
+
+	ctx.Header("Location", uploadsPath+session.ID)
+	ctx.Header("Upload-Offset", "0")
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "upload session created", uploadSessionResponse{
+		UploadID:     session.ID,
+		FileID:       session.FileID,
+		UploadOffset: session.UploadOffset,
+		UploadLength: session.TotalSize,
+		ExpiresAt:    session.ExpiresAt,
+	})
+}
+
+// @Summary Get Resumable Upload Offset
+// @Description Returns the number of bytes received so far in the Upload-Offset header
+// @Tags Files
+// @Security ApiKeyAuth
+// @Param id path string true "Upload ID"
+// @Success 200 "Upload-Offset and Upload-Length headers"
+// @Failure 404 "Not Found"
+// @Failure 410 "Upload session expired"
+// @Router /auth/files/uploads/{id} [HEAD]
+func (server *Server) getResumableUploadOffset(ctx *gin.Context) {
+	setTusHeaders(ctx)
+	ctx.Header("Cache-Control", "no-store")
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	session, err := server.store.GetUploadSession(ctx, database.GetUploadSessionParams{
+		ID:     ctx.Param("id"),
+		UserID: int32(payload.UserID),
+	})
+
+	if err != nil || session.UploadMethod != database.UploadResumable {
+		if err == nil || errors.Is(err, pgx.ErrNoRows) {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching upload session")
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if session.Status != database.SessionActive || time.Now().After(session.ExpiresAt) {
+		ctx.Status(http.StatusGone)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(session.UploadOffset, 10))
+	ctx.Header("Upload-Length", strconv.FormatInt(session.TotalSize, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// @Summary Upload Resumable Chunk
+// @Description Append a chunk at the given offset, the file is assembled and marked successful once the last byte arrives
+// @Tags Files
+// @Security ApiKeyAuth
+// @Accept application/offset+octet-stream
+// @Param id path string true "Upload ID"
+// @Param Tus-Resumable header string true "tus protocol version, 1.0.0"
+// @Param Upload-Offset header int true "Offset the chunk starts at"
+// @Success 204 "Upload-Offset header with the new offset"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 409 {object} standardResponse "Offset mismatch or identical content already uploaded"
+// @Failure 410 {object} standardResponse "Upload session expired"
+// @Failure 415 {object} standardResponse "Wrong content type or not a supported audio file"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/uploads/{id} [PATCH]
+func (server *Server) uploadResumableChunk(ctx *gin.Context) {
+	setTusHeaders(ctx)
+
+	if ctx.ContentType() != tusContentType {
+		server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, "content type must be "+tusContentType, nil)
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid Upload-Offset header", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	uploadID := ctx.Param("id")
+
+	session, err := server.store.GetUploadSession(ctx, database.GetUploadSessionParams{
+		ID:     uploadID,
+		UserID: int32(payload.UserID),
+	})
+
+	if err != nil || session.UploadMethod != database.UploadResumable {
+		if err == nil || errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find upload session", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching upload session", nil)
+		return
+	}
+
+	if session.Status != database.SessionActive || time.Now().After(session.ExpiresAt) {
+		server.enhanceHTTPResponse(ctx, http.StatusGone, "upload session expired or already finished", nil)
+		return
+	}
+
+	if session.UploadOffset != offset {
+		server.enhanceHTTPResponse(ctx, http.StatusConflict, "Upload-Offset does not match the current offset", nil)
+		return
+	}
+
+	if offset < session.TotalSize {
+		partKey := uploadPartKey(session.ObjectKey, offset)
+		// tus clients send the whole remainder in one request unless told otherwise, so a chunk is only bounded by
+		// what is left of the upload, which the session already capped at the maximum upload size
+		var body io.Reader = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, session.TotalSize-offset)
+
+		// reject non audio on the first chunk instead of after the whole file has been received
+		if offset == 0 {
+			buffered := bufio.NewReader(body)
+			header, _ := buffered.Peek(audio.SniffLen)
+			if len(header) == audio.SniffLen || int64(len(header)) == session.TotalSize {
+				if _, err := audio.Sniff(header); err != nil {
+					server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, nil)
+					return
+				}
+			}
+			body = buffered
+		}
+
+		writer := server.objectStore.NewWriter(ctx, partKey)
+		written, err := io.Copy(writer, body)
+		if err != nil {
+			writer.Abort()
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				server.enhanceHTTPResponse(ctx, http.StatusRequestEntityTooLarge, "chunk exceeds the remaining upload length", nil)
+				return
+			}
+
+			server.baseLogger.Error().Err(err).Msg("error while copying chunk to object store writer")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while receiving chunk, please resume from the current offset", nil)
+			return
+		}
+
+		if written == 0 {
+			writer.Abort()
+			ctx.Header("Upload-Offset", strconv.FormatInt(offset, 10))
+			ctx.Status(http.StatusNoContent)
+			return
+		}
+
+		if err := writer.Close(); err != nil {
+			server.baseLogger.Error().Err(err).Msg("error while closing the object store writer")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while storing chunk, please resume from the current offset", nil)
+			return
+		}
+
+		advanced, err := server.store.AdvanceUploadSessionTx(ctx, int32(payload.UserID), uploadID, offset, written, partKey)
+		if err != nil {
+			if deleteErr := server.objectStore.Delete(ctx, partKey); deleteErr != nil {
+				server.baseLogger.Error().Err(deleteErr).Msgf("error deleting rejected upload part %s", partKey)
+			}
+
+			if errors.Is(err, custom_errors.ErrOffsetMismatch) {
+				server.enhanceHTTPResponse(ctx, http.StatusConflict, "Upload-Offset does not match the current offset", nil)
+				return
+			}
+
+			if errors.Is(err, custom_errors.ErrUploadExpired) || errors.Is(err, custom_errors.ErrNoRecordFound) {
+				server.enhanceHTTPResponse(ctx, http.StatusGone, "upload session expired or already finished", nil)
+				return
+			}
+
+			server.baseLogger.Error().Err(err).Msg("error while advancing upload session")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while storing chunk, please resume from the current offset", nil)
+			return
+		}
+
+		session = *advanced
+	}
+
+	if session.UploadOffset == session.TotalSize {
+		objectKey, contentHash, err := server.assembleUpload(ctx, session)
+		if err != nil {
+			if isInvalidAudio(err) {
+				server.baseLogger.Error().Err(err).Msg("resumable upload is not valid audio")
+				server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+				server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, err.Error())
+				return
+			}
+
+			server.baseLogger.Error().Err(err).Msg("error while assembling uploaded parts")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while finishing upload, retry the last request with an empty body", nil)
+			return
+		}
+
+		metadata, err := server.probeStoredAudio(ctx, objectKey, session.TotalSize)
+		if err != nil {
+			if isInvalidAudio(err) {
+				server.baseLogger.Error().Err(err).Msg("resumable upload is not valid audio")
+				server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+				server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, err.Error())
+				return
+			}
+
+			server.baseLogger.Error().Err(err).Msg("error while probing assembled upload")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while finishing upload, retry the last request with an empty body", nil)
+			return
+		}
+
+		file, err := server.store.CompleteUploadSessionTx(ctx, database.CompleteUploadSessionTxParams{
+			UserID:    int32(payload.UserID),
+			ID:        uploadID,
+			ObjectKey: objectKey,
+			Metadata:  audioMetadataParams(metadata),
+			ContentHash: pgtype.Text{
+				Valid:  true,
+				String: contentHash,
+			},
+			DuplicatePolicy: server.config.DuplicateUploadPolicy,
+		})
+
+		if err != nil {
+			if errors.Is(err, custom_errors.ErrDuplicateContent) {
+				server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+				server.enhanceHTTPResponse(ctx, http.StatusConflict, "file with identical content already exists", nil)
+				return
+			}
+
+			server.baseLogger.Error().Err(err).Msg("error while completing upload session")
+			server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while finishing upload, retry the last request with an empty body", nil)
+			return
+		}
+
+		server.deleteUploadParts(ctx, session.ObjectKey)
+
+		if file.ObjectKey.String != objectKey {
+			server.deleteObject(ctx, objectKey)
+		}
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(session.UploadOffset, 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// @Summary Abort Upload
+// @Description Terminate a resumable or upload url session and discard everything received so far
+// @Tags Files
+// @Security ApiKeyAuth
+// @Param id path string true "Upload ID"
+// @Success 204 "upload aborted"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 410 {object} standardResponse "Upload session already finished"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/uploads/{id} [DELETE]
+func (server *Server) abortResumableUpload(ctx *gin.Context) {
+	setTusHeaders(ctx)
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	session, err := server.store.AbortUploadSessionTx(ctx, int32(payload.UserID), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find upload session", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrUploadExpired) {
+			server.enhanceHTTPResponse(ctx, http.StatusGone, "upload session already finished", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while aborting upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while aborting upload", nil)
+		return
+	}
+
+	server.deleteUploadObjects(ctx, session.ObjectKey)
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
-	"github.com/DEVunderdog/transcript-generator-backend/internal/gcp/cloud_pubsub"
-	"github.com/DEVunderdog/transcript-generator-backend/internal/gcp/storage"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/mailer"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/middleware"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/purge"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/queue"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/ratelimit"
 
 	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
@@ -23,14 +27,19 @@ import (
 )
 
 type Server struct {
-	router        *gin.Engine
-	config        *utils.Config
-	store         database.Store
-	tokenMaker    token.TokenMaker
-	storageClient *storage.StorageClient
-	pubsubClient  *cloud_pubsub.CloudPubSubClient
-	baseLogger    *logger.Logger
-	httpLogger    *middleware.HTTPLogger
+	router      *gin.Engine
+	config      *utils.Config
+	store       database.Store
+	tokenMaker  *token.TokenMaker
+	keyCache    *token.KeyCache
+	objectStore objectstore.ObjectStore
+	publisher   queue.Publisher
+	mailer      mailer.Mailer
+	limiter     ratelimit.Limiter
+	purger      *purge.AccountPurger
+	trashPurger *purge.TrashPurger
+	baseLogger  *logger.Logger
+	httpLogger  *middleware.HTTPLogger
 }
 
 // @Description Response data structure
@@ -77,41 +86,48 @@ func NewServer(ctx context.Context, store database.Store, config *utils.Config,
 		return nil, fmt.Errorf("error initializing encryption keys: %w", err)
 	}
 
-	jwtKeyResponse, err := token.GetKeyBasedOnPurpose(ctx, store, config.KeysPurpose)
+	tokenMaker, err := token.NewTokenMaker(ctx, store, config.Passphrase, config.KeysPurpose)
 	if err != nil {
-		return nil, fmt.Errorf("error getting encryption keys: %w", err)
+		return nil, fmt.Errorf("error loading signing key: %w", err)
 	}
 
-	privateKey, err := token.GetPrivateKey(jwtKeyResponse.PrivateKey, []byte(config.Passphrase))
+	objectStore, err := objectstore.NewObjectStore(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting private key: %w", err)
+		return nil, fmt.Errorf("error creating object store: %w", err)
 	}
 
-	publicKey, err := token.GetPublicKey([]byte(jwtKeyResponse.PublicKey))
+	publisher, err := queue.NewPublisher(ctx, config, store)
 	if err != nil {
-		return nil, fmt.Errorf("error getting public key: %w", err)
+		return nil, fmt.Errorf("error while creating queue publisher: %w", err)
 	}
 
-	tokenMaker := token.NewTokenMaker(publicKey, privateKey)
-
-	storageClient, err := storage.NewStorageClient(ctx, config.BucketName)
+	mailService, err := mailer.NewMailer(config, baseLogger)
 	if err != nil {
-		return nil, fmt.Errorf("error creating storage client in gcp: %w", err)
+		return nil, fmt.Errorf("error while creating mailer: %w", err)
+	}
+
+	if err := promoteConfiguredAdmins(ctx, store, config.AdminEmails, baseLogger); err != nil {
+		return nil, fmt.Errorf("error promoting configured admins: %w", err)
 	}
 
-	pubSubClient, err := cloud_pubsub.NewCloudPubSubClient(ctx, config.TopicID, config.ProjectID)
+	limiter, err := ratelimit.NewLimiter(config, store)
 	if err != nil {
-		return nil, fmt.Errorf("error while creating pub sub client in gcp: %w", err)
+		return nil, fmt.Errorf("error while creating rate limiter: %w", err)
 	}
 
 	server := &Server{
-		config:        config,
-		store:         store,
-		tokenMaker:    *tokenMaker,
-		storageClient: storageClient,
-		pubsubClient:  pubSubClient,
-		baseLogger:    baseLogger,
-		httpLogger:    httpLogger,
+		config:      config,
+		store:       store,
+		tokenMaker:  tokenMaker,
+		keyCache:    token.NewKeyCache(store, config.KeysPurpose, time.Duration(config.AuthCacheTTL)*time.Second, config.AuthCacheSize),
+		objectStore: objectStore,
+		publisher:   publisher,
+		mailer:      mailService,
+		limiter:     limiter,
+		purger:      purge.NewAccountPurger(store, objectStore, baseLogger),
+		trashPurger: purge.NewTrashPurger(store, objectStore, baseLogger, time.Duration(config.TrashRetention)*time.Hour),
+		baseLogger:  baseLogger,
+		httpLogger:  httpLogger,
 	}
 
 	if err := server.setupRouter(); err != nil {
@@ -121,18 +137,37 @@ func NewServer(ctx context.Context, store database.Store, config *utils.Config,
 	return server, nil
 }
 
+// ServerShutdown waits for in-flight requests until ctx is done and then stops the background workers and clients,
+// ctx must not be one that is already cancelled by the shutdown signal.
 func (server *Server) ServerShutdown(ctx context.Context, srv *http.Server) error {
+	// requests still use every client below, so they drain first
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down http server: %w", err)
+	}
 
-	if err := server.storageClient.Close(); err != nil {
-		return fmt.Errorf("error closing gcp storage client: %w", err)
+	// the purges still need the object store and the database
+	if err := server.purger.Close(); err != nil {
+		return fmt.Errorf("error stopping account purger: %w", err)
 	}
 
-	if err := server.pubsubClient.Close(); err != nil {
-		return fmt.Errorf("error closing gcp pub sub client: %w", err)
+	if err := server.trashPurger.Close(); err != nil {
+		return fmt.Errorf("error stopping trash purger: %w", err)
 	}
 
-	if err := srv.Shutdown(ctx); err != nil {
-		return fmt.Errorf("error shutting down http server: %w", err)
+	if err := server.objectStore.Close(); err != nil {
+		return fmt.Errorf("error closing object store: %w", err)
+	}
+
+	if err := server.publisher.Close(); err != nil {
+		return fmt.Errorf("error closing queue publisher: %w", err)
+	}
+
+	if err := server.limiter.Close(); err != nil {
+		return fmt.Errorf("error closing rate limiter: %w", err)
+	}
+
+	if err := server.mailer.Close(); err != nil {
+		return fmt.Errorf("error closing mailer: %w", err)
 	}
 
 	return nil
@@ -141,15 +176,26 @@ func (server *Server) ServerShutdown(ctx context.Context, srv *http.Server) erro
 func (server *Server) setupRouter() error {
 	router := gin.Default()
 
-	router.SetTrustedProxies(nil)
+	// the per ip rate limits key on ClientIP, which falls back to the peer address unless the proxy in front is trusted
+	trustedProxies := make([]string, 0, len(server.config.TrustedProxies))
+	for _, proxy := range server.config.TrustedProxies {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 
 	router.ForwardedByClientIP = true
+	router.TrustedPlatform = server.config.TrustedPlatform
 
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins: true, // needs to change in prod
-		AllowMethods:    []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:    []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:   []string{"Content-Length"},
+		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "HEAD", "DELETE"},
+		AllowHeaders:    []string{"Origin", "Content-Type", "Accept", "Authorization", "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Metadata"},
+		ExposeHeaders:   []string{"Content-Length", "Location", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size", "Upload-Offset", "Upload-Length", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"},
 		MaxAge:          24 * time.Hour,
 	}))
 
@@ -158,27 +204,118 @@ func (server *Server) setupRouter() error {
 	router.Use(server.httpLogger.LoggingMiddleware())
 
 	router.GET("/server/health", server.serverHealthCheck)
-	router.POST("/server/api/register", server.generateAPIKey)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	router.GET("/.well-known/jwks.json", server.jwks)
+
+	publicRoutes := router.Group("/server/api")
+	{
+		publicRoutes.Use(middleware.RateLimit(server.limiter, "public", ratelimit.PerMinute(server.config.RateLimitPublic), middleware.ByClientIP))
+		publicRoutes.POST("/register", server.registerUser)
+		publicRoutes.POST("/register/confirm", server.confirmRegistration)
+		publicRoutes.POST("/recover", server.recoverAPIKey)
+		publicRoutes.POST("/recover/confirm", server.confirmRecovery)
+	}
 
 	authRoutes := router.Group("/server/auth")
 	{
-		authRoutes.Use(middleware.Authenticate(*server.config, server.store))
+		authRoutes.Use(middleware.Authenticate(*server.config, server.store, server.keyCache))
+		authRoutes.Use(middleware.RateLimit(server.limiter, "api", ratelimit.PerMinute(server.config.RateLimitAPI), middleware.ByAPIKey))
+		authRoutes.POST("/token", server.createAccessToken)
 		authRoutes.DELETE("/api/delete", server.deleteAPIKey)
+		authRoutes.GET("/api/keys", server.listAPIKeys)
+		authRoutes.POST("/api/keys", server.createAPIKey)
+		authRoutes.DELETE("/api/keys/:id", server.revokeAPIKey)
+	}
+
+	adminRoutes := authRoutes.Group("/admin")
+	{
+		adminRoutes.Use(middleware.RequireRole(database.RoleAdmin), middleware.RequireScope(token.ScopeAdmin))
+		adminRoutes.GET("/signing-keys", server.listSigningKeys)
+		adminRoutes.POST("/signing-keys/rotate", server.rotateSigningKey)
+		adminRoutes.GET("/users", server.listUsers)
+		adminRoutes.GET("/users/:id", server.getUserDetails)
+		adminRoutes.POST("/users/:id/suspend", server.suspendUser)
+		adminRoutes.POST("/users/:id/unsuspend", server.unsuspendUser)
+		adminRoutes.PUT("/users/:id/role", server.updateUserRole)
+		adminRoutes.GET("/users/:id/keys", server.listUserAPIKeys)
+		adminRoutes.DELETE("/users/:id/keys", server.revokeUserAPIKeys)
+		adminRoutes.DELETE("/users/:id/keys/:key_id", server.revokeUserAPIKey)
+		adminRoutes.GET("/users/:id/files", server.listUserFiles)
+		adminRoutes.GET("/users/:id/jobs", server.listUserTranscriptJobs)
+		adminRoutes.GET("/reconciler/runs", server.listReconcilerRuns)
+		adminRoutes.GET("/gc/quarantine", server.listQuarantinedObjects)
+		adminRoutes.GET("/gc/audit", server.listGCAuditEntries)
 	}
 
+	readScope := middleware.RequireScope(token.ScopeFilesRead)
+	writeScope := middleware.RequireScope(token.ScopeFilesWrite)
+
+	authRoutes.GET("/usage", readScope, server.getUsage)
+	authRoutes.DELETE("/account", writeScope, server.deleteAccount)
+	authRoutes.GET("/account/export", readScope, server.exportAccount)
+
 	fileRoutes := authRoutes.Group("/files")
 	{
-		fileRoutes.POST("/upload", server.uploadFileToBucket)
-		fileRoutes.POST("/update", server.updateFile)
-		fileRoutes.GET("/list", server.listAllFiles)
-		fileRoutes.DELETE("/delete/:filename", server.deleteFile)
-		fileRoutes.GET("/sync", server.sync)
+		fileRoutes.POST("/upload", writeScope, server.uploadFileToBucket)
+		fileRoutes.POST("/update", writeScope, server.updateFile)
+		fileRoutes.GET("/list", readScope, server.listAllFiles)
+		fileRoutes.DELETE("/delete/:filename", writeScope, server.deleteFile)
+		fileRoutes.GET("/trash", readScope, server.listTrashedFiles)
+		fileRoutes.POST("/:id/restore", writeScope, server.restoreFile)
+		fileRoutes.PUT("/:id/collection", writeScope, server.setFileCollection)
+		fileRoutes.PUT("/:id/tags", writeScope, server.setFileTags)
+		fileRoutes.POST("/uploads", writeScope, server.createResumableUpload)
+		fileRoutes.HEAD("/uploads/:id", writeScope, server.getResumableUploadOffset)
+		fileRoutes.PATCH("/uploads/:id", writeScope, server.uploadResumableChunk)
+		fileRoutes.DELETE("/uploads/:id", writeScope, server.abortResumableUpload)
+		fileRoutes.POST("/upload-url", writeScope, server.createUploadURL)
+		fileRoutes.POST("/upload-url/:id/complete", writeScope, server.completeUploadURL)
+		fileRoutes.GET("/download-url/:file_id", readScope, server.createDownloadURL)
+	}
+
+	collectionRoutes := authRoutes.Group("/collections")
+	{
+		collectionRoutes.GET("", readScope, server.listCollections)
+		collectionRoutes.POST("", writeScope, server.createCollection)
+		collectionRoutes.PUT("/:id", writeScope, server.updateCollection)
+		collectionRoutes.DELETE("/:id", writeScope, server.deleteCollection)
+		// the handler charges the transcript bucket once per file it requests, not once per call
+		collectionRoutes.POST("/:id/transcripts", middleware.RequireScope(token.ScopeTranscriptRequest), server.requestCollectionTranscripts)
+	}
+
+	tagRoutes := authRoutes.Group("/tags")
+	{
+		tagRoutes.GET("", readScope, server.listTags)
+		tagRoutes.POST("", writeScope, server.createTag)
+		tagRoutes.PUT("/:id", writeScope, server.renameTag)
+		tagRoutes.DELETE("/:id", writeScope, server.deleteTag)
 	}
 
 	transcriptRoutes := authRoutes.Group("/transcript")
 	{
-		transcriptRoutes.GET("/request", server.requestTranscript)
+		transcriptRoutes.GET("/request",
+			middleware.RequireScope(token.ScopeTranscriptRequest),
+			middleware.RateLimit(server.limiter, "transcript", ratelimit.PerMinute(server.config.RateLimitTranscript), middleware.ByUser),
+			server.requestTranscript,
+		)
+		transcriptRoutes.GET("/jobs", readScope, server.listTranscriptJobs)
+		transcriptRoutes.GET("/jobs/:id", readScope, server.getTranscriptJob)
+		transcriptRoutes.GET("/:file_id", readScope, server.getTranscript)
+	}
+
+	if localStore, ok := server.objectStore.(*objectstore.LocalStore); ok {
+		storageRoutes := router.Group(objectstore.LocalSignedURLPath)
+		{
+			storageRoutes.GET("/*key", server.serveLocalObject(localStore))
+			storageRoutes.HEAD("/*key", server.serveLocalObject(localStore))
+			storageRoutes.PUT("/*key", server.serveLocalObject(localStore))
+		}
+	}
+
+	internalRoutes := router.Group("/server/internal")
+	{
+		internalRoutes.Use(middleware.VerifyWorkerSignature(server.config.WorkerSecret))
+		internalRoutes.POST("/jobs/:id/status", server.updateJobStatus)
 	}
 
 	server.router = router
@@ -0,0 +1,553 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/audio"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type uploadURLRequest struct {
+	FileName    string `json:"file_name" binding:"required,max=100"`
+	Size        int64  `json:"size" binding:"required,gt=0"`
+	ContentType string `json:"content_type" binding:"required"`
+}
+
+type uploadURLResponse struct {
+	UploadID  string            `json:"upload_id"`
+	FileID    int32             `json:"file_id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type downloadURLResponse struct {
+	FileID    int32     `json:"file_id"`
+	FileName  string    `json:"file_name"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func sameMediaType(first, second string) bool {
+	firstType, _, err := mime.ParseMediaType(first)
+	if err != nil {
+		return false
+	}
+
+	secondType, _, err := mime.ParseMediaType(second)
+	if err != nil {
+		return false
+	}
+
+	return firstType == secondType
+}
+
+func (server *Server) hashStoredObject(ctx *gin.Context, objectKey string) (string, error) {
+	reader, err := server.objectStore.NewReader(ctx, objectKey)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// errUploadChanged means the staging object was overwritten between sniffing it and copying it.
+var errUploadChanged = errors.New("uploaded object changed during verification")
+
+// sniffStoredAudio reads just enough of an object to tell its audio format.
+func (server *Server) sniffStoredAudio(ctx *gin.Context, objectKey string) (string, error) {
+	reader, err := server.objectStore.NewRangeReader(ctx, objectKey, 0, audio.SniffLen)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	header := make([]byte, audio.SniffLen)
+	n, err := io.ReadFull(reader, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return audio.Sniff(header[:n])
+}
+
+// verifyUploadCopy holds the copy of a direct upload to what the session declared and returns its metadata and
+// hex sha256, a copy whose format differs from the sniffed one was taken from a staging object that got replaced.
+func (server *Server) verifyUploadCopy(ctx *gin.Context, session database.UploadSession, objectKey, format string) (*audio.Metadata, string, error) {
+	attrs, err := server.objectStore.Attrs(ctx, objectKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if attrs.Size != session.TotalSize {
+		return nil, "", errUploadChanged
+	}
+
+	// an empty content type means the backend does not record one, the local store checks it while serving the PUT instead
+	if attrs.ContentType != "" && !sameMediaType(attrs.ContentType, session.ContentType.String) {
+		return nil, "", errUploadChanged
+	}
+
+	metadata, err := server.probeStoredAudio(ctx, objectKey, attrs.Size)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if metadata.Format != format {
+		return nil, "", errUploadChanged
+	}
+
+	// the bytes never passed through the server, so they are read back once to hash them
+	contentHash, err := server.hashStoredObject(ctx, objectKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return metadata, contentHash, nil
+}
+
+func (server *Server) signedURLExpiry() time.Duration {
+	return time.Duration(server.config.SignedURLExpiry) * time.Minute
+}
+
+// @Summary Create Upload URL
+// @Description Register a pending file and return a time limited url the client uploads the audio to directly
+// @Tags Files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body uploadURLRequest true "File to upload"
+// @Success 201 {object} standardResponse{response=responseData{data=uploadURLResponse}} "upload url created"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 403 {object} standardResponse "Plan quota exceeded"
+// @Failure 409 {object} standardResponse "Status Conflict"
+// @Failure 413 {object} standardResponse "Request Entity Too Large"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/upload-url [POST]
+func (server *Server) createUploadURL(ctx *gin.Context) {
+	var req uploadURLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request body")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.ContentType)
+	if err != nil || !strings.HasPrefix(mediaType, "audio/") {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "content type must be an audio media type", nil)
+		return
+	}
+
+	if req.Size > server.config.MaxUploadSize {
+		server.enhanceHTTPResponse(ctx, http.StatusRequestEntityTooLarge, "file size exceeded the allowed upload size", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	// the client only ever writes to this staging key, completion copies the verified content elsewhere
+	objectKey := fmt.Sprintf("%d/%s", payload.UserID, uuid.New().String())
+
+	session, err := server.store.CreateUploadSessionTx(ctx, database.CreateUploadSessionTxParams{
+		ID:           uuid.New().String(),
+		UserID:       int32(payload.UserID),
+		FileName:     req.FileName,
+		ObjectKey:    objectKey,
+		TotalSize:    req.Size,
+		ExpiresAt:    time.Now().Add(time.Duration(server.config.UploadExpiry) * time.Hour),
+		UploadMethod: database.UploadDirect,
+		ContentType: pgtype.Text{
+			Valid:  true,
+			String: req.ContentType,
+		},
+	})
+
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrDuplicateData) {
+			server.baseLogger.Error().Err(err).Msg("file with that name already exists")
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file with that name already exits", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrQuotaExceeded) {
+			server.baseLogger.Error().Err(err).Msg("storage quota exceeded")
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while creating upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating upload session", nil)
+		return
+	}
+
+	expiry := server.signedURLExpiry()
+
+	signed, err := server.objectStore.SignedURL(ctx, objectKey, objectstore.SignedURLOptions{
+		Method:        http.MethodPut,
+		Expires:       expiry,
+		ContentType:   req.ContentType,
+		ContentLength: req.Size,
+	})
+
+	if err != nil {
+		if _, rollbackErr := server.store.AbortUploadSessionTx(ctx, int32(payload.UserID), session.ID); rollbackErr != nil {
+			server.baseLogger.Error().Err(rollbackErr).Msgf("error while rollbacking upload session because signing failed: %s", err.Error())
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while signing upload url")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating upload url", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "upload url created", uploadURLResponse{
+		UploadID:  session.ID,
+		FileID:    session.FileID,
+		URL:       signed.URL,
+		Method:    http.MethodPut,
+		Headers:   signed.Headers,
+		ExpiresAt: time.Now().Add(expiry),
+	})
+}
+
+// @Summary Complete Upload URL
+// @Description Verify the object uploaded through the signed url and mark the file as uploaded
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Success 200 {object} standardResponse "file uploaded successfully"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 409 {object} standardResponse "Object missing, not matching the declared file or identical content already uploaded"
+// @Failure 410 {object} standardResponse "Upload session expired"
+// @Failure 415 {object} standardResponse "Not a supported audio file"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/upload-url/{id}/complete [POST]
+func (server *Server) completeUploadURL(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	uploadID := ctx.Param("id")
+
+	session, err := server.store.GetUploadSession(ctx, database.GetUploadSessionParams{
+		ID:     uploadID,
+		UserID: int32(payload.UserID),
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find upload session", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching upload session", nil)
+		return
+	}
+
+	if session.UploadMethod != database.UploadDirect {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find upload session", nil)
+		return
+	}
+
+	if session.Status != database.SessionActive || time.Now().After(session.ExpiresAt) {
+		server.enhanceHTTPResponse(ctx, http.StatusGone, "upload session expired or already finished", nil)
+		return
+	}
+
+	attrs, err := server.objectStore.Attrs(ctx, session.ObjectKey)
+	if err != nil {
+		if errors.Is(err, objectstore.ErrObjectNotExist) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file has not been uploaded to the upload url yet", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching uploaded object attributes")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while verifying uploaded file", nil)
+		return
+	}
+
+	if attrs.Size != session.TotalSize {
+		server.enhanceHTTPResponse(ctx, http.StatusConflict, "uploaded file size does not match the declared size", gin.H{
+			"expected_size": session.TotalSize,
+			"actual_size":   attrs.Size,
+		})
+		return
+	}
+
+	format, err := server.sniffStoredAudio(ctx, session.ObjectKey)
+	if err != nil {
+		if isInvalidAudio(err) {
+			server.baseLogger.Error().Err(err).Msg("object uploaded through upload url is not valid audio")
+			server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+			server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, err.Error())
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while sniffing uploaded object")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while verifying uploaded file", nil)
+		return
+	}
+
+	// the signed url stays usable until it expires, so everything below works on a copy the client cannot write to
+	objectKey := session.ObjectKey + audio.Extension(format)
+	if err := server.objectStore.Copy(ctx, session.ObjectKey, objectKey); err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while copying uploaded object")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while verifying uploaded file", nil)
+		return
+	}
+
+	metadata, contentHash, err := server.verifyUploadCopy(ctx, session, objectKey, format)
+	if err != nil {
+		server.deleteObject(ctx, objectKey)
+
+		if errors.Is(err, errUploadChanged) {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "uploaded file changed while being verified, please complete the upload again", nil)
+			return
+		}
+
+		if isInvalidAudio(err) {
+			server.baseLogger.Error().Err(err).Msg("object uploaded through upload url is not valid audio")
+			server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+			server.enhanceHTTPResponse(ctx, http.StatusUnsupportedMediaType, unsupportedAudioMessage, err.Error())
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while verifying copy of uploaded object")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while verifying uploaded file", nil)
+		return
+	}
+
+	file, err := server.store.CompleteUploadSessionTx(ctx, database.CompleteUploadSessionTxParams{
+		UserID:    int32(payload.UserID),
+		ID:        uploadID,
+		ObjectKey: objectKey,
+		Metadata:  audioMetadataParams(metadata),
+		ContentHash: pgtype.Text{
+			Valid:  true,
+			String: contentHash,
+		},
+		DuplicatePolicy: server.config.DuplicateUploadPolicy,
+	})
+	if err != nil {
+		server.deleteObject(ctx, objectKey)
+
+		if errors.Is(err, custom_errors.ErrDuplicateContent) {
+			server.discardUploadSession(ctx, int32(payload.UserID), uploadID)
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "file with identical content already exists", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrUploadExpired) {
+			server.enhanceHTTPResponse(ctx, http.StatusGone, "upload session expired or already finished", nil)
+			return
+		}
+
+		if errors.Is(err, custom_errors.ErrResourceConflict) {
+			server.baseLogger.Error().Err(err).Msg("resource concurrently got tampered")
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "resource conflicts, please try again later", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while completing upload session")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while completing upload", nil)
+		return
+	}
+
+	server.deleteObject(ctx, session.ObjectKey)
+
+	if file.ObjectKey.String != objectKey {
+		server.deleteObject(ctx, objectKey)
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "file uploaded successfully", uploadedFileResponse{
+		ID:       file.ID,
+		FileName: file.FileName,
+	})
+}
+
+// @Summary Create Download URL
+// @Description Return a time limited url to download the original audio directly from storage
+// @Tags Files
+// @Security ApiKeyAuth
+// @Produce json
+// @Param file_id path int true "File ID"
+// @Success 200 {object} standardResponse{response=responseData{data=downloadURLResponse}} "download url created"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 409 {object} standardResponse "File is not available"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/download-url/{file_id} [GET]
+func (server *Server) createDownloadURL(ctx *gin.Context) {
+	fileID, err := strconv.ParseInt(ctx.Param("file_id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid file id", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	file, err := server.store.GetFile(ctx, database.GetFileParams{
+		ID:     int32(fileID),
+		UserID: int32(payload.UserID),
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find file with provided id", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching file")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching file", nil)
+		return
+	}
+
+	if file.LockStatus || file.UploadStatus != database.Success || !file.ObjectKey.Valid {
+		server.enhanceHTTPResponse(ctx, http.StatusConflict, "file is not available for download, please try later", nil)
+		return
+	}
+
+	expiry := server.signedURLExpiry()
+
+	signed, err := server.objectStore.SignedURL(ctx, file.ObjectKey.String, objectstore.SignedURLOptions{
+		Method:  http.MethodGet,
+		Expires: expiry,
+	})
+
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while signing download url")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating download url", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "download url created", downloadURLResponse{
+		FileID:    file.ID,
+		FileName:  file.FileName,
+		URL:       signed.URL,
+		ExpiresAt: time.Now().Add(expiry),
+	})
+}
+
+// serveLocalObject answers the signed urls of the local store, it is only mounted when that store is in use
+// and authenticates purely through the url signature.
+func (server *Server) serveLocalObject(localStore *objectstore.LocalStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := strings.TrimPrefix(ctx.Param("key"), "/")
+		method := ctx.Request.Method
+		if method == http.MethodHead {
+			method = http.MethodGet
+		}
+
+		signed, err := localStore.VerifySignedURL(method, key, ctx.Request.URL.Query())
+		if err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusForbidden, "invalid or expired signed url", nil)
+			return
+		}
+
+		switch ctx.Request.Method {
+		case http.MethodPut:
+			if signed.ContentType != "" && !sameMediaType(ctx.GetHeader("Content-Type"), signed.ContentType) {
+				server.enhanceHTTPResponse(ctx, http.StatusForbidden, "content type does not match the signed url", nil)
+				return
+			}
+
+			// the url outlives its session, once the upload was completed or aborted nothing may be written anymore
+			session, err := server.store.GetUploadSessionByObjectKey(ctx, key)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					server.enhanceHTTPResponse(ctx, http.StatusForbidden, "upload session is no longer active", nil)
+					return
+				}
+
+				server.baseLogger.Error().Err(err).Msg("error while fetching upload session of signed url")
+				server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while storing object", nil)
+				return
+			}
+
+			if session.Status != database.SessionActive || time.Now().After(session.ExpiresAt) {
+				server.enhanceHTTPResponse(ctx, http.StatusForbidden, "upload session is no longer active", nil)
+				return
+			}
+
+			limit := server.config.MaxUploadSize
+			if signed.ContentLength > 0 {
+				if ctx.Request.ContentLength >= 0 && ctx.Request.ContentLength != signed.ContentLength {
+					server.enhanceHTTPResponse(ctx, http.StatusForbidden, "content length does not match the signed url", nil)
+					return
+				}
+				limit = signed.ContentLength
+			}
+
+			body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+
+			writer := localStore.NewWriter(ctx, key)
+			written, err := io.Copy(writer, body)
+			if err == nil && signed.ContentLength > 0 && written != signed.ContentLength {
+				writer.Abort()
+				server.enhanceHTTPResponse(ctx, http.StatusForbidden, "content length does not match the signed url", nil)
+				return
+			}
+			if err != nil {
+				writer.Abort()
+
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					server.enhanceHTTPResponse(ctx, http.StatusRequestEntityTooLarge, "object exceeds the allowed upload size", nil)
+					return
+				}
+
+				server.baseLogger.Error().Err(err).Msg("error while writing object through signed url")
+				server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while storing object", nil)
+				return
+			}
+
+			if err := writer.Close(); err != nil {
+				server.baseLogger.Error().Err(err).Msg("error while closing object written through signed url")
+				server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while storing object", nil)
+				return
+			}
+
+			ctx.Status(http.StatusOK)
+
+		default:
+			reader, err := localStore.NewReader(ctx, key)
+			if err != nil {
+				if errors.Is(err, objectstore.ErrObjectNotExist) {
+					server.enhanceHTTPResponse(ctx, http.StatusNotFound, "object does not exist", nil)
+					return
+				}
+
+				server.baseLogger.Error().Err(err).Msg("error while reading object through signed url")
+				server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while reading object", nil)
+				return
+			}
+			defer reader.Close()
+
+			ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(key)))
+			ctx.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, nil)
+		}
+	}
+}
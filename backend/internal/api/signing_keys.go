@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+)
+
+type rotateSigningKeyRequest struct {
+	RetireAfterHours *int `json:"retire_after_hours" binding:"omitempty,min=0"`
+}
+
+type rotateSigningKeyResponse struct {
+	KeyID        int32      `json:"key_id"`
+	RetiredKeyID *int32     `json:"retired_key_id,omitempty"`
+	RetiresAt    *time.Time `json:"retires_at,omitempty"`
+	Resigned     int        `json:"resigned_api_keys"`
+}
+
+type signingKeyDetails struct {
+	ID        int32      `json:"id"`
+	PublicKey string     `json:"public_key"`
+	Active    bool       `json:"active"`
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// @Summary List Signing Keys
+// @Description Lists the signing keys which still verify api keys, the active one signs new keys
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse{response=responseData{data=[]signingKeyDetails}} "signing keys fetched successfully"
+// @Failure 403 {object} standardResponse "admin role and scope required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/signing-keys [GET]
+func (server *Server) listSigningKeys(ctx *gin.Context) {
+	keys, err := server.store.ListVerificationKeys(ctx, server.config.KeysPurpose)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing signing keys")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing signing keys", nil)
+		return
+	}
+
+	details := make([]signingKeyDetails, 0, len(keys))
+	for _, key := range keys {
+		details = append(details, signingKeyDetails{
+			ID:        key.ID,
+			PublicKey: key.PublicKey,
+			Active:    key.IsActive.Bool,
+			RetiresAt: optionalTime(key.RetiresAt),
+			CreatedAt: key.CreatedAt.Time,
+		})
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "signing keys fetched successfully", details)
+}
+
+// @Summary Rotate Signing Key
+// @Description Generates a new signing key and re-signs every api key with it, the previous key keeps verifying until it retires
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body rotateSigningKeyRequest false "Hours until the previous key retires"
+// @Success 201 {object} standardResponse{response=responseData{data=rotateSigningKeyResponse}} "signing key rotated"
+// @Failure 400 {object} standardResponse "invalid request"
+// @Failure 403 {object} standardResponse "admin role and scope required"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/admin/signing-keys/rotate [POST]
+func (server *Server) rotateSigningKey(ctx *gin.Context) {
+	var req rotateSigningKeyRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid request", err.Error())
+			return
+		}
+	}
+
+	retireAfter := server.config.SigningKeyRetirement
+	if req.RetireAfterHours != nil {
+		retireAfter = *req.RetireAfterHours
+	}
+
+	retiresAt := time.Now().Add(time.Duration(retireAfter) * time.Hour)
+
+	result, err := token.RotateSigningKey(ctx, server.store, server.config.Passphrase, server.config.KeysPurpose, retiresAt)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while rotating signing key")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while rotating signing key", nil)
+		return
+	}
+
+	// cached rows still carry the old signature and signer
+	server.keyCache.InvalidateAll()
+
+	response := rotateSigningKeyResponse{
+		KeyID:    result.Key.ID,
+		Resigned: result.Resigned,
+	}
+
+	if result.RetiredKeyID.Valid {
+		response.RetiredKeyID = &result.RetiredKeyID.Int32
+		response.RetiresAt = &retiresAt
+	}
+
+	server.baseLogger.Info().Int32("key_id", result.Key.ID).Int("resigned", result.Resigned).Msg("signing key rotated")
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "signing key rotated", response)
+}
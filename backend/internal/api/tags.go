@@ -0,0 +1,308 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxTagNameLength = 50
+
+var errInvalidTagName = errors.New("tag names must be between 1 and 50 characters")
+
+type tagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type fileTagsRequest struct {
+	// Tags replaces every tag of the file, an empty list clears them
+	Tags []string `json:"tags" binding:"max=20"`
+}
+
+type tagDetails struct {
+	ID        int32      `json:"id"`
+	Name      string     `json:"name"`
+	FileCount *int64     `json:"file_count,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type fileTagsResponse struct {
+	FileID int32    `json:"file_id"`
+	Tags   []string `json:"tags"`
+}
+
+// normalizeTagNames trims names and drops repeats regardless of case, the first spelling wins.
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(names))
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+			return nil, errInvalidTagName
+		}
+
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		normalized = append(normalized, name)
+	}
+
+	return normalized, nil
+}
+
+func newTagSummaries(tags []database.ListTagsRow) []tagDetails {
+	details := make([]tagDetails, 0, len(tags))
+	for _, tag := range tags {
+		details = append(details, tagDetails{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			FileCount: &tag.FileCount,
+			CreatedAt: optionalTime(tag.CreatedAt),
+		})
+	}
+
+	return details
+}
+
+// @Summary List Tags
+// @Description Lists every tag of the user with the number of files carrying it, in alphabetical order
+// @Tags Tags
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse{response=responseData{data=[]tagDetails}} "tags fetched successfully"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/tags [GET]
+func (server *Server) listTags(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	tags, err := server.store.ListTags(ctx, int32(payload.UserID))
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing tags")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing tags", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "tags fetched successfully", newTagSummaries(tags))
+}
+
+// @Summary Create Tag
+// @Description Creates a tag ahead of using it, tags are also created on the fly when set on a file. Names are unique regardless of case.
+// @Tags Tags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body tagRequest true "Tag"
+// @Success 201 {object} standardResponse{response=responseData{data=tagDetails}} "tag created"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 409 {object} standardResponse "Tag exists"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/tags [POST]
+func (server *Server) createTag(ctx *gin.Context) {
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	names, err := normalizeTagNames([]string{req.Name})
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	tag, err := server.store.CreateTag(ctx, database.CreateTagParams{
+		UserID: int32(payload.UserID),
+		Name:   names[0],
+	})
+	if err != nil {
+		if database.ErrorCode(err) == database.UniqueViolation {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "tag already exists", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while creating tag")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while creating tag", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusCreated, "tag created", tagDetails{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: optionalTime(tag.CreatedAt),
+	})
+}
+
+// @Summary Rename Tag
+// @Description Renames a tag on every file carrying it
+// @Tags Tags
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param request body tagRequest true "Tag"
+// @Success 200 {object} standardResponse{response=responseData{data=tagDetails}} "tag renamed"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Tag not found"
+// @Failure 409 {object} standardResponse "Tag exists"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/tags/{id} [PUT]
+func (server *Server) renameTag(ctx *gin.Context) {
+	tagID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid tag id", nil)
+		return
+	}
+
+	var req tagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	names, err := normalizeTagNames([]string{req.Name})
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	tag, err := server.store.UpdateTagName(ctx, database.UpdateTagNameParams{
+		ID:     int32(tagID),
+		UserID: int32(payload.UserID),
+		Name:   names[0],
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find tag with provided id", nil)
+			return
+		}
+		if database.ErrorCode(err) == database.UniqueViolation {
+			server.enhanceHTTPResponse(ctx, http.StatusConflict, "tag already exists", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while renaming tag")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while renaming tag", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "tag renamed", tagDetails{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		CreatedAt: optionalTime(tag.CreatedAt),
+	})
+}
+
+// @Summary Delete Tag
+// @Description Deletes a tag and removes it from every file, the files themselves are kept
+// @Tags Tags
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} standardResponse "tag deleted"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Tag not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/tags/{id} [DELETE]
+func (server *Server) deleteTag(ctx *gin.Context) {
+	tagID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid tag id", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	rows, err := server.store.DeleteTag(ctx, database.DeleteTagParams{
+		ID:     int32(tagID),
+		UserID: int32(payload.UserID),
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while deleting tag")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while deleting tag", nil)
+		return
+	}
+
+	if rows == 0 {
+		server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find tag with provided id", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "tag deleted", nil)
+}
+
+// @Summary Set File Tags
+// @Description Replaces the tags of a file, names the user has not used before become new tags. Names are matched regardless of case.
+// @Tags Files
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "File ID"
+// @Param request body fileTagsRequest true "Tags, at most 20"
+// @Success 200 {object} standardResponse{response=responseData{data=fileTagsResponse}} "file tags updated"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "File not found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/files/{id}/tags [PUT]
+func (server *Server) setFileTags(ctx *gin.Context) {
+	fileID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid file id", nil)
+		return
+	}
+
+	var req fileTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request body", err.Error())
+		return
+	}
+
+	names, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	if len(names) > database.MaxTagsPerFile {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "a file can carry at most "+strconv.Itoa(database.MaxTagsPerFile)+" tags", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	tags, err := server.store.SetFileTagsTx(ctx, int32(payload.UserID), int32(fileID), names)
+	if err != nil {
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find file with provided id", nil)
+			return
+		}
+		server.baseLogger.Error().Err(err).Msg("error while setting file tags")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while setting file tags", nil)
+		return
+	}
+
+	response := fileTagsResponse{
+		FileID: int32(fileID),
+		Tags:   make([]string, 0, len(tags)),
+	}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, tag.Name)
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "file tags updated", response)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/transcriptformat"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type transcriptQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=txt srt vtt json"`
+}
+
+type transcriptSegmentResponse struct {
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Speaker string  `json:"speaker,omitempty"`
+	Text    string  `json:"text"`
+}
+
+type transcriptResponse struct {
+	FileID   int32                       `json:"file_id"`
+	FileName string                      `json:"file_name"`
+	JobID    int32                       `json:"job_id"`
+	Segments []transcriptSegmentResponse `json:"segments"`
+}
+
+// @Summary Get Transcript
+// @Description Get the latest successful transcript of a file as plain text, SRT, WebVTT or JSON segments
+// @Tags Transcript
+// @Security ApiKeyAuth
+// @Produce json,plain
+// @Param file_id path int true "File ID"
+// @Param format query string false "Output format, defaults to txt" Enums(txt, srt, vtt, json)
+// @Success 200 {object} transcriptResponse "transcript fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/transcript/{file_id} [GET]
+func (server *Server) getTranscript(ctx *gin.Context) {
+	fileID, err := strconv.ParseInt(ctx.Param("file_id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid file id", nil)
+		return
+	}
+
+	var query transcriptQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request query")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Format == "" {
+		query.Format = transcriptformat.FormatText
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	job, err := server.store.GetLatestSucceededJobForFile(ctx, database.GetLatestSucceededJobForFileParams{
+		FileID: int32(fileID),
+		UserID: int32(payload.UserID),
+		Status: database.JobSucceeded,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.baseLogger.Error().Err(err).Msg("cannot find successful transcript job")
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "no transcript available for the file, please request one", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching transcript job")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching transcript", nil)
+		return
+	}
+
+	rows, err := server.store.ListTranscriptSegments(ctx, job.ID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing transcript segments")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching transcript", nil)
+		return
+	}
+
+	if query.Format == transcriptformat.FormatJSON {
+		segments := make([]transcriptSegmentResponse, 0, len(rows))
+		for _, row := range rows {
+			segments = append(segments, transcriptSegmentResponse{
+				Start:   float64(row.StartMs) / 1000,
+				End:     float64(row.EndMs) / 1000,
+				Speaker: row.Speaker.String,
+				Text:    row.Content,
+			})
+		}
+
+		server.enhanceHTTPResponse(ctx, http.StatusOK, "transcript fetched successfully", transcriptResponse{
+			FileID:   int32(fileID),
+			FileName: job.FileName,
+			JobID:    job.ID,
+			Segments: segments,
+		})
+		return
+	}
+
+	segments := make([]transcriptformat.Segment, 0, len(rows))
+	for _, row := range rows {
+		segments = append(segments, transcriptformat.Segment{
+			Start:   time.Duration(row.StartMs) * time.Millisecond,
+			End:     time.Duration(row.EndMs) * time.Millisecond,
+			Speaker: row.Speaker.String,
+			Text:    row.Content,
+		})
+	}
+
+	content, err := transcriptformat.Render(query.Format, segments)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while rendering transcript")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while rendering transcript", nil)
+		return
+	}
+
+	baseName := strings.TrimSuffix(job.FileName, filepath.Ext(job.FileName))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", baseName+"."+query.Format))
+	ctx.Data(http.StatusOK, transcriptformat.ContentType(query.Format), []byte(content))
+}
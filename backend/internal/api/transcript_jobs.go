@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultJobsPageLimit = 20
+
+type listJobsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=QUEUED PROCESSING SUCCEEDED FAILED"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int32  `form:"offset" binding:"omitempty,min=0"`
+}
+
+// @Summary List Transcript Jobs
+// @Description List transcript jobs of the user, newest first
+// @Tags Transcript
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query string false "Filter by job status" Enums(QUEUED, PROCESSING, SUCCEEDED, FAILED)
+// @Param limit query int false "Page size, defaults to 20 and at most 100"
+// @Param offset query int false "Number of jobs to skip"
+// @Success 200 {object} standardResponse "jobs fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/transcript/jobs [GET]
+func (server *Server) listTranscriptJobs(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	server.listJobsOfUser(ctx, int32(payload.UserID))
+}
+
+func (server *Server) listJobsOfUser(ctx *gin.Context, userID int32) {
+	var query listJobsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		server.baseLogger.Error().Err(err).Msg("bad request query")
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "bad request query", err.Error())
+		return
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultJobsPageLimit
+	}
+
+	jobs, err := server.store.ListTranscriptJobs(ctx, database.ListTranscriptJobsParams{
+		UserID: userID,
+		Status: pgtype.Text{
+			Valid:  query.Status != "",
+			String: query.Status,
+		},
+		PageLimit:  query.Limit,
+		PageOffset: query.Offset,
+	})
+
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while listing transcript jobs")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while listing transcript jobs", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "jobs fetched successfully", jobs)
+}
+
+// @Summary Get Transcript Job
+// @Description Get the current state of a transcript job
+// @Tags Transcript
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} standardResponse "job fetched successfully"
+// @Failure 400 {object} standardResponse "Bad Request"
+// @Failure 404 {object} standardResponse "Not Found"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/transcript/jobs/{id} [GET]
+func (server *Server) getTranscriptJob(ctx *gin.Context) {
+	jobID, err := strconv.ParseInt(ctx.Param("id"), 10, 32)
+	if err != nil {
+		server.enhanceHTTPResponse(ctx, http.StatusBadRequest, "please provide valid job id", nil)
+		return
+	}
+
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+	job, err := server.store.GetTranscriptJob(ctx, database.GetTranscriptJobParams{
+		ID:     int32(jobID),
+		UserID: int32(payload.UserID),
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			server.baseLogger.Error().Err(err).Msg("cannot find transcript job")
+			server.enhanceHTTPResponse(ctx, http.StatusNotFound, "cannot find transcript job", nil)
+			return
+		}
+
+		server.baseLogger.Error().Err(err).Msg("error while fetching transcript job")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching transcript job", nil)
+		return
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "job fetched successfully", job)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// usageResponse reports consumption against the plan, a missing maximum means the plan does not cap that resource.
+type usageResponse struct {
+	Plan                    string    `json:"plan"`
+	PeriodStart             time.Time `json:"period_start"`
+	PeriodEnd               time.Time `json:"period_end"`
+	StorageBytes            int64     `json:"storage_bytes"`
+	MaxStorageBytes         *int64    `json:"max_storage_bytes,omitempty"`
+	FileCount               int32     `json:"file_count"`
+	MaxFiles                *int32    `json:"max_files,omitempty"`
+	TranscriptionMinutes    float64   `json:"transcription_minutes"`
+	MaxTranscriptionMinutes *int32    `json:"max_transcription_minutes,omitempty"`
+}
+
+// @Summary Usage
+// @Description Reports the storage and file count held by the user and the transcription minutes consumed in the current monthly period, alongside the limits of the user's plan
+// @Tags Usage
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} standardResponse{response=responseData{data=usageResponse}} "usage fetched successfully"
+// @Failure 500 {object} standardResponse "Internal Server Error"
+// @Router /auth/usage [GET]
+func (server *Server) getUsage(ctx *gin.Context) {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	userID := int32(payload.UserID)
+
+	plan, err := server.store.GetUserPlan(ctx, userID)
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while fetching user plan")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching usage", nil)
+		return
+	}
+
+	// counters are created lazily on the first upload
+	usage, err := server.store.GetUserUsage(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		server.baseLogger.Error().Err(err).Msg("error while fetching user usage")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching usage", nil)
+		return
+	}
+
+	periodStart := database.UsagePeriodStart(time.Now())
+
+	transcribedMs, err := server.store.SumUsageSince(ctx, database.SumUsageSinceParams{
+		UserID:       userID,
+		Kind:         database.UsageTranscription,
+		Since:        periodStart,
+		FailedStatus: database.JobFailed,
+	})
+	if err != nil {
+		server.baseLogger.Error().Err(err).Msg("error while summing transcription usage")
+		server.enhanceHTTPResponse(ctx, http.StatusInternalServerError, "error while fetching usage", nil)
+		return
+	}
+
+	response := usageResponse{
+		Plan:                 plan.Name,
+		PeriodStart:          periodStart,
+		PeriodEnd:            periodStart.AddDate(0, 1, 0),
+		StorageBytes:         usage.StorageBytes,
+		FileCount:            usage.FileCount,
+		TranscriptionMinutes: float64(transcribedMs) / float64(time.Minute/time.Millisecond),
+	}
+
+	if plan.MaxStorageBytes.Valid {
+		response.MaxStorageBytes = &plan.MaxStorageBytes.Int64
+	}
+	if plan.MaxFiles.Valid {
+		response.MaxFiles = &plan.MaxFiles.Int32
+	}
+	if plan.MaxTranscriptionMinutes.Valid {
+		response.MaxTranscriptionMinutes = &plan.MaxTranscriptionMinutes.Int32
+	}
+
+	server.enhanceHTTPResponse(ctx, http.StatusOK, "usage fetched successfully", response)
+}
@@ -0,0 +1,137 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	FormatWAV  = "wav"
+	FormatMP3  = "mp3"
+	FormatFLAC = "flac"
+	FormatOgg  = "ogg"
+	FormatM4A  = "m4a"
+	FormatWebM = "webm"
+)
+
+// SniffLen is the number of leading bytes Sniff needs to recognise every supported format.
+const SniffLen = 12
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported or non audio file")
+	ErrMalformed         = errors.New("malformed audio file")
+)
+
+// Metadata is what can be learned from the container and stream headers without decoding any audio.
+// Duration, SampleRate and Channels are zero when the file does not carry them in its headers.
+type Metadata struct {
+	Format     string
+	Codec      string
+	Duration   time.Duration
+	SampleRate int
+	Channels   int
+}
+
+// Extension returns the file extension objects of the given format are stored with.
+func Extension(format string) string {
+	return "." + format
+}
+
+// Sniff identifies the container from the magic bytes at the start of the file.
+func Sniff(header []byte) (string, error) {
+	switch {
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WAVE")):
+		return FormatWAV, nil
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return FormatFLAC, nil
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return FormatOgg, nil
+	case len(header) >= 8 && bytes.Equal(header[4:8], []byte("ftyp")):
+		return FormatM4A, nil
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return FormatWebM, nil
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return FormatMP3, nil
+	case len(header) >= 4:
+		if _, ok := parseMPEGHeader(header[:4]); ok {
+			return FormatMP3, nil
+		}
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// Probe sniffs the format and parses its headers, size is the total length of the file.
+// Anything that is not audio, or a container without an audio stream, yields ErrUnsupportedFormat.
+func Probe(r io.ReadSeeker, size int64) (*Metadata, error) {
+	header := make([]byte, SniffLen)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrUnsupportedFormat
+		}
+		return nil, err
+	}
+
+	format, err := Sniff(header[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var metadata *Metadata
+
+	switch format {
+	case FormatWAV:
+		metadata, err = probeWAV(r, size)
+	case FormatMP3:
+		metadata, err = probeMP3(r, size)
+	case FormatFLAC:
+		metadata, err = probeFLAC(r)
+	case FormatOgg:
+		metadata, err = probeOgg(r, size)
+	case FormatM4A:
+		metadata, err = probeMP4(r, size)
+	case FormatWebM:
+		metadata, err = probeWebM(r, size)
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedFormat) || errors.Is(err, ErrMalformed) {
+			return nil, err
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: %s file is truncated", ErrMalformed, format)
+		}
+
+		return nil, err
+	}
+
+	metadata.Format = format
+
+	return metadata, nil
+}
+
+func malformed(format, reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrMalformed, format, reason)
+}
+
+func durationOf(units, perSecond uint64) time.Duration {
+	if perSecond == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(units) / float64(perSecond) * float64(time.Second))
+}
+
+func skip(r io.ReadSeeker, n int64) error {
+	_, err := r.Seek(n, io.SeekCurrent)
+	return err
+}
@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+const flacStreamInfoLen = 34
+
+func probeFLAC(r io.ReadSeeker) (*Metadata, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	// STREAMINFO is mandated to be the first metadata block
+	if header[4]&0x7F != 0 {
+		return nil, malformed(FormatFLAC, "does not start with STREAMINFO")
+	}
+
+	streamInfo := make([]byte, flacStreamInfoLen)
+	if _, err := io.ReadFull(r, streamInfo); err != nil {
+		return nil, err
+	}
+
+	metadata := parseFLACStreamInfo(streamInfo)
+
+	return &metadata, nil
+}
+
+// parseFLACStreamInfo reads the 20 bit sample rate, 3 bit channel count and 36 bit sample count packed at byte 10.
+func parseFLACStreamInfo(streamInfo []byte) Metadata {
+	packed := binary.BigEndian.Uint64(streamInfo[10:18])
+
+	sampleRate := packed >> 44
+	channels := (packed>>41)&0x7 + 1
+	totalSamples := packed & 0xFFFFFFFFF
+
+	return Metadata{
+		Codec:      "flac",
+		Duration:   durationOf(totalSamples, sampleRate),
+		SampleRate: int(sampleRate),
+		Channels:   int(channels),
+	}
+}
@@ -0,0 +1,215 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	mpegVersion25 = 0
+	mpegVersion2  = 2
+	mpegVersion1  = 3
+
+	mpegLayer3 = 1
+	mpegLayer2 = 2
+	mpegLayer1 = 3
+
+	mpegChannelMono = 3
+
+	// how far past the id3 tag a frame sync is searched for, encoders sometimes pad generously
+	mp3ScanLen = 64 * 1024
+)
+
+// bitrates in kbps indexed by [version 1 or 2][layer 1, 2 or 3][bitrate index]
+var mpegBitrates = [2][3][16]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+}
+
+var mpegSampleRates = [3]int{44100, 48000, 32000}
+
+type mpegFrame struct {
+	version         int
+	layer           int
+	bitrate         int
+	sampleRate      int
+	channels        int
+	samplesPerFrame int
+	length          int
+}
+
+func parseMPEGHeader(header []byte) (mpegFrame, bool) {
+	var frame mpegFrame
+
+	value := binary.BigEndian.Uint32(header)
+	if value>>21 != 0x7FF {
+		return frame, false
+	}
+
+	version := int(value>>19) & 0x3
+	layer := int(value>>17) & 0x3
+	bitrateIndex := int(value>>12) & 0xF
+	sampleRateIndex := int(value>>10) & 0x3
+	padding := int(value>>9) & 0x1
+	channelMode := int(value>>6) & 0x3
+
+	if version == 1 || layer == 0 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+		return frame, false
+	}
+
+	table := 0
+	if version != mpegVersion1 {
+		table = 1
+	}
+
+	frame.version = version
+	frame.layer = layer
+	frame.bitrate = mpegBitrates[table][3-layer][bitrateIndex] * 1000
+	frame.sampleRate = mpegSampleRates[sampleRateIndex]
+
+	switch version {
+	case mpegVersion2:
+		frame.sampleRate /= 2
+	case mpegVersion25:
+		frame.sampleRate /= 4
+	}
+
+	frame.channels = 2
+	if channelMode == mpegChannelMono {
+		frame.channels = 1
+	}
+
+	switch {
+	case layer == mpegLayer1:
+		frame.samplesPerFrame = 384
+		frame.length = (12*frame.bitrate/frame.sampleRate + padding) * 4
+	case layer == mpegLayer3 && version != mpegVersion1:
+		frame.samplesPerFrame = 576
+		frame.length = 72*frame.bitrate/frame.sampleRate + padding
+	default:
+		frame.samplesPerFrame = 1152
+		frame.length = 144*frame.bitrate/frame.sampleRate + padding
+	}
+
+	return frame, true
+}
+
+func (frame mpegFrame) codec() string {
+	switch frame.layer {
+	case mpegLayer1:
+		return "mp1"
+	case mpegLayer2:
+		return "mp2"
+	default:
+		return "mp3"
+	}
+}
+
+// vbrFrameCount reads the frame count from a Xing/Info or VBRI header in the first frame, which is how
+// variable bitrate files make their duration knowable without scanning every frame.
+func (frame mpegFrame) vbrFrameCount(data []byte) (uint64, bool) {
+	sideInfo := 32
+	switch {
+	case frame.version == mpegVersion1 && frame.channels == 1:
+		sideInfo = 17
+	case frame.version != mpegVersion1 && frame.channels == 2:
+		sideInfo = 17
+	case frame.version != mpegVersion1:
+		sideInfo = 9
+	}
+
+	xing := 4 + sideInfo
+	if len(data) >= xing+12 && (bytes.Equal(data[xing:xing+4], []byte("Xing")) || bytes.Equal(data[xing:xing+4], []byte("Info"))) {
+		flags := binary.BigEndian.Uint32(data[xing+4 : xing+8])
+		if flags&0x1 != 0 {
+			return uint64(binary.BigEndian.Uint32(data[xing+8 : xing+12])), true
+		}
+	}
+
+	const vbri = 4 + 32
+	if len(data) >= vbri+18 && bytes.Equal(data[vbri:vbri+4], []byte("VBRI")) {
+		return uint64(binary.BigEndian.Uint32(data[vbri+14 : vbri+18])), true
+	}
+
+	return 0, false
+}
+
+func id3v2Length(header []byte) int64 {
+	if len(header) < 10 || !bytes.Equal(header[:3], []byte("ID3")) {
+		return 0
+	}
+
+	// the tag size is a syncsafe integer, seven bits per byte
+	length := int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9])
+	length += 10
+
+	if header[5]&0x10 != 0 {
+		length += 10
+	}
+
+	return length
+}
+
+func probeMP3(r io.ReadSeeker, size int64) (*Metadata, error) {
+	var header [10]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	offset := id3v2Length(header[:])
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, mp3ScanLen)
+	n, err := io.ReadFull(r, data)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	data = data[:n]
+
+	for i := 0; i+4 <= len(data); i++ {
+		if data[i] != 0xFF {
+			continue
+		}
+
+		frame, ok := parseMPEGHeader(data[i : i+4])
+		if !ok {
+			continue
+		}
+
+		// a lone sync word is easily hit by chance, require the following frame to line up as well
+		next := i + frame.length
+		if next+4 <= len(data) {
+			if _, ok := parseMPEGHeader(data[next : next+4]); !ok {
+				continue
+			}
+		}
+
+		metadata := &Metadata{
+			Codec:      frame.codec(),
+			SampleRate: frame.sampleRate,
+			Channels:   frame.channels,
+		}
+
+		if frames, ok := frame.vbrFrameCount(data[i:]); ok {
+			metadata.Duration = durationOf(frames*uint64(frame.samplesPerFrame), uint64(frame.sampleRate))
+		} else {
+			audioBytes := size - offset - int64(i)
+			metadata.Duration = durationOf(uint64(audioBytes)*8, uint64(frame.bitrate))
+		}
+
+		return metadata, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
@@ -0,0 +1,237 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// the moov box holds only indexes and headers, anything larger than this is not a sane audio file
+const maxMoovLen = 64 * 1024 * 1024
+
+var mp4Codecs = map[string]string{
+	"mp4a": "aac",
+	"alac": "alac",
+	"Opus": "opus",
+	"fLaC": "flac",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	".mp3": "mp3",
+}
+
+type mp4Track struct {
+	handler    string
+	codec      string
+	channels   int
+	sampleRate int
+	timescale  uint64
+	duration   uint64
+}
+
+// eachBox walks the boxes packed in data, handing the payload of each to fn.
+func eachBox(data []byte, fn func(boxType string, payload []byte) error) error {
+	for len(data) >= 8 {
+		boxSize := uint64(binary.BigEndian.Uint32(data[0:4]))
+		boxType := string(data[4:8])
+		headerLen := uint64(8)
+
+		switch boxSize {
+		case 0:
+			boxSize = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return malformed(FormatM4A, "box header is truncated")
+			}
+			boxSize = binary.BigEndian.Uint64(data[8:16])
+			headerLen = 16
+		}
+
+		if boxSize < headerLen || boxSize > uint64(len(data)) {
+			return malformed(FormatM4A, "box overruns its parent")
+		}
+
+		if err := fn(boxType, data[headerLen:boxSize]); err != nil {
+			return err
+		}
+
+		data = data[boxSize:]
+	}
+
+	return nil
+}
+
+// readMediaTimes reads timescale and duration from a mvhd or mdhd payload, both share the layout.
+func readMediaTimes(payload []byte) (uint64, uint64, bool) {
+	if len(payload) < 1 {
+		return 0, 0, false
+	}
+
+	if payload[0] == 1 {
+		if len(payload) < 32 {
+			return 0, 0, false
+		}
+		return uint64(binary.BigEndian.Uint32(payload[20:24])), binary.BigEndian.Uint64(payload[24:32]), true
+	}
+
+	if len(payload) < 20 {
+		return 0, 0, false
+	}
+	return uint64(binary.BigEndian.Uint32(payload[12:16])), uint64(binary.BigEndian.Uint32(payload[16:20])), true
+}
+
+func parseMP4Track(trak []byte) (mp4Track, error) {
+	var track mp4Track
+
+	err := eachBox(trak, func(boxType string, payload []byte) error {
+		if boxType != "mdia" {
+			return nil
+		}
+
+		return eachBox(payload, func(boxType string, payload []byte) error {
+			switch boxType {
+			case "hdlr":
+				if len(payload) >= 12 {
+					track.handler = string(payload[8:12])
+				}
+			case "mdhd":
+				track.timescale, track.duration, _ = readMediaTimes(payload)
+			case "minf":
+				return eachBox(payload, func(boxType string, payload []byte) error {
+					if boxType != "stbl" {
+						return nil
+					}
+					return eachBox(payload, func(boxType string, payload []byte) error {
+						if boxType == "stsd" && len(payload) > 8 {
+							parseMP4SampleEntry(&track, payload[8:])
+						}
+						return nil
+					})
+				})
+			}
+			return nil
+		})
+	})
+
+	return track, err
+}
+
+// parseMP4SampleEntry reads the first AudioSampleEntry, channel count sits 16 bytes into its payload
+// and the 16.16 fixed point sample rate 24 bytes in.
+func parseMP4SampleEntry(track *mp4Track, entries []byte) {
+	eachBox(entries, func(boxType string, payload []byte) error {
+		if track.codec != "" {
+			return nil
+		}
+
+		track.codec = strings.TrimSpace(boxType)
+		if codec, ok := mp4Codecs[boxType]; ok {
+			track.codec = codec
+		}
+
+		if len(payload) >= 28 {
+			track.channels = int(binary.BigEndian.Uint16(payload[16:18]))
+			track.sampleRate = int(binary.BigEndian.Uint32(payload[24:28]) >> 16)
+		}
+
+		return nil
+	})
+}
+
+func readMoov(r io.ReadSeeker, size int64) ([]byte, error) {
+	var position int64
+
+	for position+8 <= size {
+		var header [16]byte
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return nil, err
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[0:4]))
+		boxType := string(header[4:8])
+		headerLen := int64(8)
+
+		switch boxSize {
+		case 0:
+			boxSize = size - position
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return nil, err
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerLen = 16
+		}
+
+		if boxSize < headerLen {
+			return nil, malformed(FormatM4A, "box is smaller than its header")
+		}
+
+		if boxType == "moov" {
+			if boxSize > maxMoovLen {
+				return nil, malformed(FormatM4A, "moov box is too large")
+			}
+
+			moov := make([]byte, boxSize-headerLen)
+			if _, err := io.ReadFull(r, moov); err != nil {
+				return nil, err
+			}
+			return moov, nil
+		}
+
+		position += boxSize
+		if _, err := r.Seek(position, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, malformed(FormatM4A, "has no moov box")
+}
+
+func probeMP4(r io.ReadSeeker, size int64) (*Metadata, error) {
+	moov, err := readMoov(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		audioTrack     *mp4Track
+		movieTimescale uint64
+		movieDuration  uint64
+	)
+
+	err = eachBox(moov, func(boxType string, payload []byte) error {
+		switch boxType {
+		case "mvhd":
+			movieTimescale, movieDuration, _ = readMediaTimes(payload)
+		case "trak":
+			track, err := parseMP4Track(payload)
+			if err != nil {
+				return err
+			}
+			if track.handler == "soun" && audioTrack == nil {
+				audioTrack = &track
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if audioTrack == nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	metadata := &Metadata{
+		Codec:      audioTrack.codec,
+		Duration:   durationOf(audioTrack.duration, audioTrack.timescale),
+		SampleRate: audioTrack.sampleRate,
+		Channels:   audioTrack.channels,
+	}
+
+	if metadata.Duration == 0 {
+		metadata.Duration = durationOf(movieDuration, movieTimescale)
+	}
+
+	return metadata, nil
+}
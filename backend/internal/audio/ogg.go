@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	oggPageHeaderLen = 27
+	opusGranuleRate  = 48000
+
+	// the last page is searched for in this many trailing bytes, a page is at most about 64 KiB
+	oggTailLen = 80 * 1024
+)
+
+func probeOgg(r io.ReadSeeker, size int64) (*Metadata, error) {
+	var page [oggPageHeaderLen]byte
+	if _, err := io.ReadFull(r, page[:]); err != nil {
+		return nil, err
+	}
+
+	serial := binary.LittleEndian.Uint32(page[14:18])
+
+	segments := make([]byte, page[26])
+	if _, err := io.ReadFull(r, segments); err != nil {
+		return nil, err
+	}
+
+	// the identification header is alone on the first page, so the whole payload is the first packet
+	payloadLen := 0
+	for _, segment := range segments {
+		payloadLen += int(segment)
+	}
+
+	packet := make([]byte, payloadLen)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return nil, err
+	}
+
+	var (
+		metadata    Metadata
+		granuleRate uint64
+		preSkip     uint64
+	)
+
+	switch {
+	case len(packet) >= 19 && bytes.HasPrefix(packet, []byte("OpusHead")):
+		metadata.Codec = "opus"
+		metadata.Channels = int(packet[9])
+		preSkip = uint64(binary.LittleEndian.Uint16(packet[10:12]))
+		metadata.SampleRate = int(binary.LittleEndian.Uint32(packet[12:16]))
+		if metadata.SampleRate == 0 {
+			metadata.SampleRate = opusGranuleRate
+		}
+		granuleRate = opusGranuleRate
+
+	case len(packet) >= 16 && bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		metadata.Codec = "vorbis"
+		metadata.Channels = int(packet[11])
+		metadata.SampleRate = int(binary.LittleEndian.Uint32(packet[12:16]))
+		granuleRate = uint64(metadata.SampleRate)
+
+	case len(packet) >= 17+flacStreamInfoLen && bytes.HasPrefix(packet, []byte("\x7fFLAC")):
+		// mapping header of 9 bytes, the native "fLaC" marker and the STREAMINFO block header precede the block
+		metadata = parseFLACStreamInfo(packet[17:])
+		granuleRate = uint64(metadata.SampleRate)
+
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	granule, err := lastOggGranule(r, size, serial)
+	if err != nil {
+		return nil, err
+	}
+
+	if granule > preSkip {
+		metadata.Duration = durationOf(granule-preSkip, granuleRate)
+	}
+
+	return &metadata, nil
+}
+
+// lastOggGranule returns the granule position of the last page of the stream, which counts the samples in it.
+func lastOggGranule(r io.ReadSeeker, size int64, serial uint32) (uint64, error) {
+	start := max(size-oggTailLen, 0)
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	tail := make([]byte, size-start)
+	if _, err := io.ReadFull(r, tail); err != nil {
+		return 0, err
+	}
+
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+oggPageHeaderLen > len(tail) {
+			continue
+		}
+
+		granule := binary.LittleEndian.Uint64(tail[i+6 : i+14])
+		pageSerial := binary.LittleEndian.Uint32(tail[i+14 : i+18])
+
+		// pages that end no packet carry a granule of -1
+		if pageSerial == serial && granule != ^uint64(0) {
+			return granule, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var wavCodecs = map[uint16]string{
+	0x0001: "pcm",
+	0x0002: "adpcm_ms",
+	0x0003: "pcm_float",
+	0x0006: "pcm_alaw",
+	0x0007: "pcm_mulaw",
+	0x0011: "adpcm_ima",
+	0x0055: "mp3",
+}
+
+const wavFormatExtensible = 0xFFFE
+
+func probeWAV(r io.ReadSeeker, size int64) (*Metadata, error) {
+	if err := skip(r, 12); err != nil {
+		return nil, err
+	}
+
+	var (
+		metadata  Metadata
+		byteRate  uint32
+		hasFormat bool
+		position  int64 = 12
+	)
+
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, malformed(FormatWAV, "has no data chunk")
+			}
+			return nil, err
+		}
+		position += 8
+
+		id := string(chunk[:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(chunk[4:]))
+
+		switch id {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, malformed(FormatWAV, "fmt chunk is too short")
+			}
+
+			var format [16]byte
+			if _, err := io.ReadFull(r, format[:]); err != nil {
+				return nil, err
+			}
+
+			formatTag := binary.LittleEndian.Uint16(format[0:2])
+			metadata.Channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			metadata.SampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			byteRate = binary.LittleEndian.Uint32(format[8:12])
+
+			if formatTag == wavFormatExtensible {
+				metadata.Codec = "pcm"
+			} else if codec, ok := wavCodecs[formatTag]; ok {
+				metadata.Codec = codec
+			} else {
+				metadata.Codec = fmt.Sprintf("wav_0x%04x", formatTag)
+			}
+
+			hasFormat = true
+
+			if err := skip(r, chunkSize-16+chunkSize&1); err != nil {
+				return nil, err
+			}
+
+		case "data":
+			if !hasFormat {
+				return nil, malformed(FormatWAV, "data chunk precedes fmt chunk")
+			}
+
+			// streaming writers leave the size at its maximum, the data then runs to the end of the file
+			if chunkSize == 0xFFFFFFFF || position+chunkSize > size {
+				chunkSize = size - position
+			}
+
+			metadata.Duration = durationOf(uint64(chunkSize), uint64(byteRate))
+
+			return &metadata, nil
+
+		default:
+			if err := skip(r, chunkSize+chunkSize&1); err != nil {
+				return nil, err
+			}
+		}
+
+		position += chunkSize + chunkSize&1
+	}
+}
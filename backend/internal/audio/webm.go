@@ -0,0 +1,332 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	ebmlHeaderID     = 0x1A45DFA3
+	ebmlDocTypeID    = 0x4282
+	segmentID        = 0x18538067
+	segmentInfoID    = 0x1549A966
+	timecodeScaleID  = 0x2AD7B1
+	durationID       = 0x4489
+	tracksID         = 0x1654AE6B
+	trackEntryID     = 0xAE
+	trackTypeID      = 0x83
+	codecIDID        = 0x86
+	trackAudioID     = 0xE1
+	samplingFreqID   = 0xB5
+	channelsID       = 0x9F
+	clusterID        = 0x1F43B675
+	audioTrackType   = 2
+	unknownEBMLSize  = -1
+	maxEBMLMasterLen = 16 * 1024 * 1024
+
+	defaultTimecodeScale = 1000000
+)
+
+var webmCodecs = map[string]string{
+	"A_OPUS":    "opus",
+	"A_VORBIS":  "vorbis",
+	"A_FLAC":    "flac",
+	"A_AAC":     "aac",
+	"A_MPEG/L3": "mp3",
+}
+
+// readVint decodes an ebml variable length integer, the count of leading zero bits gives its length.
+// IDs keep the length marker bit, sizes drop it and use all ones to mean unknown.
+func readVint(data []byte, keepMarker bool) (int64, int, bool) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0, false
+	}
+
+	length := 1
+	for mask := byte(0x80); data[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+
+	if len(data) < length {
+		return 0, 0, false
+	}
+
+	value := int64(data[0])
+	if !keepMarker {
+		value &= int64(0xFF >> length)
+	}
+
+	allOnes := value == int64(0xFF>>length)
+	for _, b := range data[1:length] {
+		value = value<<8 | int64(b)
+		allOnes = allOnes && b == 0xFF
+	}
+
+	if !keepMarker && allOnes {
+		return unknownEBMLSize, length, true
+	}
+
+	return value, length, true
+}
+
+func readElementHeader(r io.Reader) (int64, int64, int64, error) {
+	var buf [12]byte
+
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return 0, 0, 0, err
+	}
+	idLen := 1
+	for mask := byte(0x80); idLen <= 4 && buf[0]&mask == 0; mask >>= 1 {
+		idLen++
+	}
+	if idLen > 4 {
+		return 0, 0, 0, malformed(FormatWebM, "element id is invalid")
+	}
+	if _, err := io.ReadFull(r, buf[1:idLen]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if _, err := io.ReadFull(r, buf[idLen:idLen+1]); err != nil {
+		return 0, 0, 0, err
+	}
+	sizeLen := 1
+	for mask := byte(0x80); sizeLen <= 8 && buf[idLen]&mask == 0; mask >>= 1 {
+		sizeLen++
+	}
+	if sizeLen > 8 {
+		return 0, 0, 0, malformed(FormatWebM, "element size is invalid")
+	}
+	if _, err := io.ReadFull(r, buf[idLen+1:idLen+sizeLen]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	id, _, _ := readVint(buf[:idLen], true)
+	size, _, _ := readVint(buf[idLen:idLen+sizeLen], false)
+
+	return id, size, int64(idLen + sizeLen), nil
+}
+
+// eachElement walks the ebml elements packed in data, handing the payload of each to fn.
+func eachElement(data []byte, fn func(id int64, payload []byte) error) error {
+	for len(data) > 0 {
+		id, idLen, ok := readVint(data, true)
+		if !ok {
+			return malformed(FormatWebM, "element id is invalid")
+		}
+
+		size, sizeLen, ok := readVint(data[idLen:], false)
+		if !ok || size == unknownEBMLSize || int64(idLen+sizeLen)+size > int64(len(data)) {
+			return malformed(FormatWebM, "element overruns its parent")
+		}
+
+		start := idLen + sizeLen
+		if err := fn(id, data[start:start+int(size)]); err != nil {
+			return err
+		}
+
+		data = data[start+int(size):]
+	}
+
+	return nil
+}
+
+func ebmlUint(payload []byte) uint64 {
+	var value uint64
+	for _, b := range payload {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+func ebmlFloat(payload []byte) float64 {
+	switch len(payload) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(payload)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(payload))
+	default:
+		return 0
+	}
+}
+
+func readMaster(r io.Reader, size int64) ([]byte, error) {
+	if size == unknownEBMLSize || size > maxEBMLMasterLen {
+		return nil, malformed(FormatWebM, "header element is too large")
+	}
+
+	data := make([]byte, size)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
+
+func parseWebMAudioTrack(tracks []byte, metadata *Metadata) (bool, error) {
+	found := false
+
+	err := eachElement(tracks, func(id int64, payload []byte) error {
+		if id != trackEntryID || found {
+			return nil
+		}
+
+		var (
+			trackType  uint64
+			codecID    string
+			sampleRate = 8000.0
+			channels   = uint64(1)
+		)
+
+		err := eachElement(payload, func(id int64, payload []byte) error {
+			switch id {
+			case trackTypeID:
+				trackType = ebmlUint(payload)
+			case codecIDID:
+				codecID = strings.TrimRight(string(payload), "\x00")
+			case trackAudioID:
+				return eachElement(payload, func(id int64, payload []byte) error {
+					switch id {
+					case samplingFreqID:
+						sampleRate = ebmlFloat(payload)
+					case channelsID:
+						channels = ebmlUint(payload)
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+
+		if err != nil || trackType != audioTrackType {
+			return err
+		}
+
+		found = true
+		metadata.SampleRate = int(sampleRate)
+		metadata.Channels = int(channels)
+		metadata.Codec = strings.ToLower(strings.TrimPrefix(codecID, "A_"))
+
+		for prefix, codec := range webmCodecs {
+			if strings.HasPrefix(codecID, prefix) {
+				metadata.Codec = codec
+			}
+		}
+
+		return nil
+	})
+
+	return found, err
+}
+
+func probeWebM(r io.ReadSeeker, size int64) (*Metadata, error) {
+	id, elementSize, _, err := readElementHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if id != ebmlHeaderID {
+		return nil, ErrUnsupportedFormat
+	}
+
+	header, err := readMaster(r, elementSize)
+	if err != nil {
+		return nil, err
+	}
+
+	docType := ""
+	if err := eachElement(header, func(id int64, payload []byte) error {
+		if id == ebmlDocTypeID {
+			docType = strings.TrimRight(string(payload), "\x00")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if docType != FormatWebM {
+		return nil, ErrUnsupportedFormat
+	}
+
+	id, _, _, err = readElementHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if id != segmentID {
+		return nil, malformed(FormatWebM, "has no segment")
+	}
+
+	var (
+		metadata      Metadata
+		hasAudio      bool
+		hasInfo       bool
+		timecodeScale uint64 = defaultTimecodeScale
+		duration      float64
+	)
+
+	position, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Info and Tracks precede the clusters in any file written for playback, so stop at the first cluster
+	for position < size && !(hasAudio && hasInfo) {
+		id, elementSize, headerLen, err := readElementHeader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if id == clusterID {
+			break
+		}
+
+		switch id {
+		case segmentInfoID:
+			info, err := readMaster(r, elementSize)
+			if err != nil {
+				return nil, err
+			}
+
+			err = eachElement(info, func(id int64, payload []byte) error {
+				switch id {
+				case timecodeScaleID:
+					timecodeScale = ebmlUint(payload)
+				case durationID:
+					duration = ebmlFloat(payload)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			hasInfo = true
+
+		case tracksID:
+			tracks, err := readMaster(r, elementSize)
+			if err != nil {
+				return nil, err
+			}
+
+			if hasAudio, err = parseWebMAudioTrack(tracks, &metadata); err != nil {
+				return nil, err
+			}
+
+		default:
+			if elementSize == unknownEBMLSize {
+				return nil, malformed(FormatWebM, "element of unknown size before the clusters")
+			}
+			if err := skip(r, elementSize); err != nil {
+				return nil, err
+			}
+		}
+
+		position += headerLen + elementSize
+	}
+
+	if !hasAudio {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Duration is a float counted in timecode ticks, each tick lasting timecodeScale nanoseconds
+	metadata.Duration = time.Duration(duration * float64(timecodeScale))
+
+	return &metadata, nil
+}
@@ -0,0 +1,9 @@
+create table "message_queue" (
+    id bigserial primary key,
+    topic varchar(100) not null,
+    payload bytea not null,
+    claimed_at timestamptz null,
+    created_at timestamptz default current_timestamp
+);
+
+create index idx_message_queue_unclaimed on "message_queue" ("topic", "id") where claimed_at is null;
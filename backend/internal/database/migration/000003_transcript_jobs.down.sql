@@ -0,0 +1 @@
+drop table if exists transcript_jobs;
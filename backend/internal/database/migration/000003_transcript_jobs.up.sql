@@ -0,0 +1,19 @@
+create table "transcript_jobs" (
+    id serial primary key,
+    user_id int not null,
+    file_id int not null,
+    status varchar(20) not null,
+    error_message text null,
+    started_at timestamptz null,
+    completed_at timestamptz null,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+alter table "transcript_jobs" add constraint "fk_user_transcript_jobs" foreign key ("user_id") references "users" ("id") on update cascade on delete restrict;
+
+alter table "transcript_jobs" add constraint "fk_file_transcript_jobs" foreign key ("file_id") references "file_registry" ("id") on update cascade on delete cascade;
+
+create index idx_transcript_jobs_user_id on "transcript_jobs" ("user_id", "created_at");
+
+create index idx_transcript_jobs_file_status on "transcript_jobs" ("file_id", "status");
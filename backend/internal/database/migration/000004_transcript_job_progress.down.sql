@@ -0,0 +1,3 @@
+alter table "transcript_jobs" drop column if exists "transcript_object_key";
+
+alter table "transcript_jobs" drop column if exists "progress";
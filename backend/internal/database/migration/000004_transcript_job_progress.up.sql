@@ -0,0 +1,3 @@
+alter table "transcript_jobs" add column "progress" int not null default 0;
+
+alter table "transcript_jobs" add column "transcript_object_key" varchar(200) null;
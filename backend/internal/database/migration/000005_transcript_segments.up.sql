@@ -0,0 +1,13 @@
+create table "transcript_segments" (
+    id bigserial primary key,
+    job_id int not null,
+    segment_index int not null,
+    start_ms bigint not null,
+    end_ms bigint not null,
+    speaker varchar(100) null,
+    content text not null
+);
+
+alter table "transcript_segments" add constraint "fk_job_transcript_segments" foreign key ("job_id") references "transcript_jobs" ("id") on update cascade on delete cascade;
+
+create unique index idx_transcript_segments_job_index on "transcript_segments" ("job_id", "segment_index");
@@ -0,0 +1,18 @@
+create table "upload_sessions" (
+    id varchar(36) primary key,
+    user_id int not null,
+    file_id int not null,
+    object_key varchar(150) not null,
+    total_size bigint not null,
+    upload_offset bigint not null default 0,
+    status varchar(20) not null,
+    expires_at timestamptz not null,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+alter table "upload_sessions" add constraint "fk_user_upload_sessions" foreign key ("user_id") references "users" ("id") on update cascade on delete restrict;
+
+alter table "upload_sessions" add constraint "fk_file_upload_sessions" foreign key ("file_id") references "file_registry" ("id") on update cascade on delete cascade;
+
+create index idx_upload_sessions_file_status on "upload_sessions" ("file_id", "status");
@@ -0,0 +1,3 @@
+alter table "upload_sessions" drop column if exists "content_type";
+
+alter table "upload_sessions" drop column if exists "upload_method";
@@ -0,0 +1,3 @@
+alter table "upload_sessions" add column "upload_method" varchar(20) not null default 'RESUMABLE';
+
+alter table "upload_sessions" add column "content_type" varchar(100);
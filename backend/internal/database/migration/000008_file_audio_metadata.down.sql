@@ -0,0 +1,9 @@
+alter table "file_registry" drop column if exists "channels";
+
+alter table "file_registry" drop column if exists "sample_rate";
+
+alter table "file_registry" drop column if exists "duration_ms";
+
+alter table "file_registry" drop column if exists "codec";
+
+alter table "file_registry" drop column if exists "audio_format";
@@ -0,0 +1,9 @@
+alter table "file_registry" add column "audio_format" varchar(20);
+
+alter table "file_registry" add column "codec" varchar(30);
+
+alter table "file_registry" add column "duration_ms" bigint;
+
+alter table "file_registry" add column "sample_rate" int;
+
+alter table "file_registry" add column "channels" int;
@@ -0,0 +1,3 @@
+drop index if exists idx_file_registry_content_hash;
+
+alter table "file_registry" drop column if exists "content_hash";
@@ -0,0 +1,3 @@
+alter table "file_registry" add column "content_hash" varchar(64);
+
+create index idx_file_registry_content_hash on "file_registry" ("user_id", "content_hash") where "content_hash" is not null;
@@ -0,0 +1,5 @@
+drop index if exists idx_transcript_jobs_file_created;
+
+drop index if exists idx_file_registry_user_created;
+
+alter table "file_registry" drop column if exists "size_bytes";
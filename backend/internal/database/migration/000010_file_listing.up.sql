@@ -0,0 +1,5 @@
+alter table "file_registry" add column "size_bytes" bigint;
+
+create index idx_file_registry_user_created on "file_registry" ("user_id", "created_at", "id");
+
+create index idx_transcript_jobs_file_created on "transcript_jobs" ("file_id", "created_at");
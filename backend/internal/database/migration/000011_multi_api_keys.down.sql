@@ -0,0 +1,18 @@
+drop index if exists idx_unique_api_key_credential;
+
+drop index if exists idx_unique_api_key_name;
+
+-- only the oldest key of every user survives the return to one key per user
+delete from "api_keys" a
+using "api_keys" b
+where a.user_id = b.user_id and a.id > b.id;
+
+alter table "api_keys" drop column if exists "last_used_at";
+
+alter table "api_keys" drop column if exists "expires_at";
+
+alter table "api_keys" drop column if exists "scopes";
+
+alter table "api_keys" drop column if exists "name";
+
+alter table "api_keys" add constraint "api_keys_user_id_key" unique ("user_id");
@@ -0,0 +1,13 @@
+alter table "api_keys" drop constraint if exists "api_keys_user_id_key";
+
+alter table "api_keys" add column "name" varchar(100) not null default 'default';
+
+alter table "api_keys" add column "scopes" text[] not null default '{files:read,files:write,transcript:request}';
+
+alter table "api_keys" add column "expires_at" timestamptz;
+
+alter table "api_keys" add column "last_used_at" timestamptz;
+
+create unique index idx_unique_api_key_name on "api_keys" ("user_id", "name");
+
+create unique index idx_unique_api_key_credential on "api_keys" ("credential");
@@ -0,0 +1,14 @@
+-- raw credentials cannot be recovered from their hashes, every key has to be issued again
+delete from "api_keys";
+
+drop index if exists idx_unique_legacy_api_key_hash;
+
+drop index if exists idx_unique_api_key_key_id;
+
+alter table "api_keys" add column "credential" bytea not null;
+
+create unique index idx_unique_api_key_credential on "api_keys" ("credential");
+
+alter table "api_keys" drop column if exists "secret_hash";
+
+alter table "api_keys" drop column if exists "key_id";
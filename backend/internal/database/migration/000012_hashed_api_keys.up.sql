@@ -0,0 +1,17 @@
+alter table "api_keys" add column "key_id" varchar(32);
+
+alter table "api_keys" add column "secret_hash" bytea;
+
+-- legacy keys are the base64 of 32 random bytes, their signature already covers sha256 of those bytes
+update "api_keys" set "secret_hash" = sha256(decode(convert_from("credential", 'UTF8'), 'base64'));
+
+alter table "api_keys" alter column "secret_hash" set not null;
+
+drop index if exists idx_unique_api_key_credential;
+
+alter table "api_keys" drop column "credential";
+
+create unique index idx_unique_api_key_key_id on "api_keys" ("key_id");
+
+-- legacy keys carry no key id and are still looked up by their hash until revoked
+create unique index idx_unique_legacy_api_key_hash on "api_keys" ("secret_hash") where "key_id" is null;
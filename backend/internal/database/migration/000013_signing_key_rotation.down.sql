@@ -0,0 +1,9 @@
+drop index if exists idx_api_keys_signing_key;
+
+alter table "api_keys" drop constraint if exists "fk_signing_key_api_keys";
+
+alter table "api_keys" drop column if exists "signing_key_id";
+
+drop index if exists idx_unique_active_key_purpose;
+
+alter table "encryption_keys" drop column if exists "retires_at";
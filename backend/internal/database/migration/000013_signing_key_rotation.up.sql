@@ -0,0 +1,16 @@
+alter table "encryption_keys" add column "retires_at" timestamptz;
+
+create unique index idx_unique_active_key_purpose on "encryption_keys" ("purpose") where "is_active";
+
+alter table "api_keys" add column "signing_key_id" int;
+
+-- until now every api key was signed by the one active key
+update "api_keys" set "signing_key_id" = (
+    select id from "encryption_keys" where "is_active" order by id desc limit 1
+);
+
+alter table "api_keys" alter column "signing_key_id" set not null;
+
+alter table "api_keys" add constraint "fk_signing_key_api_keys" foreign key ("signing_key_id") references "encryption_keys" ("id") on update cascade on delete restrict;
+
+create index idx_api_keys_signing_key on "api_keys" ("signing_key_id");
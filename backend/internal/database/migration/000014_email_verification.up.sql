@@ -0,0 +1,17 @@
+alter table "users" add column "email_verified_at" timestamptz;
+
+-- accounts registered before verification existed are trusted as they are
+update "users" set "email_verified_at" = coalesce("created_at", current_timestamp);
+
+create table "email_verifications" (
+    id serial primary key,
+    email varchar(255) not null,
+    purpose varchar(20) not null,
+    code_hash bytea not null,
+    attempts int not null default 0,
+    expires_at timestamptz not null,
+    consumed_at timestamptz,
+    created_at timestamptz not null default current_timestamp
+);
+
+create index idx_email_verifications_email on "email_verifications" ("email", "purpose");
@@ -0,0 +1,8 @@
+create unlogged table "rate_limit_buckets" (
+    key varchar(255) primary key,
+    tokens double precision not null,
+    allowed bool not null,
+    updated_at timestamptz not null default current_timestamp
+);
+
+create index idx_rate_limit_buckets_updated on "rate_limit_buckets" ("updated_at");
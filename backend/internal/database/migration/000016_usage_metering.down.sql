@@ -0,0 +1,9 @@
+drop table if exists "usage_events";
+
+drop table if exists "user_usage";
+
+alter table "users" drop constraint if exists "fk_plan_users";
+
+alter table "users" drop column if exists "plan";
+
+drop table if exists "plans";
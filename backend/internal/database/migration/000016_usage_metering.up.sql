@@ -0,0 +1,49 @@
+create table "plans" (
+    name varchar(50) primary key,
+    max_storage_bytes bigint,
+    max_files int,
+    max_transcription_minutes int,
+    created_at timestamptz not null default current_timestamp
+);
+
+-- a null limit means the plan is not capped on that dimension
+insert into "plans" (name, max_storage_bytes, max_files, max_transcription_minutes) values
+    ('free', 1073741824, 100, 60),
+    ('pro', 107374182400, 10000, 3000),
+    ('unlimited', null, null, null);
+
+alter table "users" add column "plan" varchar(50) not null default 'free';
+
+alter table "users" add constraint "fk_plan_users" foreign key ("plan") references "plans" ("name") on update cascade on delete restrict;
+
+create table "user_usage" (
+    user_id int primary key,
+    storage_bytes bigint not null default 0,
+    file_count int not null default 0,
+    updated_at timestamptz not null default current_timestamp
+);
+
+alter table "user_usage" add constraint "fk_user_user_usage" foreign key ("user_id") references "users" ("id") on update cascade on delete cascade;
+
+insert into "user_usage" (user_id, storage_bytes, file_count)
+select user_id, coalesce(sum(size_bytes), 0), count(*)
+from "file_registry"
+group by user_id;
+
+create table "usage_events" (
+    id bigserial primary key,
+    user_id int not null,
+    kind varchar(30) not null,
+    file_id int,
+    job_id int,
+    quantity bigint not null,
+    created_at timestamptz not null default current_timestamp
+);
+
+alter table "usage_events" add constraint "fk_user_usage_events" foreign key ("user_id") references "users" ("id") on update cascade on delete restrict;
+
+alter table "usage_events" add constraint "fk_file_usage_events" foreign key ("file_id") references "file_registry" ("id") on update cascade on delete set null;
+
+alter table "usage_events" add constraint "fk_job_usage_events" foreign key ("job_id") references "transcript_jobs" ("id") on update cascade on delete set null;
+
+create index idx_usage_events_user_kind_created on "usage_events" ("user_id", "kind", "created_at");
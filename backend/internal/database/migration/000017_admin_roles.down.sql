@@ -0,0 +1,9 @@
+drop index if exists idx_users_role;
+
+alter table "users" drop column if exists "suspension_reason";
+
+alter table "users" drop column if exists "suspended_at";
+
+alter table "users" drop constraint if exists "chk_users_role";
+
+alter table "users" drop column if exists "role";
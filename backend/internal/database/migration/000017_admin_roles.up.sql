@@ -0,0 +1,9 @@
+alter table "users" add column "role" varchar(20) not null default 'USER';
+
+alter table "users" add constraint "chk_users_role" check ("role" in ('USER', 'ADMIN'));
+
+alter table "users" add column "suspended_at" timestamptz;
+
+alter table "users" add column "suspension_reason" text;
+
+create index idx_users_role on "users" ("role") where "role" <> 'USER';
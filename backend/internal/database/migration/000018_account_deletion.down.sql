@@ -0,0 +1,3 @@
+drop table if exists "account_deletions";
+
+alter table "users" drop column if exists "deleted_at";
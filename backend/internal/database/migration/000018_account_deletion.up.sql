@@ -0,0 +1,21 @@
+alter table "users" add column "deleted_at" timestamptz;
+
+-- the tombstone outlives the user row, it keeps only a hash of the email so the purge stays auditable without retaining it
+create table "account_deletions" (
+    id serial primary key,
+    user_id int not null,
+    email_hash bytea not null,
+    status varchar(20) not null,
+    attempts int not null default 0,
+    last_error text,
+    objects_deleted int not null default 0,
+    files_deleted int not null default 0,
+    jobs_deleted int not null default 0,
+    lease_expires_at timestamptz,
+    requested_at timestamptz not null default current_timestamp,
+    completed_at timestamptz
+);
+
+create unique index idx_account_deletions_pending_user on "account_deletions" ("user_id") where status = 'PENDING';
+
+create index idx_account_deletions_pending on "account_deletions" ("id") where status = 'PENDING';
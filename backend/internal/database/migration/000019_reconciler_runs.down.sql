@@ -0,0 +1,3 @@
+drop index if exists idx_file_registry_unsettled;
+
+drop table if exists "reconciler_runs";
@@ -0,0 +1,15 @@
+create table "reconciler_runs" (
+    id serial primary key,
+    dry_run bool not null,
+    files_scanned int not null default 0,
+    files_repaired int not null default 0,
+    files_deleted int not null default 0,
+    orphans_found int not null default 0,
+    errors int not null default 0,
+    started_at timestamptz not null default current_timestamp,
+    finished_at timestamptz
+);
+
+create index idx_reconciler_runs_started_at on "reconciler_runs" ("started_at");
+
+create index idx_file_registry_unsettled on "file_registry" ("id") where lock_status or upload_status <> 'SUCCESS';
@@ -0,0 +1,7 @@
+alter table "reconciler_runs" drop column if exists "objects_purged";
+
+alter table "reconciler_runs" drop column if exists "objects_quarantined";
+
+drop table if exists "object_gc_audit";
+
+drop table if exists "quarantined_objects";
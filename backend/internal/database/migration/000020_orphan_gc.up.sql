@@ -0,0 +1,34 @@
+-- user_id carries no foreign key, quarantined objects and their audit trail outlive a purged account
+create table "quarantined_objects" (
+    id serial primary key,
+    user_id int not null,
+    original_key varchar(300) not null,
+    quarantine_key varchar(350) not null,
+    size_bytes bigint not null,
+    quarantined_at timestamptz not null default current_timestamp,
+    purge_after timestamptz not null,
+    purged_at timestamptz
+);
+
+create unique index idx_quarantined_objects_key on "quarantined_objects" ("quarantine_key");
+
+create index idx_quarantined_objects_purge on "quarantined_objects" ("purge_after") where purged_at is null;
+
+create index idx_quarantined_objects_user on "quarantined_objects" ("user_id");
+
+create table "object_gc_audit" (
+    id bigserial primary key,
+    run_id int,
+    action varchar(20) not null,
+    user_id int not null,
+    object_key varchar(300) not null,
+    quarantine_key varchar(350),
+    size_bytes bigint,
+    created_at timestamptz not null default current_timestamp
+);
+
+create index idx_object_gc_audit_user on "object_gc_audit" ("user_id", "id");
+
+alter table "reconciler_runs" add column "objects_quarantined" int not null default 0;
+
+alter table "reconciler_runs" add column "objects_purged" int not null default 0;
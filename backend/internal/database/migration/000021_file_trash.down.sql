@@ -0,0 +1,25 @@
+drop index if exists idx_file_registry_trashed;
+
+-- trashed files would collide with live ones of the same name, they are purged and their usage released
+with deleted as (
+    delete from "file_registry"
+    where deleted_at is not null
+    returning user_id, size_bytes
+)
+update "user_usage"
+set
+    storage_bytes = user_usage.storage_bytes - released.storage_bytes,
+    file_count = user_usage.file_count - released.file_count,
+    updated_at = current_timestamp
+from (
+    select user_id, coalesce(sum(size_bytes), 0) as storage_bytes, count(*) as file_count
+    from deleted
+    group by user_id
+) released
+where user_usage.user_id = released.user_id;
+
+drop index if exists idx_unique_filename;
+
+create unique index idx_unique_filename on "file_registry" ("file_name", "user_id");
+
+alter table "file_registry" drop column if exists "deleted_at";
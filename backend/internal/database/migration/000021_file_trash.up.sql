@@ -0,0 +1,8 @@
+alter table "file_registry" add column "deleted_at" timestamptz;
+
+-- a trashed file keeps its name until it is purged, only live files have to be unique
+drop index if exists idx_unique_filename;
+
+create unique index idx_unique_filename on "file_registry" ("file_name", "user_id") where deleted_at is null;
+
+create index idx_file_registry_trashed on "file_registry" ("deleted_at") where deleted_at is not null;
@@ -0,0 +1,7 @@
+drop table if exists "file_tags";
+
+drop table if exists "tags";
+
+alter table "file_registry" drop column if exists "collection_id";
+
+drop table if exists "collections";
@@ -0,0 +1,50 @@
+create table "collections" (
+    id serial primary key,
+    user_id int not null,
+    parent_id int,
+    name varchar(100) not null,
+    created_at timestamptz default current_timestamp,
+    updated_at timestamptz default current_timestamp
+);
+
+alter table "collections" add constraint "fk_user_collections" foreign key ("user_id") references "users" ("id") on update cascade on delete restrict;
+
+-- no action instead of restrict, purging an account removes a whole tree in one statement
+alter table "collections" add constraint "fk_parent_collections" foreign key ("parent_id") references "collections" ("id") on update cascade;
+
+-- root collections have no parent, coalesce keeps their names unique as well
+create unique index idx_unique_collection_name on "collections" ("user_id", coalesce("parent_id", 0), "name");
+
+create index idx_collections_parent on "collections" ("parent_id");
+
+alter table "file_registry" add column "collection_id" int;
+
+-- files of a deleted collection fall back to the root
+alter table "file_registry" add constraint "fk_collection_file_registry" foreign key ("collection_id") references "collections" ("id") on update cascade on delete set null;
+
+create index idx_file_registry_collection on "file_registry" ("collection_id") where collection_id is not null;
+
+create table "tags" (
+    id serial primary key,
+    user_id int not null,
+    name varchar(50) not null,
+    created_at timestamptz default current_timestamp
+);
+
+alter table "tags" add constraint "fk_user_tags" foreign key ("user_id") references "users" ("id") on update cascade on delete restrict;
+
+-- tags are matched case insensitively, their spelling is kept as first entered
+create unique index idx_unique_tag_name on "tags" ("user_id", lower("name"));
+
+create table "file_tags" (
+    file_id int not null,
+    tag_id int not null,
+    created_at timestamptz default current_timestamp,
+    primary key ("file_id", "tag_id")
+);
+
+alter table "file_tags" add constraint "fk_file_file_tags" foreign key ("file_id") references "file_registry" ("id") on update cascade on delete cascade;
+
+alter table "file_tags" add constraint "fk_tag_file_tags" foreign key ("tag_id") references "tags" ("id") on update cascade on delete cascade;
+
+create index idx_file_tags_tag on "file_tags" ("tag_id");
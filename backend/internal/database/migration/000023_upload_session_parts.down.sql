@@ -0,0 +1 @@
+alter table "upload_sessions" drop column if exists "part_keys";
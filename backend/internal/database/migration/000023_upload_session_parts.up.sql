@@ -0,0 +1,7 @@
+-- every chunk is written under its own key, only the parts recorded here make up the upload
+alter table "upload_sessions" add column "part_keys" text[] not null default '{}';
+
+-- parts received before this change were not recorded and cannot be assembled safely, those uploads restart
+update "upload_sessions"
+set expires_at = current_timestamp
+where status = 'ACTIVE' and upload_method = 'RESUMABLE' and upload_offset > 0;
@@ -0,0 +1 @@
+drop index if exists idx_upload_sessions_object_key;
@@ -0,0 +1,2 @@
+-- signed url requests of the local store find their session through the key they write to
+create index idx_upload_sessions_object_key on "upload_sessions" ("object_key");
@@ -0,0 +1,106 @@
+-- name: MarkUserDeleted :one
+update users
+set
+    deleted_at = current_timestamp,
+    updated_at = current_timestamp
+where id = sqlc.arg(id) and deleted_at is null
+returning *;
+
+-- name: CreateAccountDeletion :one
+insert into account_deletions (
+    user_id,
+    email_hash,
+    status
+) values (
+    $1, $2, $3
+) returning *;
+
+-- name: ClaimAccountDeletion :one
+-- a lease instead of a row lock, the purge talks to the object store for far longer than a transaction should stay open
+update account_deletions
+set
+    attempts = attempts + 1,
+    lease_expires_at = sqlc.arg(lease_expires_at)
+where id = (
+    select pending.id from account_deletions pending
+    where
+        pending.status = sqlc.arg(status)
+        and (pending.lease_expires_at is null or pending.lease_expires_at < current_timestamp)
+    order by pending.id
+    for update skip locked
+    limit 1
+)
+returning *;
+
+-- name: RecordAccountDeletionFailure :exec
+update account_deletions
+set
+    last_error = sqlc.arg(last_error),
+    lease_expires_at = null
+where id = sqlc.arg(id);
+
+-- name: AddDeletedObjects :exec
+update account_deletions
+set objects_deleted = objects_deleted + sqlc.arg(objects)::int
+where id = sqlc.arg(id);
+
+-- name: CompleteAccountDeletion :exec
+update account_deletions
+set
+    status = sqlc.arg(status),
+    files_deleted = sqlc.arg(files_deleted),
+    jobs_deleted = sqlc.arg(jobs_deleted),
+    last_error = null,
+    lease_expires_at = null,
+    completed_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: LockUserFiles :exec
+update file_registry
+set
+    lock_status = sqlc.arg(lock_status),
+    updated_at = current_timestamp
+where user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserUsageEvents :exec
+delete from usage_events
+where user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserTranscriptJobs :execrows
+delete from transcript_jobs
+where user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserFiles :execrows
+delete from file_registry
+where user_id = sqlc.arg(user_id);
+
+-- name: DeleteUserEmailVerifications :exec
+delete from email_verifications
+where email = sqlc.arg(email);
+
+-- name: DeleteUser :exec
+delete from users
+where id = sqlc.arg(id);
+
+-- name: ListExportFiles :many
+select
+    id, file_name, object_key, upload_status, lock_status, content_hash, size_bytes,
+    audio_format, codec, duration_ms, sample_rate, channels, created_at, updated_at, deleted_at, collection_id,
+    array(
+        select t.name from file_tags ft
+        join tags t on t.id = ft.tag_id
+        where ft.file_id = file_registry.id
+        order by lower(t.name)
+    )::text[] as tags
+from file_registry
+where file_registry.user_id = sqlc.arg(user_id)
+order by file_registry.id;
+
+-- name: ListExportTranscriptJobs :many
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where j.user_id = sqlc.arg(user_id)
+order by j.id;
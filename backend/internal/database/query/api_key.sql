@@ -1,16 +1,63 @@
 -- name: CreateAPIKey :one
 insert into api_keys (
     user_id,
-    credential,
-    signature
+    key_id,
+    secret_hash,
+    signature,
+    name,
+    scopes,
+    expires_at,
+    signing_key_id
 ) values (
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) returning *;
 
 -- name: GetAPIKey :one
-select user_id, signature from api_keys
-where credential = sqlc.arg('credential');
+select id, user_id, secret_hash, signature, signing_key_id, scopes, expires_at from api_keys
+where key_id = sqlc.arg(key_id)::varchar;
 
--- name: DeleteAPIKey :exec
+-- name: GetAPIKeyStatus :one
+select user_id, expires_at from api_keys
+where id = sqlc.arg(id);
+
+-- name: GetLegacyAPIKey :one
+select id, user_id, secret_hash, signature, signing_key_id, scopes, expires_at from api_keys
+where secret_hash = sqlc.arg(secret_hash) and key_id is null;
+
+-- name: ListAPIKeys :many
+select id, key_id, name, scopes, expires_at, last_used_at, created_at from api_keys
+where user_id = sqlc.arg(user_id)
+order by created_at, id;
+
+-- name: GetAPIKeyByID :one
+select id, key_id, name, scopes, expires_at, last_used_at, created_at from api_keys
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: TouchAPIKey :exec
+update api_keys
+set last_used_at = current_timestamp
+where
+    id = sqlc.arg(id)
+    and (last_used_at is null or last_used_at < current_timestamp - interval '1 minute');
+
+-- name: DeleteAPIKeyByID :execrows
+delete from api_keys
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: ListAPIKeysForResign :many
+select id, secret_hash from api_keys
+where signing_key_id <> sqlc.arg(signing_key_id)
+order by id
+for update;
+
+-- name: UpdateAPIKeySignature :exec
+update api_keys
+set
+    signature = sqlc.arg(signature),
+    signing_key_id = sqlc.arg(signing_key_id),
+    updated_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: DeleteAPIKeysByUser :execrows
 delete from api_keys
-where credential = sqlc.arg('credential');
\ No newline at end of file
+where user_id = sqlc.arg(user_id);
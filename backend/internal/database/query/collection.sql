@@ -0,0 +1,81 @@
+-- name: CreateCollection :one
+insert into collections (
+    user_id,
+    parent_id,
+    name
+) values (
+    $1, $2, $3
+) returning *;
+
+-- name: GetCollection :one
+select * from collections
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: GetCollectionByLocking :one
+select * from collections
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+for update;
+
+-- name: ListCollections :many
+select
+    c.id,
+    c.parent_id,
+    c.name,
+    c.created_at,
+    c.updated_at,
+    (
+        select count(*) from file_registry f
+        where f.collection_id = c.id and f.deleted_at is null
+    ) as file_count
+from collections c
+where c.user_id = sqlc.arg(user_id)
+order by c.parent_id nulls first, c.name, c.id;
+
+-- name: UpdateCollection :one
+update collections
+set
+    name = sqlc.arg(name),
+    parent_id = sqlc.narg(parent_id),
+    updated_at = current_timestamp
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+returning *;
+
+-- name: CountChildCollections :one
+select count(*) from collections
+where parent_id = sqlc.arg(parent_id);
+
+-- name: DeleteCollection :exec
+delete from collections
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: ListCollectionAncestry :many
+-- the collection and every collection above it, the root first
+with recursive ancestry as (
+    select c.id, c.parent_id, 1 as depth
+    from collections c
+    where c.id = sqlc.arg(id) and c.user_id = sqlc.arg(user_id)
+    union all
+    select p.id, p.parent_id, ancestry.depth + 1
+    from collections p
+    join ancestry on p.id = ancestry.parent_id
+)
+select ancestry.id from ancestry
+order by ancestry.depth desc;
+
+-- name: ListCollectionSubtree :many
+-- the collection and every collection below it, depth counts the collection itself as 1
+with recursive subtree as (
+    select c.id, 1 as depth
+    from collections c
+    where c.id = sqlc.arg(id) and c.user_id = sqlc.arg(user_id)
+    union all
+    select c.id, subtree.depth + 1
+    from collections c
+    join subtree on c.parent_id = subtree.id
+)
+select subtree.id, subtree.depth::int as depth from subtree
+order by subtree.depth, subtree.id;
+
+-- name: DeleteUserCollections :exec
+delete from collections
+where user_id = sqlc.arg(user_id);
@@ -0,0 +1,38 @@
+-- name: CreateEmailVerification :one
+insert into email_verifications (
+    email,
+    purpose,
+    code_hash,
+    expires_at
+) values (
+    $1, $2, $3, $4
+) returning *;
+
+-- name: GetPendingEmailVerification :one
+select * from email_verifications
+where
+    email = sqlc.arg(email)
+    and purpose = sqlc.arg(purpose)
+    and consumed_at is null
+    and expires_at > current_timestamp
+order by id desc
+limit 1;
+
+-- name: IncrementEmailVerificationAttempts :one
+update email_verifications
+set attempts = attempts + 1
+where id = sqlc.arg(id)
+returning attempts;
+
+-- name: ConsumeEmailVerification :execrows
+update email_verifications
+set consumed_at = current_timestamp
+where
+    id = sqlc.arg(id)
+    and consumed_at is null
+    and expires_at > current_timestamp
+    and attempts < sqlc.arg(max_attempts)::int;
+
+-- name: DeletePendingEmailVerifications :exec
+delete from email_verifications
+where email = sqlc.arg(email) and purpose = sqlc.arg(purpose) and consumed_at is null;
@@ -12,14 +12,37 @@ insert into encryption_keys (
 select id, public_key, private_key, created_at from encryption_keys
 where is_active = 'true' and purpose = sqlc.arg('purpose');
 
+-- name: GetActiveKeyIDBasedOnPurpose :one
+select id from encryption_keys
+where is_active = 'true' and purpose = sqlc.arg('purpose');
+
+-- name: LockActiveKeyBasedOnPurpose :one
+select id from encryption_keys
+where is_active = 'true' and purpose = sqlc.arg('purpose')
+for update;
+
+-- name: GetVerificationKey :one
+select id, public_key, retires_at from encryption_keys
+where
+    id = sqlc.arg('id')
+    and purpose = sqlc.arg('purpose')
+    and (retires_at is null or retires_at > current_timestamp);
+
+-- name: ListVerificationKeys :many
+select id, public_key, is_active, retires_at, created_at from encryption_keys
+where
+    purpose = sqlc.arg('purpose')
+    and (retires_at is null or retires_at > current_timestamp)
+order by id;
+
 -- name: UpdateKeyStatus :exec
 update encryption_keys
 set
     is_active = coalesce(sqlc.narg('is_active'), is_active),
+    retires_at = coalesce(sqlc.narg('retires_at'), retires_at),
     updated_at = current_timestamp
 where
-    id = sqlc.arg('id')
-returning *;
+    id = sqlc.arg('id');
 
 -- name: CountEncryptionKeys :one
-select count(*) from encryption_keys;
\ No newline at end of file
+select count(*) from encryption_keys;
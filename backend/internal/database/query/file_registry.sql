@@ -3,46 +3,143 @@ insert into file_registry (
     user_id,
     file_name,
     lock_status,
-    upload_status
+    upload_status,
+    audio_format,
+    codec,
+    duration_ms,
+    sample_rate,
+    channels,
+    size_bytes
 ) values (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) returning *;
 
 -- name: GetFileByID :one
-select upload_status, lock_status, updated_at
+select upload_status, lock_status, updated_at, object_key, duration_ms
 from file_registry
-where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null
+for update;
+
+-- name: GetFile :one
+select * from file_registry
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null;
+
+-- name: GetFileByContentHash :one
+-- a trashed file can still lend its object to a new upload, purging it keeps the object while it is referenced
+select * from file_registry
+where
+    user_id = sqlc.arg(user_id)
+    and content_hash = sqlc.arg(content_hash)
+    and id <> sqlc.arg(id)
+    and upload_status = sqlc.arg(upload_status)
+    and lock_status = sqlc.arg(lock_status)
+    and (sqlc.arg(include_trashed)::boolean or deleted_at is null)
+order by id
+limit 1
+for update;
+
+-- name: LockObjectKeyReferences :many
+select id from file_registry
+where user_id = sqlc.arg(user_id) and object_key = sqlc.arg(object_key)
 for update;
 
 -- name: GetFileByName :one
 select * from file_registry
-where file_name = sqlc.arg(file_name) and user_id = sqlc.arg(user_id);
+where file_name = sqlc.arg(file_name) and user_id = sqlc.arg(user_id) and deleted_at is null;
 
 -- name: GetFileByNameByLocking :one
 select * from file_registry
 where
     file_name = sqlc.arg(file_name)
     and user_id = sqlc.arg(user_id)
+    and deleted_at is null
 for update;
 
--- name: ListAllFiles :many
-select id, file_name from file_registry
+-- name: ListFiles :many
+select
+    f.id,
+    f.file_name,
+    f.upload_status,
+    f.size_bytes,
+    f.audio_format,
+    f.codec,
+    f.duration_ms,
+    f.sample_rate,
+    f.channels,
+    f.created_at,
+    f.updated_at,
+    f.collection_id,
+    array(
+        select t.name from file_tags ft
+        join tags t on t.id = ft.tag_id
+        where ft.file_id = f.id
+        order by lower(t.name)
+    )::text[] as tags,
+    latest_job.id as latest_job_id,
+    latest_job.status as latest_job_status
+from file_registry f
+left join transcript_jobs latest_job on latest_job.id = (
+    select j.id
+    from transcript_jobs j
+    where j.file_id = f.id
+    order by j.created_at desc, j.id desc
+    limit 1
+)
 where
-    user_id = sqlc.arg(user_id)
-    and
-    upload_status = sqlc.arg(upload_status)
-    and
-    lock_status = sqlc.arg(lock_status);
-
--- name: ListConflictingFiles :many
-select id, object_key from file_registry
-where ((lock_status = sqlc.arg(first_lock_condition) AND upload_status = sqlc.arg(first_upload_status)) OR
-    (lock_status = sqlc.arg(second_lock_condition) AND upload_status = sqlc.arg(second_upload_status)) OR
-    (lock_status = sqlc.arg(third_lock_condition) AND upload_status = sqlc.arg(third_upload_status)) OR
-    (lock_status = sqlc.arg(fourth_lock_condition) AND upload_status = sqlc.arg(fourth_upload_status)) OR
-    (lock_status = sqlc.arg(fifth_lock_condition) AND upload_status = sqlc.arg(fifth_upload_status)))
-    AND
-    user_id = sqlc.arg(user_id);
+    f.user_id = sqlc.arg(user_id)
+    and f.deleted_at is null
+    and (
+        (
+            sqlc.narg(upload_status)::varchar is null
+            and f.upload_status = sqlc.arg(default_upload_status)
+            and f.lock_status = sqlc.arg(default_lock_status)
+        )
+        or f.upload_status = sqlc.narg(upload_status)::varchar
+    )
+    and (sqlc.narg(name_prefix)::varchar is null or f.file_name like sqlc.narg(name_prefix)::varchar || '%')
+    and (sqlc.narg(collection_ids)::int[] is null or f.collection_id = any(sqlc.narg(collection_ids)::int[]))
+    and (
+        sqlc.narg(tags)::text[] is null
+        or (
+            select count(*) from file_tags ft
+            join tags t on t.id = ft.tag_id
+            where ft.file_id = f.id and lower(t.name) in (select lower(n) from unnest(sqlc.narg(tags)::text[]) as n)
+        ) = (select count(distinct lower(n)) from unnest(sqlc.narg(tags)::text[]) as n)
+    )
+    and (sqlc.narg(created_after)::timestamptz is null or f.created_at >= sqlc.narg(created_after)::timestamptz)
+    and (sqlc.narg(created_before)::timestamptz is null or f.created_at < sqlc.narg(created_before)::timestamptz)
+    and (
+        sqlc.narg(has_transcript)::bool is null
+        or exists (
+            select 1 from transcript_jobs t
+            where t.file_id = f.id and t.status = sqlc.arg(succeeded_status)
+        ) = sqlc.narg(has_transcript)::bool
+    )
+    and (
+        sqlc.narg(cursor_id)::int is null
+        or (sqlc.arg(sort_by)::varchar = 'created_at' and not sqlc.arg(sort_desc)::bool
+            and (f.created_at, f.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::int))
+        or (sqlc.arg(sort_by)::varchar = 'created_at' and sqlc.arg(sort_desc)::bool
+            and (f.created_at, f.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::int))
+        or (sqlc.arg(sort_by)::varchar = 'file_name' and not sqlc.arg(sort_desc)::bool
+            and (f.file_name, f.id) > (sqlc.narg(cursor_file_name)::varchar, sqlc.narg(cursor_id)::int))
+        or (sqlc.arg(sort_by)::varchar = 'file_name' and sqlc.arg(sort_desc)::bool
+            and (f.file_name, f.id) < (sqlc.narg(cursor_file_name)::varchar, sqlc.narg(cursor_id)::int))
+        or (sqlc.arg(sort_by)::varchar = 'size' and not sqlc.arg(sort_desc)::bool
+            and (coalesce(f.size_bytes, 0), f.id) > (sqlc.narg(cursor_size)::bigint, sqlc.narg(cursor_id)::int))
+        or (sqlc.arg(sort_by)::varchar = 'size' and sqlc.arg(sort_desc)::bool
+            and (coalesce(f.size_bytes, 0), f.id) < (sqlc.narg(cursor_size)::bigint, sqlc.narg(cursor_id)::int))
+    )
+order by
+    case when sqlc.arg(sort_by)::varchar = 'created_at' and not sqlc.arg(sort_desc)::bool then f.created_at end asc,
+    case when sqlc.arg(sort_by)::varchar = 'created_at' and sqlc.arg(sort_desc)::bool then f.created_at end desc,
+    case when sqlc.arg(sort_by)::varchar = 'file_name' and not sqlc.arg(sort_desc)::bool then f.file_name end asc,
+    case when sqlc.arg(sort_by)::varchar = 'file_name' and sqlc.arg(sort_desc)::bool then f.file_name end desc,
+    case when sqlc.arg(sort_by)::varchar = 'size' and not sqlc.arg(sort_desc)::bool then coalesce(f.size_bytes, 0) end asc,
+    case when sqlc.arg(sort_by)::varchar = 'size' and sqlc.arg(sort_desc)::bool then coalesce(f.size_bytes, 0) end desc,
+    case when not sqlc.arg(sort_desc)::bool then f.id end asc,
+    case when sqlc.arg(sort_desc)::bool then f.id end desc
+limit sqlc.arg(page_limit);
 
 -- name: UpdateFileMetadata :one
 update file_registry
@@ -50,6 +147,7 @@ set
     object_key = sqlc.arg(object_key),
     upload_status = sqlc.arg(upload_status),
     lock_status = sqlc.arg(lock_status),
+    content_hash = sqlc.arg(content_hash),
     updated_at = current_timestamp
 where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
 returning *;
@@ -59,21 +157,103 @@ update file_registry
 set
     file_name = sqlc.arg(new_file_name),
     updated_at = current_timestamp
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null
+returning *;
+
+-- name: UpdateFileAudioMetadata :exec
+update file_registry
+set
+    audio_format = sqlc.arg(audio_format),
+    codec = sqlc.arg(codec),
+    duration_ms = sqlc.arg(duration_ms),
+    sample_rate = sqlc.arg(sample_rate),
+    channels = sqlc.arg(channels),
+    updated_at = current_timestamp
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: SetFileCollection :one
+update file_registry
+set
+    collection_id = sqlc.narg(collection_id),
+    updated_at = current_timestamp
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null
+returning *;
+
+-- name: ListCollectionFilesForTranscript :many
+select f.id, f.file_name, f.object_key
+from file_registry f
+where
+    f.user_id = sqlc.arg(user_id)
+    and f.collection_id = any(sqlc.arg(collection_ids)::int[])
+    and f.deleted_at is null
+    and f.upload_status = sqlc.arg(upload_status)
+    and f.lock_status = sqlc.arg(lock_status)
+    and not exists (
+        select 1 from transcript_jobs j
+        where j.file_id = f.id
+            and (
+                j.status in (sqlc.arg(queued_status)::varchar, sqlc.arg(processing_status)::varchar)
+                or (not sqlc.arg(include_transcribed)::bool and j.status = sqlc.arg(succeeded_status)::varchar)
+            )
+    )
+order by f.id
+limit sqlc.arg(page_limit);
+
+-- name: TrashFile :one
+update file_registry
+set
+    deleted_at = current_timestamp,
+    updated_at = current_timestamp
 where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
 returning *;
 
--- name: UnlockAndLockFile :one
+-- name: GetTrashedFileByLocking :one
+select * from file_registry
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is not null
+for update;
+
+-- name: RestoreFile :one
 update file_registry
 set
-    upload_status = sqlc.arg(status),
-    lock_status = sqlc.arg(lock_status),
+    deleted_at = null,
     updated_at = current_timestamp
 where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
 returning *;
 
+-- name: ListTrashedFiles :many
+select id, file_name, size_bytes, duration_ms, created_at, deleted_at
+from file_registry
+where user_id = sqlc.arg(user_id) and deleted_at is not null
+order by deleted_at desc, id desc
+limit sqlc.arg(page_limit)
+offset sqlc.arg(page_offset);
+
+-- name: ListExpiredTrashedFiles :many
+select id, user_id
+from file_registry
+where deleted_at < sqlc.arg(trashed_before)
+order by deleted_at, id
+limit sqlc.arg(page_limit);
+
+-- name: GetExpiredTrashedFileByLocking :one
+select object_key from file_registry
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at < sqlc.arg(trashed_before)
+for update;
+
 -- name: DeleteFiles :exec
-delete from file_registry
-where
-    user_id = sqlc.arg(user_id)
-    and
-    id = sqlc.arg(id);
\ No newline at end of file
+-- every way a file leaves the registry goes through here, so the usage counters are released with it
+with deleted as (
+    delete from file_registry
+    where
+        file_registry.user_id = sqlc.arg(user_id)
+        and
+        file_registry.id = sqlc.arg(id)
+    returning file_registry.user_id, file_registry.size_bytes
+)
+insert into user_usage (user_id, storage_bytes, file_count)
+select deleted.user_id, -coalesce(deleted.size_bytes, 0), -1 from deleted
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp;
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- name: EnqueueMessage :one
+insert into message_queue (
+    topic,
+    payload
+) values (
+    $1, $2
+) returning id;
+
+-- name: NotifyMessage :exec
+select pg_notify(sqlc.arg(channel)::text, sqlc.arg(payload)::text);
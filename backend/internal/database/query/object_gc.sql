@@ -0,0 +1,80 @@
+-- name: IsObjectKeyReferenced :one
+select (
+    exists (
+        select 1 from file_registry
+        where file_registry.user_id = sqlc.arg(user_id) and file_registry.object_key = sqlc.arg(object_key)::varchar
+    ) or exists (
+        select 1 from upload_sessions
+        where
+            upload_sessions.user_id = sqlc.arg(user_id)
+            and upload_sessions.status = sqlc.arg(active_status)
+            and upload_sessions.object_key = sqlc.arg(object_key)::varchar
+    ) or exists (
+        select 1 from upload_sessions
+        where
+            upload_sessions.user_id = sqlc.arg(user_id)
+            and upload_sessions.status = sqlc.arg(active_status)
+            and upload_sessions.expires_at > current_timestamp
+            and starts_with(sqlc.arg(object_key)::varchar, upload_sessions.object_key || '.')
+    )
+)::boolean as referenced;
+
+-- name: CreateQuarantinedObject :one
+insert into quarantined_objects (
+    user_id,
+    original_key,
+    quarantine_key,
+    size_bytes,
+    purge_after
+) values (
+    $1, $2, $3, $4, $5
+) returning *;
+
+-- name: DeleteQuarantinedObject :exec
+delete from quarantined_objects
+where id = sqlc.arg(id);
+
+-- name: ListExpiredQuarantinedObjects :many
+select * from quarantined_objects
+where purged_at is null and purge_after < current_timestamp
+order by id
+limit sqlc.arg(page_limit);
+
+-- name: MarkQuarantinedObjectPurged :exec
+update quarantined_objects
+set purged_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: ExpireUserQuarantine :exec
+update quarantined_objects
+set purge_after = current_timestamp
+where user_id = sqlc.arg(user_id) and purged_at is null;
+
+-- name: ListQuarantinedObjects :many
+select * from quarantined_objects
+where
+    purged_at is null
+    and
+    (sqlc.narg(user_id)::int is null or user_id = sqlc.narg(user_id)::int)
+order by id desc
+limit sqlc.arg(page_limit)
+offset sqlc.arg(page_offset);
+
+-- name: CreateGCAuditEntry :exec
+insert into object_gc_audit (
+    run_id,
+    action,
+    user_id,
+    object_key,
+    quarantine_key,
+    size_bytes
+) values (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListGCAuditEntries :many
+select * from object_gc_audit
+where (sqlc.narg(user_id)::int is null or user_id = sqlc.narg(user_id)::int)
+order by id desc
+limit sqlc.arg(page_limit)
+offset sqlc.arg(page_offset);
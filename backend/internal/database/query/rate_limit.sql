@@ -0,0 +1,27 @@
+-- name: TakeRateLimitToken :one
+-- refills the bucket for the time elapsed since its last use and takes cost tokens when that many are available
+insert into rate_limit_buckets (
+    key,
+    tokens,
+    allowed,
+    updated_at
+) values (
+    sqlc.arg(key),
+    case when sqlc.arg(burst)::float8 >= sqlc.arg(cost)::float8 then sqlc.arg(burst)::float8 - sqlc.arg(cost)::float8 else sqlc.arg(burst)::float8 end,
+    sqlc.arg(burst)::float8 >= sqlc.arg(cost)::float8,
+    current_timestamp
+)
+on conflict (key) do update
+set
+    tokens = case
+        when least(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * sqlc.arg(rate)::float8) >= sqlc.arg(cost)::float8
+        then least(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * sqlc.arg(rate)::float8) - sqlc.arg(cost)::float8
+        else least(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * sqlc.arg(rate)::float8)
+    end,
+    allowed = least(sqlc.arg(burst)::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * sqlc.arg(rate)::float8) >= sqlc.arg(cost)::float8,
+    updated_at = current_timestamp
+returning tokens, allowed;
+
+-- name: DeleteStaleRateLimitBuckets :execrows
+delete from rate_limit_buckets
+where updated_at < sqlc.arg(before);
@@ -0,0 +1,102 @@
+-- name: LockReconcilerRuns :exec
+-- held until the transaction ends, it serialises instances deciding whether a pass is due
+select pg_advisory_xact_lock(hashtext('reconciler_runs'));
+
+-- name: CountReconcilerRunsSince :one
+select count(*) from reconciler_runs
+where started_at > sqlc.arg(since);
+
+-- name: CreateReconcilerRun :one
+insert into reconciler_runs (
+    dry_run
+) values (
+    $1
+) returning *;
+
+-- name: FinishReconcilerRun :exec
+update reconciler_runs
+set
+    files_scanned = sqlc.arg(files_scanned),
+    files_repaired = sqlc.arg(files_repaired),
+    files_deleted = sqlc.arg(files_deleted),
+    orphans_found = sqlc.arg(orphans_found),
+    objects_quarantined = sqlc.arg(objects_quarantined),
+    objects_purged = sqlc.arg(objects_purged),
+    errors = sqlc.arg(errors),
+    finished_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: ListReconcilerRuns :many
+select * from reconciler_runs
+order by started_at desc, id desc
+limit sqlc.arg(page_limit);
+
+-- name: ListStuckFiles :many
+-- every state other than unlocked SUCCESS is transient, rows left in one past stuck_before were abandoned by their request
+select f.id, f.user_id, f.object_key, f.upload_status, f.lock_status, f.updated_at
+from file_registry f
+join users u on u.id = f.user_id
+where
+    (f.lock_status or f.upload_status <> sqlc.arg(success_status))
+    and f.updated_at < sqlc.arg(stuck_before)
+    and f.id > sqlc.arg(after_id)
+    and u.deleted_at is null
+    and not exists (
+        select 1 from upload_sessions s
+        where
+            s.file_id = f.id
+            and s.status = sqlc.arg(active_session_status)
+            and s.expires_at > current_timestamp
+    )
+order by f.id
+limit sqlc.arg(page_limit);
+
+-- name: RepairStuckFile :execrows
+update file_registry
+set
+    upload_status = sqlc.arg(upload_status),
+    lock_status = sqlc.arg(lock_status),
+    updated_at = current_timestamp
+where id = sqlc.arg(id) and updated_at = sqlc.arg(updated_at);
+
+-- name: DeleteStuckFile :execrows
+-- the updated_at guard leaves the row alone when a request picked it up after the scan
+with deleted as (
+    delete from file_registry
+    where
+        file_registry.id = sqlc.arg(id)
+        and
+        file_registry.updated_at = sqlc.arg(updated_at)
+    returning file_registry.user_id, file_registry.size_bytes
+)
+insert into user_usage (user_id, storage_bytes, file_count)
+select deleted.user_id, -coalesce(deleted.size_bytes, 0), -1 from deleted
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp;
+
+-- name: ListActiveUserIDs :many
+select id from users
+where id > sqlc.arg(after_id) and deleted_at is null
+order by id
+limit sqlc.arg(page_limit);
+
+-- name: ListReferencedObjectKeys :many
+-- objects of uploads still in flight are referenced by their session until the registry row takes the key over,
+-- a finished session no longer protects its key since a client can still write to it until the signed url expires
+select object_key::varchar from file_registry
+where file_registry.user_id = sqlc.arg(user_id) and object_key is not null
+union
+select object_key::varchar from upload_sessions
+where upload_sessions.user_id = sqlc.arg(user_id) and upload_sessions.status = sqlc.arg(active_status);
+
+-- name: ListActiveUploadPrefixes :many
+-- chunks of a resumable upload live under <object_key>.parts/ and the verified copy of an upload is written to
+-- <object_key>.<format> before the session completes, an active session keeps all of them
+select (object_key || '.')::varchar as upload_prefix from upload_sessions
+where
+    upload_sessions.user_id = sqlc.arg(user_id)
+    and upload_sessions.status = sqlc.arg(active_status)
+    and upload_sessions.expires_at > current_timestamp;
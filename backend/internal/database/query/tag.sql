@@ -0,0 +1,62 @@
+-- name: CreateTag :one
+insert into tags (
+    user_id,
+    name
+) values (
+    $1, $2
+) returning *;
+
+-- name: GetTagByLocking :one
+select * from tags
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+for update;
+
+-- name: ListTags :many
+select
+    t.id,
+    t.name,
+    t.created_at,
+    (
+        select count(*) from file_tags ft
+        join file_registry f on f.id = ft.file_id
+        where ft.tag_id = t.id and f.deleted_at is null
+    ) as file_count
+from tags t
+where t.user_id = sqlc.arg(user_id)
+order by lower(t.name), t.id;
+
+-- name: UpdateTagName :one
+update tags
+set name = sqlc.arg(name)
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+returning *;
+
+-- name: DeleteTag :execrows
+delete from tags
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: EnsureTags :exec
+-- tags are free-form, naming one on a file creates it
+insert into tags (user_id, name)
+select sqlc.arg(user_id), unnest(sqlc.arg(names)::text[])
+on conflict (user_id, lower(name)) do nothing;
+
+-- name: ListTagsByName :many
+select * from tags
+where
+    user_id = sqlc.arg(user_id)
+    and lower(name) in (select lower(n) from unnest(sqlc.arg(names)::text[]) as n)
+order by lower(name), id;
+
+-- name: DeleteFileTags :exec
+delete from file_tags
+where file_id = sqlc.arg(file_id);
+
+-- name: AddFileTags :exec
+insert into file_tags (file_id, tag_id)
+select sqlc.arg(file_id), unnest(sqlc.arg(tag_ids)::int[])
+on conflict do nothing;
+
+-- name: DeleteUserTags :exec
+delete from tags
+where user_id = sqlc.arg(user_id);
@@ -0,0 +1,55 @@
+-- name: CreateTranscriptJob :one
+insert into transcript_jobs (
+    user_id,
+    file_id,
+    status
+) values (
+    $1, $2, $3
+) returning *;
+
+-- name: GetTranscriptJob :one
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where j.id = sqlc.arg(id) and j.user_id = sqlc.arg(user_id);
+
+-- name: GetTranscriptJobByLocking :one
+select * from transcript_jobs
+where id = sqlc.arg(id)
+for update;
+
+-- name: ListTranscriptJobs :many
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where
+    j.user_id = sqlc.arg(user_id)
+    and
+    (sqlc.narg(status)::varchar is null or j.status = sqlc.narg(status)::varchar)
+order by j.created_at desc, j.id desc
+limit sqlc.arg(page_limit)
+offset sqlc.arg(page_offset);
+
+-- name: CountActiveTranscriptJobs :one
+select count(*) from transcript_jobs
+where
+    file_id = sqlc.arg(file_id)
+    and
+    status in (sqlc.arg(queued_status), sqlc.arg(processing_status));
+
+-- name: UpdateTranscriptJobStatus :one
+update transcript_jobs
+set
+    status = sqlc.arg(status),
+    error_message = sqlc.narg(error_message),
+    progress = coalesce(sqlc.narg(progress), progress),
+    transcript_object_key = coalesce(sqlc.narg(transcript_object_key), transcript_object_key),
+    started_at = coalesce(sqlc.narg(started_at), started_at),
+    completed_at = coalesce(sqlc.narg(completed_at), completed_at),
+    updated_at = current_timestamp
+where id = sqlc.arg(id)
+returning *;
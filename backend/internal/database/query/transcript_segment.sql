@@ -0,0 +1,30 @@
+-- name: CreateTranscriptSegments :copyfrom
+insert into transcript_segments (
+    job_id,
+    segment_index,
+    start_ms,
+    end_ms,
+    speaker,
+    content
+) values (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListTranscriptSegments :many
+select segment_index, start_ms, end_ms, speaker, content
+from transcript_segments
+where job_id = sqlc.arg(job_id)
+order by segment_index;
+
+-- name: GetLatestSucceededJobForFile :one
+select j.id, f.file_name
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where
+    j.file_id = sqlc.arg(file_id)
+    and
+    j.user_id = sqlc.arg(user_id)
+    and
+    j.status = sqlc.arg(status)
+order by j.completed_at desc, j.id desc
+limit 1;
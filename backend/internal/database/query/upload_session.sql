@@ -0,0 +1,46 @@
+-- name: CreateUploadSession :one
+insert into upload_sessions (
+    id,
+    user_id,
+    file_id,
+    object_key,
+    total_size,
+    status,
+    expires_at,
+    upload_method,
+    content_type
+) values (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) returning *;
+
+-- name: GetUploadSession :one
+select * from upload_sessions
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id);
+
+-- name: GetUploadSessionByObjectKey :one
+select * from upload_sessions
+where object_key = sqlc.arg(object_key)
+order by created_at desc
+limit 1;
+
+-- name: GetUploadSessionByLocking :one
+select * from upload_sessions
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id)
+for update;
+
+-- name: UpdateUploadSessionOffset :one
+update upload_sessions
+set
+    upload_offset = sqlc.arg(upload_offset),
+    part_keys = array_append(part_keys, sqlc.arg(part_key)::text),
+    updated_at = current_timestamp
+where id = sqlc.arg(id)
+returning *;
+
+-- name: UpdateUploadSessionStatus :one
+update upload_sessions
+set
+    status = sqlc.arg(status),
+    updated_at = current_timestamp
+where id = sqlc.arg(id)
+returning *;
@@ -0,0 +1,55 @@
+-- name: LockUserUsage :one
+-- creates the counters on first use, the no-op update takes the row lock which serialises quota checks of a user
+insert into user_usage (
+    user_id
+) values (
+    sqlc.arg(user_id)
+)
+on conflict (user_id) do update
+set user_id = excluded.user_id
+returning *;
+
+-- name: AddUserUsage :exec
+insert into user_usage (
+    user_id,
+    storage_bytes,
+    file_count
+) values (
+    sqlc.arg(user_id), sqlc.arg(storage_bytes), sqlc.arg(file_count)
+)
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp;
+
+-- name: GetUserUsage :one
+select * from user_usage
+where user_id = sqlc.arg(user_id);
+
+-- name: GetUserPlan :one
+select p.* from users u
+join plans p on p.name = u.plan
+where u.id = sqlc.arg(user_id);
+
+-- name: CreateUsageEvent :one
+insert into usage_events (
+    user_id,
+    kind,
+    file_id,
+    job_id,
+    quantity
+) values (
+    $1, $2, $3, $4, $5
+) returning *;
+
+-- name: SumUsageSince :one
+-- usage of failed transcript jobs is not billed
+select coalesce(sum(e.quantity), 0)::bigint
+from usage_events e
+left join transcript_jobs j on j.id = e.job_id
+where
+    e.user_id = sqlc.arg(user_id)
+    and e.kind = sqlc.arg(kind)
+    and e.created_at >= sqlc.arg(since)
+    and (j.status is null or j.status <> sqlc.arg(failed_status));
@@ -1,8 +1,9 @@
 -- name: CreateUsers :one
 insert into users (
-    email
+    email,
+    email_verified_at
 ) values (
-    $1
+    $1, current_timestamp
 ) returning *;
 
 -- name: GetUsersID :one
@@ -12,3 +13,57 @@ where email = sqlc.arg('email');
 -- name: GetUser :one
 select email from users
 where id = sqlc.arg('id');
+
+-- name: GetUserStatus :one
+select role, suspended_at, deleted_at from users
+where id = sqlc.arg(id);
+
+-- name: ListUsers :many
+select id, email, role, plan, email_verified_at, suspended_at, created_at from users
+where
+    (sqlc.narg(email)::varchar is null or email ilike '%' || sqlc.narg(email)::varchar || '%')
+    and
+    (sqlc.narg(role)::varchar is null or role = sqlc.narg(role)::varchar)
+    and
+    (sqlc.narg(suspended)::boolean is null or (suspended_at is not null) = sqlc.narg(suspended)::boolean)
+order by id
+limit sqlc.arg(page_limit)
+offset sqlc.arg(page_offset);
+
+-- name: GetUserDetails :one
+select id, email, role, plan, email_verified_at, suspended_at, suspension_reason, created_at, updated_at from users
+where id = sqlc.arg(id);
+
+-- name: SuspendUser :execrows
+update users
+set
+    suspended_at = coalesce(suspended_at, current_timestamp),
+    suspension_reason = sqlc.narg(reason),
+    updated_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: UnsuspendUser :execrows
+update users
+set
+    suspended_at = null,
+    suspension_reason = null,
+    updated_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: UpdateUserRole :execrows
+update users
+set
+    role = sqlc.arg(role),
+    updated_at = current_timestamp
+where id = sqlc.arg(id);
+
+-- name: PromoteUsersByEmail :many
+update users
+set
+    role = sqlc.arg(role),
+    updated_at = current_timestamp
+where
+    lower(email) = any(sqlc.arg(emails)::text[])
+    and role <> sqlc.arg(role)
+    and deleted_at is null
+returning id, email;
@@ -0,0 +1,382 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: account_deletion.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addDeletedObjects = `-- name: AddDeletedObjects :exec
+update account_deletions
+set objects_deleted = objects_deleted + $1::int
+where id = $2
+`
+
+type AddDeletedObjectsParams struct {
+	Objects int32 `json:"objects"`
+	ID      int32 `json:"id"`
+}
+
+func (q *Queries) AddDeletedObjects(ctx context.Context, arg AddDeletedObjectsParams) error {
+	_, err := q.db.Exec(ctx, addDeletedObjects, arg.Objects, arg.ID)
+	return err
+}
+
+const claimAccountDeletion = `-- name: ClaimAccountDeletion :one
+update account_deletions
+set
+    attempts = attempts + 1,
+    lease_expires_at = $1
+where id = (
+    select pending.id from account_deletions pending
+    where
+        pending.status = $2
+        and (pending.lease_expires_at is null or pending.lease_expires_at < current_timestamp)
+    order by pending.id
+    for update skip locked
+    limit 1
+)
+returning id, user_id, email_hash, status, attempts, last_error, objects_deleted, files_deleted, jobs_deleted, lease_expires_at, requested_at, completed_at
+`
+
+type ClaimAccountDeletionParams struct {
+	LeaseExpiresAt pgtype.Timestamptz `json:"lease_expires_at"`
+	Status         string             `json:"status"`
+}
+
+// a lease instead of a row lock, the purge talks to the object store for far longer than a transaction should stay open
+func (q *Queries) ClaimAccountDeletion(ctx context.Context, arg ClaimAccountDeletionParams) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, claimAccountDeletion, arg.LeaseExpiresAt, arg.Status)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EmailHash,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.ObjectsDeleted,
+		&i.FilesDeleted,
+		&i.JobsDeleted,
+		&i.LeaseExpiresAt,
+		&i.RequestedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const completeAccountDeletion = `-- name: CompleteAccountDeletion :exec
+update account_deletions
+set
+    status = $1,
+    files_deleted = $2,
+    jobs_deleted = $3,
+    last_error = null,
+    lease_expires_at = null,
+    completed_at = current_timestamp
+where id = $4
+`
+
+type CompleteAccountDeletionParams struct {
+	Status       string `json:"status"`
+	FilesDeleted int32  `json:"files_deleted"`
+	JobsDeleted  int32  `json:"jobs_deleted"`
+	ID           int32  `json:"id"`
+}
+
+func (q *Queries) CompleteAccountDeletion(ctx context.Context, arg CompleteAccountDeletionParams) error {
+	_, err := q.db.Exec(ctx, completeAccountDeletion,
+		arg.Status,
+		arg.FilesDeleted,
+		arg.JobsDeleted,
+		arg.ID,
+	)
+	return err
+}
+
+const createAccountDeletion = `-- name: CreateAccountDeletion :one
+insert into account_deletions (
+    user_id,
+    email_hash,
+    status
+) values (
+    $1, $2, $3
+) returning id, user_id, email_hash, status, attempts, last_error, objects_deleted, files_deleted, jobs_deleted, lease_expires_at, requested_at, completed_at
+`
+
+type CreateAccountDeletionParams struct {
+	UserID    int32  `json:"user_id"`
+	EmailHash []byte `json:"email_hash"`
+	Status    string `json:"status"`
+}
+
+func (q *Queries) CreateAccountDeletion(ctx context.Context, arg CreateAccountDeletionParams) (AccountDeletion, error) {
+	row := q.db.QueryRow(ctx, createAccountDeletion, arg.UserID, arg.EmailHash, arg.Status)
+	var i AccountDeletion
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EmailHash,
+		&i.Status,
+		&i.Attempts,
+		&i.LastError,
+		&i.ObjectsDeleted,
+		&i.FilesDeleted,
+		&i.JobsDeleted,
+		&i.LeaseExpiresAt,
+		&i.RequestedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+delete from users
+where id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserEmailVerifications = `-- name: DeleteUserEmailVerifications :exec
+delete from email_verifications
+where email = $1
+`
+
+func (q *Queries) DeleteUserEmailVerifications(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, deleteUserEmailVerifications, email)
+	return err
+}
+
+const deleteUserFiles = `-- name: DeleteUserFiles :execrows
+delete from file_registry
+where user_id = $1
+`
+
+func (q *Queries) DeleteUserFiles(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserFiles, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserTranscriptJobs = `-- name: DeleteUserTranscriptJobs :execrows
+delete from transcript_jobs
+where user_id = $1
+`
+
+func (q *Queries) DeleteUserTranscriptJobs(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserTranscriptJobs, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserUsageEvents = `-- name: DeleteUserUsageEvents :exec
+delete from usage_events
+where user_id = $1
+`
+
+func (q *Queries) DeleteUserUsageEvents(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteUserUsageEvents, userID)
+	return err
+}
+
+const listExportFiles = `-- name: ListExportFiles :many
+select
+    id, file_name, object_key, upload_status, lock_status, content_hash, size_bytes,
+    audio_format, codec, duration_ms, sample_rate, channels, created_at, updated_at, deleted_at, collection_id,
+    array(
+        select t.name from file_tags ft
+        join tags t on t.id = ft.tag_id
+        where ft.file_id = file_registry.id
+        order by lower(t.name)
+    )::text[] as tags
+from file_registry
+where file_registry.user_id = $1
+order by file_registry.id
+`
+
+type ListExportFilesRow struct {
+	ID           int32              `json:"id"`
+	FileName     string             `json:"file_name"`
+	ObjectKey    pgtype.Text        `json:"object_key"`
+	UploadStatus string             `json:"upload_status"`
+	LockStatus   bool               `json:"lock_status"`
+	ContentHash  pgtype.Text        `json:"content_hash"`
+	SizeBytes    pgtype.Int8        `json:"size_bytes"`
+	AudioFormat  pgtype.Text        `json:"audio_format"`
+	Codec        pgtype.Text        `json:"codec"`
+	DurationMs   pgtype.Int8        `json:"duration_ms"`
+	SampleRate   pgtype.Int4        `json:"sample_rate"`
+	Channels     pgtype.Int4        `json:"channels"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+	CollectionID pgtype.Int4        `json:"collection_id"`
+	Tags         []string           `json:"tags"`
+}
+
+func (q *Queries) ListExportFiles(ctx context.Context, userID int32) ([]ListExportFilesRow, error) {
+	rows, err := q.db.Query(ctx, listExportFiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListExportFilesRow{}
+	for rows.Next() {
+		var i ListExportFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.ObjectKey,
+			&i.UploadStatus,
+			&i.LockStatus,
+			&i.ContentHash,
+			&i.SizeBytes,
+			&i.AudioFormat,
+			&i.Codec,
+			&i.DurationMs,
+			&i.SampleRate,
+			&i.Channels,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.CollectionID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExportTranscriptJobs = `-- name: ListExportTranscriptJobs :many
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where j.user_id = $1
+order by j.id
+`
+
+type ListExportTranscriptJobsRow struct {
+	ID           int32              `json:"id"`
+	FileID       int32              `json:"file_id"`
+	FileName     string             `json:"file_name"`
+	Status       string             `json:"status"`
+	Progress     int32              `json:"progress"`
+	ErrorMessage pgtype.Text        `json:"error_message"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListExportTranscriptJobs(ctx context.Context, userID int32) ([]ListExportTranscriptJobsRow, error) {
+	rows, err := q.db.Query(ctx, listExportTranscriptJobs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListExportTranscriptJobsRow{}
+	for rows.Next() {
+		var i ListExportTranscriptJobsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileID,
+			&i.FileName,
+			&i.Status,
+			&i.Progress,
+			&i.ErrorMessage,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUserFiles = `-- name: LockUserFiles :exec
+update file_registry
+set
+    lock_status = $1,
+    updated_at = current_timestamp
+where user_id = $2
+`
+
+type LockUserFilesParams struct {
+	LockStatus bool  `json:"lock_status"`
+	UserID     int32 `json:"user_id"`
+}
+
+func (q *Queries) LockUserFiles(ctx context.Context, arg LockUserFilesParams) error {
+	_, err := q.db.Exec(ctx, lockUserFiles, arg.LockStatus, arg.UserID)
+	return err
+}
+
+const markUserDeleted = `-- name: MarkUserDeleted :one
+update users
+set
+    deleted_at = current_timestamp,
+    updated_at = current_timestamp
+where id = $1 and deleted_at is null
+returning id, email, created_at, updated_at, email_verified_at, plan, role, suspended_at, suspension_reason, deleted_at
+`
+
+func (q *Queries) MarkUserDeleted(ctx context.Context, id int32) (User, error) {
+	row := q.db.QueryRow(ctx, markUserDeleted, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.Plan,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const recordAccountDeletionFailure = `-- name: RecordAccountDeletionFailure :exec
+update account_deletions
+set
+    last_error = $1,
+    lease_expires_at = null
+where id = $2
+`
+
+type RecordAccountDeletionFailureParams struct {
+	LastError pgtype.Text `json:"last_error"`
+	ID        int32       `json:"id"`
+}
+
+func (q *Queries) RecordAccountDeletionFailure(ctx context.Context, arg RecordAccountDeletionFailureParams) error {
+	_, err := q.db.Exec(ctx, recordAccountDeletionFailure, arg.LastError, arg.ID)
+	return err
+}
@@ -7,61 +7,313 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAPIKey = `-- name: CreateAPIKey :one
 insert into api_keys (
     user_id,
-    credential,
-    signature
+    key_id,
+    secret_hash,
+    signature,
+    name,
+    scopes,
+    expires_at,
+    signing_key_id
 ) values (
-    $1, $2, $3
-) returning id, user_id, credential, signature, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) returning id, user_id, signature, created_at, updated_at, name, scopes, expires_at, last_used_at, key_id, secret_hash, signing_key_id
 `
 
 type CreateAPIKeyParams struct {
-	UserID     int32  `json:"user_id"`
-	Credential []byte `json:"credential"`
-	Signature  []byte `json:"signature"`
+	UserID       int32              `json:"user_id"`
+	KeyID        pgtype.Text        `json:"key_id"`
+	SecretHash   []byte             `json:"secret_hash"`
+	Signature    []byte             `json:"signature"`
+	Name         string             `json:"name"`
+	Scopes       []string           `json:"scopes"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	SigningKeyID int32              `json:"signing_key_id"`
 }
 
 func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
-	row := q.db.QueryRow(ctx, createAPIKey, arg.UserID, arg.Credential, arg.Signature)
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.UserID,
+		arg.KeyID,
+		arg.SecretHash,
+		arg.Signature,
+		arg.Name,
+		arg.Scopes,
+		arg.ExpiresAt,
+		arg.SigningKeyID,
+	)
 	var i ApiKey
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.Credential,
 		&i.Signature,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Name,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.KeyID,
+		&i.SecretHash,
+		&i.SigningKeyID,
 	)
 	return i, err
 }
 
-const deleteAPIKey = `-- name: DeleteAPIKey :exec
+const deleteAPIKeyByID = `-- name: DeleteAPIKeyByID :execrows
 delete from api_keys
-where credential = $1
+where id = $1 and user_id = $2
 `
 
-func (q *Queries) DeleteAPIKey(ctx context.Context, credential []byte) error {
-	_, err := q.db.Exec(ctx, deleteAPIKey, credential)
-	return err
+type DeleteAPIKeyByIDParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) DeleteAPIKeyByID(ctx context.Context, arg DeleteAPIKeyByIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAPIKeyByID, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAPIKeysByUser = `-- name: DeleteAPIKeysByUser :execrows
+delete from api_keys
+where user_id = $1
+`
+
+func (q *Queries) DeleteAPIKeysByUser(ctx context.Context, userID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAPIKeysByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAPIKey = `-- name: GetAPIKey :one
-select user_id, signature from api_keys
-where credential = $1
+select id, user_id, secret_hash, signature, signing_key_id, scopes, expires_at from api_keys
+where key_id = $1::varchar
 `
 
 type GetAPIKeyRow struct {
-	UserID    int32  `json:"user_id"`
-	Signature []byte `json:"signature"`
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	SecretHash   []byte             `json:"secret_hash"`
+	Signature    []byte             `json:"signature"`
+	SigningKeyID int32              `json:"signing_key_id"`
+	Scopes       []string           `json:"scopes"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
 }
 
-func (q *Queries) GetAPIKey(ctx context.Context, credential []byte) (GetAPIKeyRow, error) {
-	row := q.db.QueryRow(ctx, getAPIKey, credential)
+func (q *Queries) GetAPIKey(ctx context.Context, keyID string) (GetAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKey, keyID)
 	var i GetAPIKeyRow
-	err := row.Scan(&i.UserID, &i.Signature)
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.SecretHash,
+		&i.Signature,
+		&i.SigningKeyID,
+		&i.Scopes,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getAPIKeyByID = `-- name: GetAPIKeyByID :one
+select id, key_id, name, scopes, expires_at, last_used_at, created_at from api_keys
+where id = $1 and user_id = $2
+`
+
+type GetAPIKeyByIDParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+type GetAPIKeyByIDRow struct {
+	ID         int32              `json:"id"`
+	KeyID      pgtype.Text        `json:"key_id"`
+	Name       string             `json:"name"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) GetAPIKeyByID(ctx context.Context, arg GetAPIKeyByIDParams) (GetAPIKeyByIDRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByID, arg.ID, arg.UserID)
+	var i GetAPIKeyByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.KeyID,
+		&i.Name,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
 	return i, err
 }
+
+const getAPIKeyStatus = `-- name: GetAPIKeyStatus :one
+select user_id, expires_at from api_keys
+where id = $1
+`
+
+type GetAPIKeyStatusRow struct {
+	UserID    int32              `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) GetAPIKeyStatus(ctx context.Context, id int32) (GetAPIKeyStatusRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyStatus, id)
+	var i GetAPIKeyStatusRow
+	err := row.Scan(&i.UserID, &i.ExpiresAt)
+	return i, err
+}
+
+const getLegacyAPIKey = `-- name: GetLegacyAPIKey :one
+select id, user_id, secret_hash, signature, signing_key_id, scopes, expires_at from api_keys
+where secret_hash = $1 and key_id is null
+`
+
+type GetLegacyAPIKeyRow struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	SecretHash   []byte             `json:"secret_hash"`
+	Signature    []byte             `json:"signature"`
+	SigningKeyID int32              `json:"signing_key_id"`
+	Scopes       []string           `json:"scopes"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) GetLegacyAPIKey(ctx context.Context, secretHash []byte) (GetLegacyAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, getLegacyAPIKey, secretHash)
+	var i GetLegacyAPIKeyRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.SecretHash,
+		&i.Signature,
+		&i.SigningKeyID,
+		&i.Scopes,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+select id, key_id, name, scopes, expires_at, last_used_at, created_at from api_keys
+where user_id = $1
+order by created_at, id
+`
+
+type ListAPIKeysRow struct {
+	ID         int32              `json:"id"`
+	KeyID      pgtype.Text        `json:"key_id"`
+	Name       string             `json:"name"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListAPIKeys(ctx context.Context, userID int32) ([]ListAPIKeysRow, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAPIKeysRow{}
+	for rows.Next() {
+		var i ListAPIKeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.KeyID,
+			&i.Name,
+			&i.Scopes,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAPIKeysForResign = `-- name: ListAPIKeysForResign :many
+select id, secret_hash from api_keys
+where signing_key_id <> $1
+order by id
+for update
+`
+
+type ListAPIKeysForResignRow struct {
+	ID         int32  `json:"id"`
+	SecretHash []byte `json:"secret_hash"`
+}
+
+func (q *Queries) ListAPIKeysForResign(ctx context.Context, signingKeyID int32) ([]ListAPIKeysForResignRow, error) {
+	rows, err := q.db.Query(ctx, listAPIKeysForResign, signingKeyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAPIKeysForResignRow{}
+	for rows.Next() {
+		var i ListAPIKeysForResignRow
+		if err := rows.Scan(&i.ID, &i.SecretHash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+update api_keys
+set last_used_at = current_timestamp
+where
+    id = $1
+    and (last_used_at is null or last_used_at < current_timestamp - interval '1 minute')
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, id)
+	return err
+}
+
+const updateAPIKeySignature = `-- name: UpdateAPIKeySignature :exec
+update api_keys
+set
+    signature = $1,
+    signing_key_id = $2,
+    updated_at = current_timestamp
+where id = $3
+`
+
+type UpdateAPIKeySignatureParams struct {
+	Signature    []byte `json:"signature"`
+	SigningKeyID int32  `json:"signing_key_id"`
+	ID           int32  `json:"id"`
+}
+
+func (q *Queries) UpdateAPIKeySignature(ctx context.Context, arg UpdateAPIKeySignatureParams) error {
+	_, err := q.db.Exec(ctx, updateAPIKeySignature, arg.Signature, arg.SigningKeyID, arg.ID)
+	return err
+}
@@ -0,0 +1,301 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: collection.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countChildCollections = `-- name: CountChildCollections :one
+select count(*) from collections
+where parent_id = $1
+`
+
+func (q *Queries) CountChildCollections(ctx context.Context, parentID pgtype.Int4) (int64, error) {
+	row := q.db.QueryRow(ctx, countChildCollections, parentID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCollection = `-- name: CreateCollection :one
+insert into collections (
+    user_id,
+    parent_id,
+    name
+) values (
+    $1, $2, $3
+) returning id, user_id, parent_id, name, created_at, updated_at
+`
+
+type CreateCollectionParams struct {
+	UserID   int32       `json:"user_id"`
+	ParentID pgtype.Int4 `json:"parent_id"`
+	Name     string      `json:"name"`
+}
+
+func (q *Queries) CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, createCollection, arg.UserID, arg.ParentID, arg.Name)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCollection = `-- name: DeleteCollection :exec
+delete from collections
+where id = $1 and user_id = $2
+`
+
+type DeleteCollectionParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) DeleteCollection(ctx context.Context, arg DeleteCollectionParams) error {
+	_, err := q.db.Exec(ctx, deleteCollection, arg.ID, arg.UserID)
+	return err
+}
+
+const deleteUserCollections = `-- name: DeleteUserCollections :exec
+delete from collections
+where user_id = $1
+`
+
+func (q *Queries) DeleteUserCollections(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteUserCollections, userID)
+	return err
+}
+
+const getCollection = `-- name: GetCollection :one
+select id, user_id, parent_id, name, created_at, updated_at from collections
+where id = $1 and user_id = $2
+`
+
+type GetCollectionParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetCollection(ctx context.Context, arg GetCollectionParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, getCollection, arg.ID, arg.UserID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCollectionByLocking = `-- name: GetCollectionByLocking :one
+select id, user_id, parent_id, name, created_at, updated_at from collections
+where id = $1 and user_id = $2
+for update
+`
+
+type GetCollectionByLockingParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetCollectionByLocking(ctx context.Context, arg GetCollectionByLockingParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, getCollectionByLocking, arg.ID, arg.UserID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCollectionAncestry = `-- name: ListCollectionAncestry :many
+with recursive ancestry as (
+    select c.id, c.parent_id, 1 as depth
+    from collections c
+    where c.id = $1 and c.user_id = $2
+    union all
+    select p.id, p.parent_id, ancestry.depth + 1
+    from collections p
+    join ancestry on p.id = ancestry.parent_id
+)
+select ancestry.id from ancestry
+order by ancestry.depth desc
+`
+
+type ListCollectionAncestryParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+// the collection and every collection above it, the root first
+func (q *Queries) ListCollectionAncestry(ctx context.Context, arg ListCollectionAncestryParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listCollectionAncestry, arg.ID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCollectionSubtree = `-- name: ListCollectionSubtree :many
+with recursive subtree as (
+    select c.id, 1 as depth
+    from collections c
+    where c.id = $1 and c.user_id = $2
+    union all
+    select c.id, subtree.depth + 1
+    from collections c
+    join subtree on c.parent_id = subtree.id
+)
+select subtree.id, subtree.depth::int as depth from subtree
+order by subtree.depth, subtree.id
+`
+
+type ListCollectionSubtreeParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+type ListCollectionSubtreeRow struct {
+	ID    int32 `json:"id"`
+	Depth int32 `json:"depth"`
+}
+
+// the collection and every collection below it, depth counts the collection itself as 1
+func (q *Queries) ListCollectionSubtree(ctx context.Context, arg ListCollectionSubtreeParams) ([]ListCollectionSubtreeRow, error) {
+	rows, err := q.db.Query(ctx, listCollectionSubtree, arg.ID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCollectionSubtreeRow{}
+	for rows.Next() {
+		var i ListCollectionSubtreeRow
+		if err := rows.Scan(&i.ID, &i.Depth); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCollections = `-- name: ListCollections :many
+select
+    c.id,
+    c.parent_id,
+    c.name,
+    c.created_at,
+    c.updated_at,
+    (
+        select count(*) from file_registry f
+        where f.collection_id = c.id and f.deleted_at is null
+    ) as file_count
+from collections c
+where c.user_id = $1
+order by c.parent_id nulls first, c.name, c.id
+`
+
+type ListCollectionsRow struct {
+	ID        int32              `json:"id"`
+	ParentID  pgtype.Int4        `json:"parent_id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	FileCount int64              `json:"file_count"`
+}
+
+func (q *Queries) ListCollections(ctx context.Context, userID int32) ([]ListCollectionsRow, error) {
+	rows, err := q.db.Query(ctx, listCollections, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCollectionsRow{}
+	for rows.Next() {
+		var i ListCollectionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ParentID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FileCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCollection = `-- name: UpdateCollection :one
+update collections
+set
+    name = $1,
+    parent_id = $2,
+    updated_at = current_timestamp
+where id = $3 and user_id = $4
+returning id, user_id, parent_id, name, created_at, updated_at
+`
+
+type UpdateCollectionParams struct {
+	Name     string      `json:"name"`
+	ParentID pgtype.Int4 `json:"parent_id"`
+	ID       int32       `json:"id"`
+	UserID   int32       `json:"user_id"`
+}
+
+func (q *Queries) UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, updateCollection,
+		arg.Name,
+		arg.ParentID,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,47 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: copyfrom.go
+
+package database
+
+import (
+	"context"
+)
+
+// iteratorForCreateTranscriptSegments implements pgx.CopyFromSource.
+type iteratorForCreateTranscriptSegments struct {
+	rows                 []CreateTranscriptSegmentsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateTranscriptSegments) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateTranscriptSegments) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].JobID,
+		r.rows[0].SegmentIndex,
+		r.rows[0].StartMs,
+		r.rows[0].EndMs,
+		r.rows[0].Speaker,
+		r.rows[0].Content,
+	}, nil
+}
+
+func (r iteratorForCreateTranscriptSegments) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateTranscriptSegments(ctx context.Context, arg []CreateTranscriptSegmentsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"transcript_segments"}, []string{"job_id", "segment_index", "start_ms", "end_ms", "speaker", "content"}, &iteratorForCreateTranscriptSegments{rows: arg})
+}
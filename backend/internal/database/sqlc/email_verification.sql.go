@@ -0,0 +1,134 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: email_verification.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const consumeEmailVerification = `-- name: ConsumeEmailVerification :execrows
+update email_verifications
+set consumed_at = current_timestamp
+where
+    id = $1
+    and consumed_at is null
+    and expires_at > current_timestamp
+    and attempts < $2::int
+`
+
+type ConsumeEmailVerificationParams struct {
+	ID          int32 `json:"id"`
+	MaxAttempts int32 `json:"max_attempts"`
+}
+
+func (q *Queries) ConsumeEmailVerification(ctx context.Context, arg ConsumeEmailVerificationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, consumeEmailVerification, arg.ID, arg.MaxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createEmailVerification = `-- name: CreateEmailVerification :one
+insert into email_verifications (
+    email,
+    purpose,
+    code_hash,
+    expires_at
+) values (
+    $1, $2, $3, $4
+) returning id, email, purpose, code_hash, attempts, expires_at, consumed_at, created_at
+`
+
+type CreateEmailVerificationParams struct {
+	Email     string    `json:"email"`
+	Purpose   string    `json:"purpose"`
+	CodeHash  []byte    `json:"code_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, createEmailVerification,
+		arg.Email,
+		arg.Purpose,
+		arg.CodeHash,
+		arg.ExpiresAt,
+	)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Purpose,
+		&i.CodeHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePendingEmailVerifications = `-- name: DeletePendingEmailVerifications :exec
+delete from email_verifications
+where email = $1 and purpose = $2 and consumed_at is null
+`
+
+type DeletePendingEmailVerificationsParams struct {
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+}
+
+func (q *Queries) DeletePendingEmailVerifications(ctx context.Context, arg DeletePendingEmailVerificationsParams) error {
+	_, err := q.db.Exec(ctx, deletePendingEmailVerifications, arg.Email, arg.Purpose)
+	return err
+}
+
+const getPendingEmailVerification = `-- name: GetPendingEmailVerification :one
+select id, email, purpose, code_hash, attempts, expires_at, consumed_at, created_at from email_verifications
+where
+    email = $1
+    and purpose = $2
+    and consumed_at is null
+    and expires_at > current_timestamp
+order by id desc
+limit 1
+`
+
+type GetPendingEmailVerificationParams struct {
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+}
+
+func (q *Queries) GetPendingEmailVerification(ctx context.Context, arg GetPendingEmailVerificationParams) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, getPendingEmailVerification, arg.Email, arg.Purpose)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Purpose,
+		&i.CodeHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.ConsumedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const incrementEmailVerificationAttempts = `-- name: IncrementEmailVerificationAttempts :one
+update email_verifications
+set attempts = attempts + 1
+where id = $1
+returning attempts
+`
+
+func (q *Queries) IncrementEmailVerificationAttempts(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementEmailVerificationAttempts, id)
+	var attempts int32
+	err := row.Scan(&attempts)
+	return attempts, err
+}
@@ -30,7 +30,7 @@ insert into encryption_keys (
     purpose
 ) values (
     $1, $2, $3, $4
-) returning id, public_key, private_key, is_active, purpose, created_at, updated_at
+) returning id, public_key, private_key, is_active, purpose, created_at, updated_at, retires_at
 `
 
 type CreateEncryptionKeysParams struct {
@@ -56,6 +56,7 @@ func (q *Queries) CreateEncryptionKeys(ctx context.Context, arg CreateEncryption
 		&i.Purpose,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetiresAt,
 	)
 	return i, err
 }
@@ -84,22 +85,116 @@ func (q *Queries) GetActiveKeyBasedOnPurpose(ctx context.Context, purpose string
 	return i, err
 }
 
+const getActiveKeyIDBasedOnPurpose = `-- name: GetActiveKeyIDBasedOnPurpose :one
+select id from encryption_keys
+where is_active = 'true' and purpose = $1
+`
+
+func (q *Queries) GetActiveKeyIDBasedOnPurpose(ctx context.Context, purpose string) (int32, error) {
+	row := q.db.QueryRow(ctx, getActiveKeyIDBasedOnPurpose, purpose)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getVerificationKey = `-- name: GetVerificationKey :one
+select id, public_key, retires_at from encryption_keys
+where
+    id = $1
+    and purpose = $2
+    and (retires_at is null or retires_at > current_timestamp)
+`
+
+type GetVerificationKeyParams struct {
+	ID      int32  `json:"id"`
+	Purpose string `json:"purpose"`
+}
+
+type GetVerificationKeyRow struct {
+	ID        int32              `json:"id"`
+	PublicKey string             `json:"public_key"`
+	RetiresAt pgtype.Timestamptz `json:"retires_at"`
+}
+
+func (q *Queries) GetVerificationKey(ctx context.Context, arg GetVerificationKeyParams) (GetVerificationKeyRow, error) {
+	row := q.db.QueryRow(ctx, getVerificationKey, arg.ID, arg.Purpose)
+	var i GetVerificationKeyRow
+	err := row.Scan(&i.ID, &i.PublicKey, &i.RetiresAt)
+	return i, err
+}
+
+const listVerificationKeys = `-- name: ListVerificationKeys :many
+select id, public_key, is_active, retires_at, created_at from encryption_keys
+where
+    purpose = $1
+    and (retires_at is null or retires_at > current_timestamp)
+order by id
+`
+
+type ListVerificationKeysRow struct {
+	ID        int32              `json:"id"`
+	PublicKey string             `json:"public_key"`
+	IsActive  pgtype.Bool        `json:"is_active"`
+	RetiresAt pgtype.Timestamptz `json:"retires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListVerificationKeys(ctx context.Context, purpose string) ([]ListVerificationKeysRow, error) {
+	rows, err := q.db.Query(ctx, listVerificationKeys, purpose)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVerificationKeysRow{}
+	for rows.Next() {
+		var i ListVerificationKeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublicKey,
+			&i.IsActive,
+			&i.RetiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockActiveKeyBasedOnPurpose = `-- name: LockActiveKeyBasedOnPurpose :one
+select id from encryption_keys
+where is_active = 'true' and purpose = $1
+for update
+`
+
+func (q *Queries) LockActiveKeyBasedOnPurpose(ctx context.Context, purpose string) (int32, error) {
+	row := q.db.QueryRow(ctx, lockActiveKeyBasedOnPurpose, purpose)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateKeyStatus = `-- name: UpdateKeyStatus :exec
 update encryption_keys
 set
     is_active = coalesce($1, is_active),
+    retires_at = coalesce($2, retires_at),
     updated_at = current_timestamp
 where
-    id = $2
-returning id, public_key, private_key, is_active, purpose, created_at, updated_at
+    id = $3
 `
 
 type UpdateKeyStatusParams struct {
-	IsActive pgtype.Bool `json:"is_active"`
-	ID       int32       `json:"id"`
+	IsActive  pgtype.Bool        `json:"is_active"`
+	RetiresAt pgtype.Timestamptz `json:"retires_at"`
+	ID        int32              `json:"id"`
 }
 
 func (q *Queries) UpdateKeyStatus(ctx context.Context, arg UpdateKeyStatusParams) error {
-	_, err := q.db.Exec(ctx, updateKeyStatus, arg.IsActive, arg.ID)
+	_, err := q.db.Exec(ctx, updateKeyStatus, arg.IsActive, arg.RetiresAt, arg.ID)
 	return err
 }
@@ -16,17 +16,29 @@ insert into file_registry (
     user_id,
     file_name,
     lock_status,
-    upload_status
+    upload_status,
+    audio_format,
+    codec,
+    duration_ms,
+    sample_rate,
+    channels,
+    size_bytes
 ) values (
-    $1, $2, $3, $4
-) returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
 `
 
 type CreateEmptyFileParams struct {
-	UserID       int32  `json:"user_id"`
-	FileName     string `json:"file_name"`
-	LockStatus   bool   `json:"lock_status"`
-	UploadStatus string `json:"upload_status"`
+	UserID       int32       `json:"user_id"`
+	FileName     string      `json:"file_name"`
+	LockStatus   bool        `json:"lock_status"`
+	UploadStatus string      `json:"upload_status"`
+	AudioFormat  pgtype.Text `json:"audio_format"`
+	Codec        pgtype.Text `json:"codec"`
+	DurationMs   pgtype.Int8 `json:"duration_ms"`
+	SampleRate   pgtype.Int4 `json:"sample_rate"`
+	Channels     pgtype.Int4 `json:"channels"`
+	SizeBytes    pgtype.Int8 `json:"size_bytes"`
 }
 
 func (q *Queries) CreateEmptyFile(ctx context.Context, arg CreateEmptyFileParams) (FileRegistry, error) {
@@ -35,6 +47,12 @@ func (q *Queries) CreateEmptyFile(ctx context.Context, arg CreateEmptyFileParams
 		arg.FileName,
 		arg.LockStatus,
 		arg.UploadStatus,
+		arg.AudioFormat,
+		arg.Codec,
+		arg.DurationMs,
+		arg.SampleRate,
+		arg.Channels,
+		arg.SizeBytes,
 	)
 	var i FileRegistry
 	err := row.Scan(
@@ -46,16 +64,35 @@ func (q *Queries) CreateEmptyFile(ctx context.Context, arg CreateEmptyFileParams
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const deleteFiles = `-- name: DeleteFiles :exec
-delete from file_registry
-where
-    user_id = $1
-    and
-    id = $2
+with deleted as (
+    delete from file_registry
+    where
+        file_registry.user_id = $1
+        and
+        file_registry.id = $2
+    returning file_registry.user_id, file_registry.size_bytes
+)
+insert into user_usage (user_id, storage_bytes, file_count)
+select deleted.user_id, -coalesce(deleted.size_bytes, 0), -1 from deleted
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp
 `
 
 type DeleteFilesParams struct {
@@ -63,15 +100,126 @@ type DeleteFilesParams struct {
 	ID     int32 `json:"id"`
 }
 
+// every way a file leaves the registry goes through here, so the usage counters are released with it
 func (q *Queries) DeleteFiles(ctx context.Context, arg DeleteFilesParams) error {
 	_, err := q.db.Exec(ctx, deleteFiles, arg.UserID, arg.ID)
 	return err
 }
 
+const getExpiredTrashedFileByLocking = `-- name: GetExpiredTrashedFileByLocking :one
+select object_key from file_registry
+where id = $1 and user_id = $2 and deleted_at < $3
+for update
+`
+
+type GetExpiredTrashedFileByLockingParams struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	TrashedBefore pgtype.Timestamptz `json:"trashed_before"`
+}
+
+func (q *Queries) GetExpiredTrashedFileByLocking(ctx context.Context, arg GetExpiredTrashedFileByLockingParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getExpiredTrashedFileByLocking, arg.ID, arg.UserID, arg.TrashedBefore)
+	var object_key pgtype.Text
+	err := row.Scan(&object_key)
+	return object_key, err
+}
+
+const getFile = `-- name: GetFile :one
+select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id from file_registry
+where id = $1 and user_id = $2 and deleted_at is null
+`
+
+type GetFileParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetFile(ctx context.Context, arg GetFileParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, getFile, arg.ID, arg.UserID)
+	var i FileRegistry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileName,
+		&i.ObjectKey,
+		&i.LockStatus,
+		&i.UploadStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
+	)
+	return i, err
+}
+
+const getFileByContentHash = `-- name: GetFileByContentHash :one
+select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id from file_registry
+where
+    user_id = $1
+    and content_hash = $2
+    and id <> $3
+    and upload_status = $4
+    and lock_status = $5
+    and ($6::boolean or deleted_at is null)
+order by id
+limit 1
+for update
+`
+
+type GetFileByContentHashParams struct {
+	UserID         int32       `json:"user_id"`
+	ContentHash    pgtype.Text `json:"content_hash"`
+	ID             int32       `json:"id"`
+	UploadStatus   string      `json:"upload_status"`
+	LockStatus     bool        `json:"lock_status"`
+	IncludeTrashed bool        `json:"include_trashed"`
+}
+
+// a trashed file can still lend its object to a new upload, purging it keeps the object while it is referenced
+func (q *Queries) GetFileByContentHash(ctx context.Context, arg GetFileByContentHashParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, getFileByContentHash,
+		arg.UserID,
+		arg.ContentHash,
+		arg.ID,
+		arg.UploadStatus,
+		arg.LockStatus,
+		arg.IncludeTrashed,
+	)
+	var i FileRegistry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileName,
+		&i.ObjectKey,
+		&i.LockStatus,
+		&i.UploadStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
+	)
+	return i, err
+}
+
 const getFileByID = `-- name: GetFileByID :one
-select upload_status, lock_status, updated_at
+select upload_status, lock_status, updated_at, object_key, duration_ms
 from file_registry
-where id = $1 and user_id = $2
+where id = $1 and user_id = $2 and deleted_at is null
 for update
 `
 
@@ -84,18 +232,26 @@ type GetFileByIDRow struct {
 	UploadStatus string             `json:"upload_status"`
 	LockStatus   bool               `json:"lock_status"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	ObjectKey    pgtype.Text        `json:"object_key"`
+	DurationMs   pgtype.Int8        `json:"duration_ms"`
 }
 
 func (q *Queries) GetFileByID(ctx context.Context, arg GetFileByIDParams) (GetFileByIDRow, error) {
 	row := q.db.QueryRow(ctx, getFileByID, arg.ID, arg.UserID)
 	var i GetFileByIDRow
-	err := row.Scan(&i.UploadStatus, &i.LockStatus, &i.UpdatedAt)
+	err := row.Scan(
+		&i.UploadStatus,
+		&i.LockStatus,
+		&i.UpdatedAt,
+		&i.ObjectKey,
+		&i.DurationMs,
+	)
 	return i, err
 }
 
 const getFileByName = `-- name: GetFileByName :one
-select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at from file_registry
-where file_name = $1 and user_id = $2
+select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id from file_registry
+where file_name = $1 and user_id = $2 and deleted_at is null
 `
 
 type GetFileByNameParams struct {
@@ -115,15 +271,25 @@ func (q *Queries) GetFileByName(ctx context.Context, arg GetFileByNameParams) (F
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const getFileByNameByLocking = `-- name: GetFileByNameByLocking :one
-select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at from file_registry
+select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id from file_registry
 where
     file_name = $1
     and user_id = $2
+    and deleted_at is null
 for update
 `
 
@@ -144,41 +310,114 @@ func (q *Queries) GetFileByNameByLocking(ctx context.Context, arg GetFileByNameB
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
+	)
+	return i, err
+}
+
+const getTrashedFileByLocking = `-- name: GetTrashedFileByLocking :one
+select id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id from file_registry
+where id = $1 and user_id = $2 and deleted_at is not null
+for update
+`
+
+type GetTrashedFileByLockingParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetTrashedFileByLocking(ctx context.Context, arg GetTrashedFileByLockingParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, getTrashedFileByLocking, arg.ID, arg.UserID)
+	var i FileRegistry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileName,
+		&i.ObjectKey,
+		&i.LockStatus,
+		&i.UploadStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
-const listAllFiles = `-- name: ListAllFiles :many
-select id, file_name from file_registry
+const listCollectionFilesForTranscript = `-- name: ListCollectionFilesForTranscript :many
+select f.id, f.file_name, f.object_key
+from file_registry f
 where
-    user_id = $1
-    and
-    upload_status = $2
-    and
-    lock_status = $3
+    f.user_id = $1
+    and f.collection_id = any($2::int[])
+    and f.deleted_at is null
+    and f.upload_status = $3
+    and f.lock_status = $4
+    and not exists (
+        select 1 from transcript_jobs j
+        where j.file_id = f.id
+            and (
+                j.status in ($5::varchar, $6::varchar)
+                or (not $7::bool and j.status = $8::varchar)
+            )
+    )
+order by f.id
+limit $9
 `
 
-type ListAllFilesParams struct {
-	UserID       int32  `json:"user_id"`
-	UploadStatus string `json:"upload_status"`
-	LockStatus   bool   `json:"lock_status"`
+type ListCollectionFilesForTranscriptParams struct {
+	UserID             int32   `json:"user_id"`
+	CollectionIds      []int32 `json:"collection_ids"`
+	UploadStatus       string  `json:"upload_status"`
+	LockStatus         bool    `json:"lock_status"`
+	QueuedStatus       string  `json:"queued_status"`
+	ProcessingStatus   string  `json:"processing_status"`
+	IncludeTranscribed bool    `json:"include_transcribed"`
+	SucceededStatus    string  `json:"succeeded_status"`
+	PageLimit          int32   `json:"page_limit"`
 }
 
-type ListAllFilesRow struct {
-	ID       int32  `json:"id"`
-	FileName string `json:"file_name"`
+type ListCollectionFilesForTranscriptRow struct {
+	ID        int32       `json:"id"`
+	FileName  string      `json:"file_name"`
+	ObjectKey pgtype.Text `json:"object_key"`
 }
 
-func (q *Queries) ListAllFiles(ctx context.Context, arg ListAllFilesParams) ([]ListAllFilesRow, error) {
-	rows, err := q.db.Query(ctx, listAllFiles, arg.UserID, arg.UploadStatus, arg.LockStatus)
+func (q *Queries) ListCollectionFilesForTranscript(ctx context.Context, arg ListCollectionFilesForTranscriptParams) ([]ListCollectionFilesForTranscriptRow, error) {
+	rows, err := q.db.Query(ctx, listCollectionFilesForTranscript,
+		arg.UserID,
+		arg.CollectionIds,
+		arg.UploadStatus,
+		arg.LockStatus,
+		arg.QueuedStatus,
+		arg.ProcessingStatus,
+		arg.IncludeTranscribed,
+		arg.SucceededStatus,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListAllFilesRow{}
+	items := []ListCollectionFilesForTranscriptRow{}
 	for rows.Next() {
-		var i ListAllFilesRow
-		if err := rows.Scan(&i.ID, &i.FileName); err != nil {
+		var i ListCollectionFilesForTranscriptRow
+		if err := rows.Scan(&i.ID, &i.FileName, &i.ObjectKey); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -189,58 +428,215 @@ func (q *Queries) ListAllFiles(ctx context.Context, arg ListAllFilesParams) ([]L
 	return items, nil
 }
 
-const listConflictingFiles = `-- name: ListConflictingFiles :many
-select id, object_key from file_registry
-where ((lock_status = $1 AND upload_status = $2) OR
-    (lock_status = $3 AND upload_status = $4) OR
-    (lock_status = $5 AND upload_status = $6) OR
-    (lock_status = $7 AND upload_status = $8) OR
-    (lock_status = $9 AND upload_status = $10))
-    AND
-    user_id = $11
+const listExpiredTrashedFiles = `-- name: ListExpiredTrashedFiles :many
+select id, user_id
+from file_registry
+where deleted_at < $1
+order by deleted_at, id
+limit $2
 `
 
-type ListConflictingFilesParams struct {
-	FirstLockCondition  bool   `json:"first_lock_condition"`
-	FirstUploadStatus   string `json:"first_upload_status"`
-	SecondLockCondition bool   `json:"second_lock_condition"`
-	SecondUploadStatus  string `json:"second_upload_status"`
-	ThirdLockCondition  bool   `json:"third_lock_condition"`
-	ThirdUploadStatus   string `json:"third_upload_status"`
-	FourthLockCondition bool   `json:"fourth_lock_condition"`
-	FourthUploadStatus  string `json:"fourth_upload_status"`
-	FifthLockCondition  bool   `json:"fifth_lock_condition"`
-	FifthUploadStatus   string `json:"fifth_upload_status"`
-	UserID              int32  `json:"user_id"`
-}
-
-type ListConflictingFilesRow struct {
-	ID        int32       `json:"id"`
-	ObjectKey pgtype.Text `json:"object_key"`
+type ListExpiredTrashedFilesParams struct {
+	TrashedBefore pgtype.Timestamptz `json:"trashed_before"`
+	PageLimit     int32              `json:"page_limit"`
+}
+
+type ListExpiredTrashedFilesRow struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
 }
 
-func (q *Queries) ListConflictingFiles(ctx context.Context, arg ListConflictingFilesParams) ([]ListConflictingFilesRow, error) {
-	rows, err := q.db.Query(ctx, listConflictingFiles,
-		arg.FirstLockCondition,
-		arg.FirstUploadStatus,
-		arg.SecondLockCondition,
-		arg.SecondUploadStatus,
-		arg.ThirdLockCondition,
-		arg.ThirdUploadStatus,
-		arg.FourthLockCondition,
-		arg.FourthUploadStatus,
-		arg.FifthLockCondition,
-		arg.FifthUploadStatus,
+func (q *Queries) ListExpiredTrashedFiles(ctx context.Context, arg ListExpiredTrashedFilesParams) ([]ListExpiredTrashedFilesRow, error) {
+	rows, err := q.db.Query(ctx, listExpiredTrashedFiles, arg.TrashedBefore, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListExpiredTrashedFilesRow{}
+	for rows.Next() {
+		var i ListExpiredTrashedFilesRow
+		if err := rows.Scan(&i.ID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFiles = `-- name: ListFiles :many
+select
+    f.id,
+    f.file_name,
+    f.upload_status,
+    f.size_bytes,
+    f.audio_format,
+    f.codec,
+    f.duration_ms,
+    f.sample_rate,
+    f.channels,
+    f.created_at,
+    f.updated_at,
+    f.collection_id,
+    array(
+        select t.name from file_tags ft
+        join tags t on t.id = ft.tag_id
+        where ft.file_id = f.id
+        order by lower(t.name)
+    )::text[] as tags,
+    latest_job.id as latest_job_id,
+    latest_job.status as latest_job_status
+from file_registry f
+left join transcript_jobs latest_job on latest_job.id = (
+    select j.id
+    from transcript_jobs j
+    where j.file_id = f.id
+    order by j.created_at desc, j.id desc
+    limit 1
+)
+where
+    f.user_id = $1
+    and f.deleted_at is null
+    and (
+        (
+            $2::varchar is null
+            and f.upload_status = $3
+            and f.lock_status = $4
+        )
+        or f.upload_status = $2::varchar
+    )
+    and ($5::varchar is null or f.file_name like $5::varchar || '%')
+    and ($6::int[] is null or f.collection_id = any($6::int[]))
+    and (
+        $7::text[] is null
+        or (
+            select count(*) from file_tags ft
+            join tags t on t.id = ft.tag_id
+            where ft.file_id = f.id and lower(t.name) in (select lower(n) from unnest($7::text[]) as n)
+        ) = (select count(distinct lower(n)) from unnest($7::text[]) as n)
+    )
+    and ($8::timestamptz is null or f.created_at >= $8::timestamptz)
+    and ($9::timestamptz is null or f.created_at < $9::timestamptz)
+    and (
+        $10::bool is null
+        or exists (
+            select 1 from transcript_jobs t
+            where t.file_id = f.id and t.status = $11
+        ) = $10::bool
+    )
+    and (
+        $12::int is null
+        or ($13::varchar = 'created_at' and not $14::bool
+            and (f.created_at, f.id) > ($15::timestamptz, $12::int))
+        or ($13::varchar = 'created_at' and $14::bool
+            and (f.created_at, f.id) < ($15::timestamptz, $12::int))
+        or ($13::varchar = 'file_name' and not $14::bool
+            and (f.file_name, f.id) > ($16::varchar, $12::int))
+        or ($13::varchar = 'file_name' and $14::bool
+            and (f.file_name, f.id) < ($16::varchar, $12::int))
+        or ($13::varchar = 'size' and not $14::bool
+            and (coalesce(f.size_bytes, 0), f.id) > ($17::bigint, $12::int))
+        or ($13::varchar = 'size' and $14::bool
+            and (coalesce(f.size_bytes, 0), f.id) < ($17::bigint, $12::int))
+    )
+order by
+    case when $13::varchar = 'created_at' and not $14::bool then f.created_at end asc,
+    case when $13::varchar = 'created_at' and $14::bool then f.created_at end desc,
+    case when $13::varchar = 'file_name' and not $14::bool then f.file_name end asc,
+    case when $13::varchar = 'file_name' and $14::bool then f.file_name end desc,
+    case when $13::varchar = 'size' and not $14::bool then coalesce(f.size_bytes, 0) end asc,
+    case when $13::varchar = 'size' and $14::bool then coalesce(f.size_bytes, 0) end desc,
+    case when not $14::bool then f.id end asc,
+    case when $14::bool then f.id end desc
+limit $18
+`
+
+type ListFilesParams struct {
+	UserID              int32              `json:"user_id"`
+	UploadStatus        pgtype.Text        `json:"upload_status"`
+	DefaultUploadStatus string             `json:"default_upload_status"`
+	DefaultLockStatus   bool               `json:"default_lock_status"`
+	NamePrefix          pgtype.Text        `json:"name_prefix"`
+	CollectionIds       []int32            `json:"collection_ids"`
+	Tags                []string           `json:"tags"`
+	CreatedAfter        pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore       pgtype.Timestamptz `json:"created_before"`
+	HasTranscript       pgtype.Bool        `json:"has_transcript"`
+	SucceededStatus     string             `json:"succeeded_status"`
+	CursorID            pgtype.Int4        `json:"cursor_id"`
+	SortBy              string             `json:"sort_by"`
+	SortDesc            bool               `json:"sort_desc"`
+	CursorCreatedAt     pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorFileName      pgtype.Text        `json:"cursor_file_name"`
+	CursorSize          pgtype.Int8        `json:"cursor_size"`
+	PageLimit           int32              `json:"page_limit"`
+}
+
+type ListFilesRow struct {
+	ID              int32              `json:"id"`
+	FileName        string             `json:"file_name"`
+	UploadStatus    string             `json:"upload_status"`
+	SizeBytes       pgtype.Int8        `json:"size_bytes"`
+	AudioFormat     pgtype.Text        `json:"audio_format"`
+	Codec           pgtype.Text        `json:"codec"`
+	DurationMs      pgtype.Int8        `json:"duration_ms"`
+	SampleRate      pgtype.Int4        `json:"sample_rate"`
+	Channels        pgtype.Int4        `json:"channels"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	CollectionID    pgtype.Int4        `json:"collection_id"`
+	Tags            []string           `json:"tags"`
+	LatestJobID     pgtype.Int4        `json:"latest_job_id"`
+	LatestJobStatus pgtype.Text        `json:"latest_job_status"`
+}
+
+func (q *Queries) ListFiles(ctx context.Context, arg ListFilesParams) ([]ListFilesRow, error) {
+	rows, err := q.db.Query(ctx, listFiles,
 		arg.UserID,
+		arg.UploadStatus,
+		arg.DefaultUploadStatus,
+		arg.DefaultLockStatus,
+		arg.NamePrefix,
+		arg.CollectionIds,
+		arg.Tags,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.HasTranscript,
+		arg.SucceededStatus,
+		arg.CursorID,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.CursorCreatedAt,
+		arg.CursorFileName,
+		arg.CursorSize,
+		arg.PageLimit,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListConflictingFilesRow{}
+	items := []ListFilesRow{}
 	for rows.Next() {
-		var i ListConflictingFilesRow
-		if err := rows.Scan(&i.ID, &i.ObjectKey); err != nil {
+		var i ListFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.UploadStatus,
+			&i.SizeBytes,
+			&i.AudioFormat,
+			&i.Codec,
+			&i.DurationMs,
+			&i.SampleRate,
+			&i.Channels,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CollectionID,
+			&i.Tags,
+			&i.LatestJobID,
+			&i.LatestJobStatus,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -251,30 +647,183 @@ func (q *Queries) ListConflictingFiles(ctx context.Context, arg ListConflictingF
 	return items, nil
 }
 
-const unlockAndLockFile = `-- name: UnlockAndLockFile :one
+const listTrashedFiles = `-- name: ListTrashedFiles :many
+select id, file_name, size_bytes, duration_ms, created_at, deleted_at
+from file_registry
+where user_id = $1 and deleted_at is not null
+order by deleted_at desc, id desc
+limit $3
+offset $2
+`
+
+type ListTrashedFilesParams struct {
+	UserID     int32 `json:"user_id"`
+	PageOffset int32 `json:"page_offset"`
+	PageLimit  int32 `json:"page_limit"`
+}
+
+type ListTrashedFilesRow struct {
+	ID         int32              `json:"id"`
+	FileName   string             `json:"file_name"`
+	SizeBytes  pgtype.Int8        `json:"size_bytes"`
+	DurationMs pgtype.Int8        `json:"duration_ms"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) ListTrashedFiles(ctx context.Context, arg ListTrashedFilesParams) ([]ListTrashedFilesRow, error) {
+	rows, err := q.db.Query(ctx, listTrashedFiles, arg.UserID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrashedFilesRow{}
+	for rows.Next() {
+		var i ListTrashedFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.SizeBytes,
+			&i.DurationMs,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockObjectKeyReferences = `-- name: LockObjectKeyReferences :many
+select id from file_registry
+where user_id = $1 and object_key = $2
+for update
+`
+
+type LockObjectKeyReferencesParams struct {
+	UserID    int32       `json:"user_id"`
+	ObjectKey pgtype.Text `json:"object_key"`
+}
+
+func (q *Queries) LockObjectKeyReferences(ctx context.Context, arg LockObjectKeyReferencesParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, lockObjectKeyReferences, arg.UserID, arg.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreFile = `-- name: RestoreFile :one
 update file_registry
 set
-    upload_status = $1,
-    lock_status = $2,
+    deleted_at = null,
     updated_at = current_timestamp
-where id = $3 and user_id = $4
-returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at
+where id = $1 and user_id = $2
+returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
 `
 
-type UnlockAndLockFileParams struct {
-	Status     string `json:"status"`
-	LockStatus bool   `json:"lock_status"`
-	ID         int32  `json:"id"`
-	UserID     int32  `json:"user_id"`
+type RestoreFileParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
 }
 
-func (q *Queries) UnlockAndLockFile(ctx context.Context, arg UnlockAndLockFileParams) (FileRegistry, error) {
-	row := q.db.QueryRow(ctx, unlockAndLockFile,
-		arg.Status,
-		arg.LockStatus,
-		arg.ID,
-		arg.UserID,
+func (q *Queries) RestoreFile(ctx context.Context, arg RestoreFileParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, restoreFile, arg.ID, arg.UserID)
+	var i FileRegistry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileName,
+		&i.ObjectKey,
+		&i.LockStatus,
+		&i.UploadStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
+	)
+	return i, err
+}
+
+const setFileCollection = `-- name: SetFileCollection :one
+update file_registry
+set
+    collection_id = $1,
+    updated_at = current_timestamp
+where id = $2 and user_id = $3 and deleted_at is null
+returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
+`
+
+type SetFileCollectionParams struct {
+	CollectionID pgtype.Int4 `json:"collection_id"`
+	ID           int32       `json:"id"`
+	UserID       int32       `json:"user_id"`
+}
+
+func (q *Queries) SetFileCollection(ctx context.Context, arg SetFileCollectionParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, setFileCollection, arg.CollectionID, arg.ID, arg.UserID)
+	var i FileRegistry
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileName,
+		&i.ObjectKey,
+		&i.LockStatus,
+		&i.UploadStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
+	return i, err
+}
+
+const trashFile = `-- name: TrashFile :one
+update file_registry
+set
+    deleted_at = current_timestamp,
+    updated_at = current_timestamp
+where id = $1 and user_id = $2
+returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
+`
+
+type TrashFileParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) TrashFile(ctx context.Context, arg TrashFileParams) (FileRegistry, error) {
+	row := q.db.QueryRow(ctx, trashFile, arg.ID, arg.UserID)
 	var i FileRegistry
 	err := row.Scan(
 		&i.ID,
@@ -285,25 +834,71 @@ func (q *Queries) UnlockAndLockFile(ctx context.Context, arg UnlockAndLockFilePa
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
+const updateFileAudioMetadata = `-- name: UpdateFileAudioMetadata :exec
+update file_registry
+set
+    audio_format = $1,
+    codec = $2,
+    duration_ms = $3,
+    sample_rate = $4,
+    channels = $5,
+    updated_at = current_timestamp
+where id = $6 and user_id = $7
+`
+
+type UpdateFileAudioMetadataParams struct {
+	AudioFormat pgtype.Text `json:"audio_format"`
+	Codec       pgtype.Text `json:"codec"`
+	DurationMs  pgtype.Int8 `json:"duration_ms"`
+	SampleRate  pgtype.Int4 `json:"sample_rate"`
+	Channels    pgtype.Int4 `json:"channels"`
+	ID          int32       `json:"id"`
+	UserID      int32       `json:"user_id"`
+}
+
+func (q *Queries) UpdateFileAudioMetadata(ctx context.Context, arg UpdateFileAudioMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateFileAudioMetadata,
+		arg.AudioFormat,
+		arg.Codec,
+		arg.DurationMs,
+		arg.SampleRate,
+		arg.Channels,
+		arg.ID,
+		arg.UserID,
+	)
+	return err
+}
+
 const updateFileMetadata = `-- name: UpdateFileMetadata :one
 update file_registry
 set
     object_key = $1,
     upload_status = $2,
     lock_status = $3,
+    content_hash = $4,
     updated_at = current_timestamp
-where id = $4 and user_id = $5
-returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at
+where id = $5 and user_id = $6
+returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
 `
 
 type UpdateFileMetadataParams struct {
 	ObjectKey    pgtype.Text `json:"object_key"`
 	UploadStatus string      `json:"upload_status"`
 	LockStatus   bool        `json:"lock_status"`
+	ContentHash  pgtype.Text `json:"content_hash"`
 	ID           int32       `json:"id"`
 	UserID       int32       `json:"user_id"`
 }
@@ -313,6 +908,7 @@ func (q *Queries) UpdateFileMetadata(ctx context.Context, arg UpdateFileMetadata
 		arg.ObjectKey,
 		arg.UploadStatus,
 		arg.LockStatus,
+		arg.ContentHash,
 		arg.ID,
 		arg.UserID,
 	)
@@ -326,6 +922,15 @@ func (q *Queries) UpdateFileMetadata(ctx context.Context, arg UpdateFileMetadata
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
@@ -335,8 +940,8 @@ update file_registry
 set
     file_name = $1,
     updated_at = current_timestamp
-where id = $2 and user_id = $3
-returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at
+where id = $2 and user_id = $3 and deleted_at is null
+returning id, user_id, file_name, object_key, lock_status, upload_status, created_at, updated_at, audio_format, codec, duration_ms, sample_rate, channels, content_hash, size_bytes, deleted_at, collection_id
 `
 
 type UpdateFileNameParams struct {
@@ -357,6 +962,15 @@ func (q *Queries) UpdateFileName(ctx context.Context, arg UpdateFileNameParams)
 		&i.UploadStatus,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AudioFormat,
+		&i.Codec,
+		&i.DurationMs,
+		&i.SampleRate,
+		&i.Channels,
+		&i.ContentHash,
+		&i.SizeBytes,
+		&i.DeletedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: message_queue.sql
+
+package database
+
+import (
+	"context"
+)
+
+const enqueueMessage = `-- name: EnqueueMessage :one
+insert into message_queue (
+    topic,
+    payload
+) values (
+    $1, $2
+) returning id
+`
+
+type EnqueueMessageParams struct {
+	Topic   string `json:"topic"`
+	Payload []byte `json:"payload"`
+}
+
+func (q *Queries) EnqueueMessage(ctx context.Context, arg EnqueueMessageParams) (int64, error) {
+	row := q.db.QueryRow(ctx, enqueueMessage, arg.Topic, arg.Payload)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const notifyMessage = `-- name: NotifyMessage :exec
+select pg_notify($1::text, $2::text)
+`
+
+type NotifyMessageParams struct {
+	Channel string `json:"channel"`
+	Payload string `json:"payload"`
+}
+
+func (q *Queries) NotifyMessage(ctx context.Context, arg NotifyMessageParams) error {
+	_, err := q.db.Exec(ctx, notifyMessage, arg.Channel, arg.Payload)
+	return err
+}
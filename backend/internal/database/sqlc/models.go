@@ -5,16 +5,59 @@
 package database
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountDeletion struct {
+	ID             int32              `json:"id"`
+	UserID         int32              `json:"user_id"`
+	EmailHash      []byte             `json:"email_hash"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	LastError      pgtype.Text        `json:"last_error"`
+	ObjectsDeleted int32              `json:"objects_deleted"`
+	FilesDeleted   int32              `json:"files_deleted"`
+	JobsDeleted    int32              `json:"jobs_deleted"`
+	LeaseExpiresAt pgtype.Timestamptz `json:"lease_expires_at"`
+	RequestedAt    time.Time          `json:"requested_at"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+}
+
 type ApiKey struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	Signature    []byte             `json:"signature"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	Name         string             `json:"name"`
+	Scopes       []string           `json:"scopes"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt   pgtype.Timestamptz `json:"last_used_at"`
+	KeyID        pgtype.Text        `json:"key_id"`
+	SecretHash   []byte             `json:"secret_hash"`
+	SigningKeyID int32              `json:"signing_key_id"`
+}
+
+type Collection struct {
+	ID        int32              `json:"id"`
+	UserID    int32              `json:"user_id"`
+	ParentID  pgtype.Int4        `json:"parent_id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type EmailVerification struct {
 	ID         int32              `json:"id"`
-	UserID     int32              `json:"user_id"`
-	Credential []byte             `json:"credential"`
-	Signature  []byte             `json:"signature"`
-	CreatedAt  pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	Email      string             `json:"email"`
+	Purpose    string             `json:"purpose"`
+	CodeHash   []byte             `json:"code_hash"`
+	Attempts   int32              `json:"attempts"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	ConsumedAt pgtype.Timestamptz `json:"consumed_at"`
+	CreatedAt  time.Time          `json:"created_at"`
 }
 
 type EncryptionKey struct {
@@ -25,6 +68,7 @@ type EncryptionKey struct {
 	Purpose    string             `json:"purpose"`
 	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	RetiresAt  pgtype.Timestamptz `json:"retires_at"`
 }
 
 type FileRegistry struct {
@@ -36,11 +80,155 @@ type FileRegistry struct {
 	UploadStatus string             `json:"upload_status"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	AudioFormat  pgtype.Text        `json:"audio_format"`
+	Codec        pgtype.Text        `json:"codec"`
+	DurationMs   pgtype.Int8        `json:"duration_ms"`
+	SampleRate   pgtype.Int4        `json:"sample_rate"`
+	Channels     pgtype.Int4        `json:"channels"`
+	ContentHash  pgtype.Text        `json:"content_hash"`
+	SizeBytes    pgtype.Int8        `json:"size_bytes"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+	CollectionID pgtype.Int4        `json:"collection_id"`
 }
 
-type User struct {
+type FileTag struct {
+	FileID    int32              `json:"file_id"`
+	TagID     int32              `json:"tag_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type MessageQueue struct {
+	ID        int64              `json:"id"`
+	Topic     string             `json:"topic"`
+	Payload   []byte             `json:"payload"`
+	ClaimedAt pgtype.Timestamptz `json:"claimed_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ObjectGcAudit struct {
+	ID            int64       `json:"id"`
+	RunID         pgtype.Int4 `json:"run_id"`
+	Action        string      `json:"action"`
+	UserID        int32       `json:"user_id"`
+	ObjectKey     string      `json:"object_key"`
+	QuarantineKey pgtype.Text `json:"quarantine_key"`
+	SizeBytes     pgtype.Int8 `json:"size_bytes"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type Plan struct {
+	Name                    string      `json:"name"`
+	MaxStorageBytes         pgtype.Int8 `json:"max_storage_bytes"`
+	MaxFiles                pgtype.Int4 `json:"max_files"`
+	MaxTranscriptionMinutes pgtype.Int4 `json:"max_transcription_minutes"`
+	CreatedAt               time.Time   `json:"created_at"`
+}
+
+type QuarantinedObject struct {
+	ID            int32              `json:"id"`
+	UserID        int32              `json:"user_id"`
+	OriginalKey   string             `json:"original_key"`
+	QuarantineKey string             `json:"quarantine_key"`
+	SizeBytes     int64              `json:"size_bytes"`
+	QuarantinedAt time.Time          `json:"quarantined_at"`
+	PurgeAfter    time.Time          `json:"purge_after"`
+	PurgedAt      pgtype.Timestamptz `json:"purged_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	Allowed   bool      `json:"allowed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReconcilerRun struct {
+	ID                 int32              `json:"id"`
+	DryRun             bool               `json:"dry_run"`
+	FilesScanned       int32              `json:"files_scanned"`
+	FilesRepaired      int32              `json:"files_repaired"`
+	FilesDeleted       int32              `json:"files_deleted"`
+	OrphansFound       int32              `json:"orphans_found"`
+	Errors             int32              `json:"errors"`
+	StartedAt          time.Time          `json:"started_at"`
+	FinishedAt         pgtype.Timestamptz `json:"finished_at"`
+	ObjectsQuarantined int32              `json:"objects_quarantined"`
+	ObjectsPurged      int32              `json:"objects_purged"`
+}
+
+type Tag struct {
 	ID        int32              `json:"id"`
-	Email     string             `json:"email"`
+	UserID    int32              `json:"user_id"`
+	Name      string             `json:"name"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TranscriptJob struct {
+	ID                  int32              `json:"id"`
+	UserID              int32              `json:"user_id"`
+	FileID              int32              `json:"file_id"`
+	Status              string             `json:"status"`
+	ErrorMessage        pgtype.Text        `json:"error_message"`
+	StartedAt           pgtype.Timestamptz `json:"started_at"`
+	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+	Progress            int32              `json:"progress"`
+	TranscriptObjectKey pgtype.Text        `json:"transcript_object_key"`
+}
+
+type TranscriptSegment struct {
+	ID           int64       `json:"id"`
+	JobID        int32       `json:"job_id"`
+	SegmentIndex int32       `json:"segment_index"`
+	StartMs      int64       `json:"start_ms"`
+	EndMs        int64       `json:"end_ms"`
+	Speaker      pgtype.Text `json:"speaker"`
+	Content      string      `json:"content"`
+}
+
+type UploadSession struct {
+	ID           string             `json:"id"`
+	UserID       int32              `json:"user_id"`
+	FileID       int32              `json:"file_id"`
+	ObjectKey    string             `json:"object_key"`
+	TotalSize    int64              `json:"total_size"`
+	UploadOffset int64              `json:"upload_offset"`
+	Status       string             `json:"status"`
+	ExpiresAt    time.Time          `json:"expires_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	UploadMethod string             `json:"upload_method"`
+	ContentType  pgtype.Text        `json:"content_type"`
+	PartKeys     []string           `json:"part_keys"`
+}
+
+type UsageEvent struct {
+	ID        int64       `json:"id"`
+	UserID    int32       `json:"user_id"`
+	Kind      string      `json:"kind"`
+	FileID    pgtype.Int4 `json:"file_id"`
+	JobID     pgtype.Int4 `json:"job_id"`
+	Quantity  int64       `json:"quantity"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type User struct {
+	ID               int32              `json:"id"`
+	Email            string             `json:"email"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	EmailVerifiedAt  pgtype.Timestamptz `json:"email_verified_at"`
+	Plan             string             `json:"plan"`
+	Role             string             `json:"role"`
+	SuspendedAt      pgtype.Timestamptz `json:"suspended_at"`
+	SuspensionReason pgtype.Text        `json:"suspension_reason"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type UserUsage struct {
+	UserID       int32     `json:"user_id"`
+	StorageBytes int64     `json:"storage_bytes"`
+	FileCount    int32     `json:"file_count"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
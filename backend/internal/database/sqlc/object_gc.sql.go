@@ -0,0 +1,281 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: object_gc.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createGCAuditEntry = `-- name: CreateGCAuditEntry :exec
+insert into object_gc_audit (
+    run_id,
+    action,
+    user_id,
+    object_key,
+    quarantine_key,
+    size_bytes
+) values (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type CreateGCAuditEntryParams struct {
+	RunID         pgtype.Int4 `json:"run_id"`
+	Action        string      `json:"action"`
+	UserID        int32       `json:"user_id"`
+	ObjectKey     string      `json:"object_key"`
+	QuarantineKey pgtype.Text `json:"quarantine_key"`
+	SizeBytes     pgtype.Int8 `json:"size_bytes"`
+}
+
+func (q *Queries) CreateGCAuditEntry(ctx context.Context, arg CreateGCAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, createGCAuditEntry,
+		arg.RunID,
+		arg.Action,
+		arg.UserID,
+		arg.ObjectKey,
+		arg.QuarantineKey,
+		arg.SizeBytes,
+	)
+	return err
+}
+
+const createQuarantinedObject = `-- name: CreateQuarantinedObject :one
+insert into quarantined_objects (
+    user_id,
+    original_key,
+    quarantine_key,
+    size_bytes,
+    purge_after
+) values (
+    $1, $2, $3, $4, $5
+) returning id, user_id, original_key, quarantine_key, size_bytes, quarantined_at, purge_after, purged_at
+`
+
+type CreateQuarantinedObjectParams struct {
+	UserID        int32     `json:"user_id"`
+	OriginalKey   string    `json:"original_key"`
+	QuarantineKey string    `json:"quarantine_key"`
+	SizeBytes     int64     `json:"size_bytes"`
+	PurgeAfter    time.Time `json:"purge_after"`
+}
+
+func (q *Queries) CreateQuarantinedObject(ctx context.Context, arg CreateQuarantinedObjectParams) (QuarantinedObject, error) {
+	row := q.db.QueryRow(ctx, createQuarantinedObject,
+		arg.UserID,
+		arg.OriginalKey,
+		arg.QuarantineKey,
+		arg.SizeBytes,
+		arg.PurgeAfter,
+	)
+	var i QuarantinedObject
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OriginalKey,
+		&i.QuarantineKey,
+		&i.SizeBytes,
+		&i.QuarantinedAt,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const deleteQuarantinedObject = `-- name: DeleteQuarantinedObject :exec
+delete from quarantined_objects
+where id = $1
+`
+
+func (q *Queries) DeleteQuarantinedObject(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteQuarantinedObject, id)
+	return err
+}
+
+const expireUserQuarantine = `-- name: ExpireUserQuarantine :exec
+update quarantined_objects
+set purge_after = current_timestamp
+where user_id = $1 and purged_at is null
+`
+
+func (q *Queries) ExpireUserQuarantine(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, expireUserQuarantine, userID)
+	return err
+}
+
+const isObjectKeyReferenced = `-- name: IsObjectKeyReferenced :one
+select (
+    exists (
+        select 1 from file_registry
+        where file_registry.user_id = $1 and file_registry.object_key = $2::varchar
+    ) or exists (
+        select 1 from upload_sessions
+        where
+            upload_sessions.user_id = $1
+            and upload_sessions.status = $3
+            and upload_sessions.object_key = $2::varchar
+    ) or exists (
+        select 1 from upload_sessions
+        where
+            upload_sessions.user_id = $1
+            and upload_sessions.status = $3
+            and upload_sessions.expires_at > current_timestamp
+            and starts_with($2::varchar, upload_sessions.object_key || '.')
+    )
+)::boolean as referenced
+`
+
+type IsObjectKeyReferencedParams struct {
+	UserID       int32  `json:"user_id"`
+	ObjectKey    string `json:"object_key"`
+	ActiveStatus string `json:"active_status"`
+}
+
+func (q *Queries) IsObjectKeyReferenced(ctx context.Context, arg IsObjectKeyReferencedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isObjectKeyReferenced, arg.UserID, arg.ObjectKey, arg.ActiveStatus)
+	var referenced bool
+	err := row.Scan(&referenced)
+	return referenced, err
+}
+
+const listExpiredQuarantinedObjects = `-- name: ListExpiredQuarantinedObjects :many
+select id, user_id, original_key, quarantine_key, size_bytes, quarantined_at, purge_after, purged_at from quarantined_objects
+where purged_at is null and purge_after < current_timestamp
+order by id
+limit $1
+`
+
+func (q *Queries) ListExpiredQuarantinedObjects(ctx context.Context, pageLimit int32) ([]QuarantinedObject, error) {
+	rows, err := q.db.Query(ctx, listExpiredQuarantinedObjects, pageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuarantinedObject{}
+	for rows.Next() {
+		var i QuarantinedObject
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OriginalKey,
+			&i.QuarantineKey,
+			&i.SizeBytes,
+			&i.QuarantinedAt,
+			&i.PurgeAfter,
+			&i.PurgedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGCAuditEntries = `-- name: ListGCAuditEntries :many
+select id, run_id, action, user_id, object_key, quarantine_key, size_bytes, created_at from object_gc_audit
+where ($1::int is null or user_id = $1::int)
+order by id desc
+limit $3
+offset $2
+`
+
+type ListGCAuditEntriesParams struct {
+	UserID     pgtype.Int4 `json:"user_id"`
+	PageOffset int32       `json:"page_offset"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListGCAuditEntries(ctx context.Context, arg ListGCAuditEntriesParams) ([]ObjectGcAudit, error) {
+	rows, err := q.db.Query(ctx, listGCAuditEntries, arg.UserID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ObjectGcAudit{}
+	for rows.Next() {
+		var i ObjectGcAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.Action,
+			&i.UserID,
+			&i.ObjectKey,
+			&i.QuarantineKey,
+			&i.SizeBytes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuarantinedObjects = `-- name: ListQuarantinedObjects :many
+select id, user_id, original_key, quarantine_key, size_bytes, quarantined_at, purge_after, purged_at from quarantined_objects
+where
+    purged_at is null
+    and
+    ($1::int is null or user_id = $1::int)
+order by id desc
+limit $3
+offset $2
+`
+
+type ListQuarantinedObjectsParams struct {
+	UserID     pgtype.Int4 `json:"user_id"`
+	PageOffset int32       `json:"page_offset"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListQuarantinedObjects(ctx context.Context, arg ListQuarantinedObjectsParams) ([]QuarantinedObject, error) {
+	rows, err := q.db.Query(ctx, listQuarantinedObjects, arg.UserID, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []QuarantinedObject{}
+	for rows.Next() {
+		var i QuarantinedObject
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OriginalKey,
+			&i.QuarantineKey,
+			&i.SizeBytes,
+			&i.QuarantinedAt,
+			&i.PurgeAfter,
+			&i.PurgedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markQuarantinedObjectPurged = `-- name: MarkQuarantinedObjectPurged :exec
+update quarantined_objects
+set purged_at = current_timestamp
+where id = $1
+`
+
+func (q *Queries) MarkQuarantinedObjectPurged(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markQuarantinedObjectPurged, id)
+	return err
+}
@@ -6,29 +6,161 @@ package database
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AddDeletedObjects(ctx context.Context, arg AddDeletedObjectsParams) error
+	AddFileTags(ctx context.Context, arg AddFileTagsParams) error
+	AddUserUsage(ctx context.Context, arg AddUserUsageParams) error
+	// a lease instead of a row lock, the purge talks to the object store for far longer than a transaction should stay open
+	ClaimAccountDeletion(ctx context.Context, arg ClaimAccountDeletionParams) (AccountDeletion, error)
+	CompleteAccountDeletion(ctx context.Context, arg CompleteAccountDeletionParams) error
+	ConsumeEmailVerification(ctx context.Context, arg ConsumeEmailVerificationParams) (int64, error)
+	CountActiveTranscriptJobs(ctx context.Context, arg CountActiveTranscriptJobsParams) (int64, error)
+	CountChildCollections(ctx context.Context, parentID pgtype.Int4) (int64, error)
 	CountEncryptionKeys(ctx context.Context) (int64, error)
+	CountReconcilerRunsSince(ctx context.Context, since time.Time) (int64, error)
 	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAccountDeletion(ctx context.Context, arg CreateAccountDeletionParams) (AccountDeletion, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error)
 	CreateEmptyFile(ctx context.Context, arg CreateEmptyFileParams) (FileRegistry, error)
 	CreateEncryptionKeys(ctx context.Context, arg CreateEncryptionKeysParams) (EncryptionKey, error)
+	CreateGCAuditEntry(ctx context.Context, arg CreateGCAuditEntryParams) error
+	CreateQuarantinedObject(ctx context.Context, arg CreateQuarantinedObjectParams) (QuarantinedObject, error)
+	CreateReconcilerRun(ctx context.Context, dryRun bool) (ReconcilerRun, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
+	CreateTranscriptJob(ctx context.Context, arg CreateTranscriptJobParams) (TranscriptJob, error)
+	CreateTranscriptSegments(ctx context.Context, arg []CreateTranscriptSegmentsParams) (int64, error)
+	CreateUploadSession(ctx context.Context, arg CreateUploadSessionParams) (UploadSession, error)
+	CreateUsageEvent(ctx context.Context, arg CreateUsageEventParams) (UsageEvent, error)
 	CreateUsers(ctx context.Context, email string) (User, error)
-	DeleteAPIKey(ctx context.Context, credential []byte) error
+	DeleteAPIKeyByID(ctx context.Context, arg DeleteAPIKeyByIDParams) (int64, error)
+	DeleteAPIKeysByUser(ctx context.Context, userID int32) (int64, error)
+	DeleteCollection(ctx context.Context, arg DeleteCollectionParams) error
+	DeleteFileTags(ctx context.Context, fileID int32) error
+	// every way a file leaves the registry goes through here, so the usage counters are released with it
 	DeleteFiles(ctx context.Context, arg DeleteFilesParams) error
-	GetAPIKey(ctx context.Context, credential []byte) (GetAPIKeyRow, error)
+	DeletePendingEmailVerifications(ctx context.Context, arg DeletePendingEmailVerificationsParams) error
+	DeleteQuarantinedObject(ctx context.Context, id int32) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, before time.Time) (int64, error)
+	// the updated_at guard leaves the row alone when a request picked it up after the scan
+	DeleteStuckFile(ctx context.Context, arg DeleteStuckFileParams) (int64, error)
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
+	DeleteUser(ctx context.Context, id int32) error
+	DeleteUserCollections(ctx context.Context, userID int32) error
+	DeleteUserEmailVerifications(ctx context.Context, email string) error
+	DeleteUserFiles(ctx context.Context, userID int32) (int64, error)
+	DeleteUserTags(ctx context.Context, userID int32) error
+	DeleteUserTranscriptJobs(ctx context.Context, userID int32) (int64, error)
+	DeleteUserUsageEvents(ctx context.Context, userID int32) error
+	EnqueueMessage(ctx context.Context, arg EnqueueMessageParams) (int64, error)
+	// tags are free-form, naming one on a file creates it
+	EnsureTags(ctx context.Context, arg EnsureTagsParams) error
+	ExpireUserQuarantine(ctx context.Context, userID int32) error
+	FinishReconcilerRun(ctx context.Context, arg FinishReconcilerRunParams) error
+	GetAPIKey(ctx context.Context, keyID string) (GetAPIKeyRow, error)
+	GetAPIKeyByID(ctx context.Context, arg GetAPIKeyByIDParams) (GetAPIKeyByIDRow, error)
+	GetAPIKeyStatus(ctx context.Context, id int32) (GetAPIKeyStatusRow, error)
 	GetActiveKeyBasedOnPurpose(ctx context.Context, purpose string) (GetActiveKeyBasedOnPurposeRow, error)
+	GetActiveKeyIDBasedOnPurpose(ctx context.Context, purpose string) (int32, error)
+	GetCollection(ctx context.Context, arg GetCollectionParams) (Collection, error)
+	GetCollectionByLocking(ctx context.Context, arg GetCollectionByLockingParams) (Collection, error)
+	GetExpiredTrashedFileByLocking(ctx context.Context, arg GetExpiredTrashedFileByLockingParams) (pgtype.Text, error)
+	GetFile(ctx context.Context, arg GetFileParams) (FileRegistry, error)
+	// a trashed file can still lend its object to a new upload, purging it keeps the object while it is referenced
+	GetFileByContentHash(ctx context.Context, arg GetFileByContentHashParams) (FileRegistry, error)
 	GetFileByID(ctx context.Context, arg GetFileByIDParams) (GetFileByIDRow, error)
 	GetFileByName(ctx context.Context, arg GetFileByNameParams) (FileRegistry, error)
 	GetFileByNameByLocking(ctx context.Context, arg GetFileByNameByLockingParams) (FileRegistry, error)
+	GetLatestSucceededJobForFile(ctx context.Context, arg GetLatestSucceededJobForFileParams) (GetLatestSucceededJobForFileRow, error)
+	GetLegacyAPIKey(ctx context.Context, secretHash []byte) (GetLegacyAPIKeyRow, error)
+	GetPendingEmailVerification(ctx context.Context, arg GetPendingEmailVerificationParams) (EmailVerification, error)
+	GetTagByLocking(ctx context.Context, arg GetTagByLockingParams) (Tag, error)
+	GetTranscriptJob(ctx context.Context, arg GetTranscriptJobParams) (GetTranscriptJobRow, error)
+	GetTranscriptJobByLocking(ctx context.Context, id int32) (TranscriptJob, error)
+	GetTrashedFileByLocking(ctx context.Context, arg GetTrashedFileByLockingParams) (FileRegistry, error)
+	GetUploadSession(ctx context.Context, arg GetUploadSessionParams) (UploadSession, error)
+	GetUploadSessionByLocking(ctx context.Context, arg GetUploadSessionByLockingParams) (UploadSession, error)
+	GetUploadSessionByObjectKey(ctx context.Context, objectKey string) (UploadSession, error)
 	GetUser(ctx context.Context, id int32) (string, error)
+	GetUserDetails(ctx context.Context, id int32) (GetUserDetailsRow, error)
+	GetUserPlan(ctx context.Context, userID int32) (Plan, error)
+	GetUserStatus(ctx context.Context, id int32) (GetUserStatusRow, error)
+	GetUserUsage(ctx context.Context, userID int32) (UserUsage, error)
 	GetUsersID(ctx context.Context, email string) (int32, error)
-	ListAllFiles(ctx context.Context, arg ListAllFilesParams) ([]ListAllFilesRow, error)
-	ListConflictingFiles(ctx context.Context, arg ListConflictingFilesParams) ([]ListConflictingFilesRow, error)
-	UnlockAndLockFile(ctx context.Context, arg UnlockAndLockFileParams) (FileRegistry, error)
+	GetVerificationKey(ctx context.Context, arg GetVerificationKeyParams) (GetVerificationKeyRow, error)
+	IncrementEmailVerificationAttempts(ctx context.Context, id int32) (int32, error)
+	IsObjectKeyReferenced(ctx context.Context, arg IsObjectKeyReferencedParams) (bool, error)
+	ListAPIKeys(ctx context.Context, userID int32) ([]ListAPIKeysRow, error)
+	ListAPIKeysForResign(ctx context.Context, signingKeyID int32) ([]ListAPIKeysForResignRow, error)
+	// chunks of a resumable upload live under <object_key>.parts/ and the verified copy of an upload is written to
+	// <object_key>.<format> before the session completes, an active session keeps all of them
+	ListActiveUploadPrefixes(ctx context.Context, arg ListActiveUploadPrefixesParams) ([]string, error)
+	ListActiveUserIDs(ctx context.Context, arg ListActiveUserIDsParams) ([]int32, error)
+	// the collection and every collection above it, the root first
+	ListCollectionAncestry(ctx context.Context, arg ListCollectionAncestryParams) ([]int32, error)
+	ListCollectionFilesForTranscript(ctx context.Context, arg ListCollectionFilesForTranscriptParams) ([]ListCollectionFilesForTranscriptRow, error)
+	// the collection and every collection below it, depth counts the collection itself as 1
+	ListCollectionSubtree(ctx context.Context, arg ListCollectionSubtreeParams) ([]ListCollectionSubtreeRow, error)
+	ListCollections(ctx context.Context, userID int32) ([]ListCollectionsRow, error)
+	ListExpiredQuarantinedObjects(ctx context.Context, pageLimit int32) ([]QuarantinedObject, error)
+	ListExpiredTrashedFiles(ctx context.Context, arg ListExpiredTrashedFilesParams) ([]ListExpiredTrashedFilesRow, error)
+	ListExportFiles(ctx context.Context, userID int32) ([]ListExportFilesRow, error)
+	ListExportTranscriptJobs(ctx context.Context, userID int32) ([]ListExportTranscriptJobsRow, error)
+	ListFiles(ctx context.Context, arg ListFilesParams) ([]ListFilesRow, error)
+	ListGCAuditEntries(ctx context.Context, arg ListGCAuditEntriesParams) ([]ObjectGcAudit, error)
+	ListQuarantinedObjects(ctx context.Context, arg ListQuarantinedObjectsParams) ([]QuarantinedObject, error)
+	ListReconcilerRuns(ctx context.Context, pageLimit int32) ([]ReconcilerRun, error)
+	// objects of uploads still in flight are referenced by their session until the registry row takes the key over,
+	// a finished session no longer protects its key since a client can still write to it until the signed url expires
+	ListReferencedObjectKeys(ctx context.Context, arg ListReferencedObjectKeysParams) ([]string, error)
+	// every state other than unlocked SUCCESS is transient, rows left in one past stuck_before were abandoned by their request
+	ListStuckFiles(ctx context.Context, arg ListStuckFilesParams) ([]ListStuckFilesRow, error)
+	ListTags(ctx context.Context, userID int32) ([]ListTagsRow, error)
+	ListTagsByName(ctx context.Context, arg ListTagsByNameParams) ([]Tag, error)
+	ListTranscriptJobs(ctx context.Context, arg ListTranscriptJobsParams) ([]ListTranscriptJobsRow, error)
+	ListTranscriptSegments(ctx context.Context, jobID int32) ([]ListTranscriptSegmentsRow, error)
+	ListTrashedFiles(ctx context.Context, arg ListTrashedFilesParams) ([]ListTrashedFilesRow, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
+	ListVerificationKeys(ctx context.Context, purpose string) ([]ListVerificationKeysRow, error)
+	LockActiveKeyBasedOnPurpose(ctx context.Context, purpose string) (int32, error)
+	LockObjectKeyReferences(ctx context.Context, arg LockObjectKeyReferencesParams) ([]int32, error)
+	// held until the transaction ends, it serialises instances deciding whether a pass is due
+	LockReconcilerRuns(ctx context.Context) error
+	LockUserFiles(ctx context.Context, arg LockUserFilesParams) error
+	// creates the counters on first use, the no-op update takes the row lock which serialises quota checks of a user
+	LockUserUsage(ctx context.Context, userID int32) (UserUsage, error)
+	MarkQuarantinedObjectPurged(ctx context.Context, id int32) error
+	MarkUserDeleted(ctx context.Context, id int32) (User, error)
+	NotifyMessage(ctx context.Context, arg NotifyMessageParams) error
+	PromoteUsersByEmail(ctx context.Context, arg PromoteUsersByEmailParams) ([]PromoteUsersByEmailRow, error)
+	RecordAccountDeletionFailure(ctx context.Context, arg RecordAccountDeletionFailureParams) error
+	RepairStuckFile(ctx context.Context, arg RepairStuckFileParams) (int64, error)
+	RestoreFile(ctx context.Context, arg RestoreFileParams) (FileRegistry, error)
+	SetFileCollection(ctx context.Context, arg SetFileCollectionParams) (FileRegistry, error)
+	// usage of failed transcript jobs is not billed
+	SumUsageSince(ctx context.Context, arg SumUsageSinceParams) (int64, error)
+	SuspendUser(ctx context.Context, arg SuspendUserParams) (int64, error)
+	// refills the bucket for the time elapsed since its last use and takes cost tokens when that many are available
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error)
+	TouchAPIKey(ctx context.Context, id int32) error
+	TrashFile(ctx context.Context, arg TrashFileParams) (FileRegistry, error)
+	UnsuspendUser(ctx context.Context, id int32) (int64, error)
+	UpdateAPIKeySignature(ctx context.Context, arg UpdateAPIKeySignatureParams) error
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
+	UpdateFileAudioMetadata(ctx context.Context, arg UpdateFileAudioMetadataParams) error
 	UpdateFileMetadata(ctx context.Context, arg UpdateFileMetadataParams) (FileRegistry, error)
 	UpdateFileName(ctx context.Context, arg UpdateFileNameParams) (FileRegistry, error)
 	UpdateKeyStatus(ctx context.Context, arg UpdateKeyStatusParams) error
+	UpdateTagName(ctx context.Context, arg UpdateTagNameParams) (Tag, error)
+	UpdateTranscriptJobStatus(ctx context.Context, arg UpdateTranscriptJobStatusParams) (TranscriptJob, error)
+	UpdateUploadSessionOffset(ctx context.Context, arg UpdateUploadSessionOffsetParams) (UploadSession, error)
+	UpdateUploadSessionStatus(ctx context.Context, arg UpdateUploadSessionStatusParams) (UploadSession, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
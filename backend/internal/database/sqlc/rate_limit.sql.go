@@ -0,0 +1,73 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: rate_limit.sql
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+const deleteStaleRateLimitBuckets = `-- name: DeleteStaleRateLimitBuckets :execrows
+delete from rate_limit_buckets
+where updated_at < $1
+`
+
+func (q *Queries) DeleteStaleRateLimitBuckets(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStaleRateLimitBuckets, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+insert into rate_limit_buckets (
+    key,
+    tokens,
+    allowed,
+    updated_at
+) values (
+    $1,
+    case when $2::float8 >= $3::float8 then $2::float8 - $3::float8 else $2::float8 end,
+    $2::float8 >= $3::float8,
+    current_timestamp
+)
+on conflict (key) do update
+set
+    tokens = case
+        when least($2::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * $4::float8) >= $3::float8
+        then least($2::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * $4::float8) - $3::float8
+        else least($2::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * $4::float8)
+    end,
+    allowed = least($2::float8, rate_limit_buckets.tokens + extract(epoch from current_timestamp - rate_limit_buckets.updated_at)::float8 * $4::float8) >= $3::float8,
+    updated_at = current_timestamp
+returning tokens, allowed
+`
+
+type TakeRateLimitTokenParams struct {
+	Key   string  `json:"key"`
+	Burst float64 `json:"burst"`
+	Cost  float64 `json:"cost"`
+	Rate  float64 `json:"rate"`
+}
+
+type TakeRateLimitTokenRow struct {
+	Tokens  float64 `json:"tokens"`
+	Allowed bool    `json:"allowed"`
+}
+
+// refills the bucket for the time elapsed since its last use and takes cost tokens when that many are available
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error) {
+	row := q.db.QueryRow(ctx, takeRateLimitToken,
+		arg.Key,
+		arg.Burst,
+		arg.Cost,
+		arg.Rate,
+	)
+	var i TakeRateLimitTokenRow
+	err := row.Scan(&i.Tokens, &i.Allowed)
+	return i, err
+}
@@ -0,0 +1,373 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: reconciler.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countReconcilerRunsSince = `-- name: CountReconcilerRunsSince :one
+select count(*) from reconciler_runs
+where started_at > $1
+`
+
+func (q *Queries) CountReconcilerRunsSince(ctx context.Context, since time.Time) (int64, error) {
+	row := q.db.QueryRow(ctx, countReconcilerRunsSince, since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createReconcilerRun = `-- name: CreateReconcilerRun :one
+insert into reconciler_runs (
+    dry_run
+) values (
+    $1
+) returning id, dry_run, files_scanned, files_repaired, files_deleted, orphans_found, errors, started_at, finished_at, objects_quarantined, objects_purged
+`
+
+func (q *Queries) CreateReconcilerRun(ctx context.Context, dryRun bool) (ReconcilerRun, error) {
+	row := q.db.QueryRow(ctx, createReconcilerRun, dryRun)
+	var i ReconcilerRun
+	err := row.Scan(
+		&i.ID,
+		&i.DryRun,
+		&i.FilesScanned,
+		&i.FilesRepaired,
+		&i.FilesDeleted,
+		&i.OrphansFound,
+		&i.Errors,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ObjectsQuarantined,
+		&i.ObjectsPurged,
+	)
+	return i, err
+}
+
+const deleteStuckFile = `-- name: DeleteStuckFile :execrows
+with deleted as (
+    delete from file_registry
+    where
+        file_registry.id = $1
+        and
+        file_registry.updated_at = $2
+    returning file_registry.user_id, file_registry.size_bytes
+)
+insert into user_usage (user_id, storage_bytes, file_count)
+select deleted.user_id, -coalesce(deleted.size_bytes, 0), -1 from deleted
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp
+`
+
+type DeleteStuckFileParams struct {
+	ID        int32              `json:"id"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+// the updated_at guard leaves the row alone when a request picked it up after the scan
+func (q *Queries) DeleteStuckFile(ctx context.Context, arg DeleteStuckFileParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStuckFile, arg.ID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishReconcilerRun = `-- name: FinishReconcilerRun :exec
+update reconciler_runs
+set
+    files_scanned = $1,
+    files_repaired = $2,
+    files_deleted = $3,
+    orphans_found = $4,
+    objects_quarantined = $5,
+    objects_purged = $6,
+    errors = $7,
+    finished_at = current_timestamp
+where id = $8
+`
+
+type FinishReconcilerRunParams struct {
+	FilesScanned       int32 `json:"files_scanned"`
+	FilesRepaired      int32 `json:"files_repaired"`
+	FilesDeleted       int32 `json:"files_deleted"`
+	OrphansFound       int32 `json:"orphans_found"`
+	ObjectsQuarantined int32 `json:"objects_quarantined"`
+	ObjectsPurged      int32 `json:"objects_purged"`
+	Errors             int32 `json:"errors"`
+	ID                 int32 `json:"id"`
+}
+
+func (q *Queries) FinishReconcilerRun(ctx context.Context, arg FinishReconcilerRunParams) error {
+	_, err := q.db.Exec(ctx, finishReconcilerRun,
+		arg.FilesScanned,
+		arg.FilesRepaired,
+		arg.FilesDeleted,
+		arg.OrphansFound,
+		arg.ObjectsQuarantined,
+		arg.ObjectsPurged,
+		arg.Errors,
+		arg.ID,
+	)
+	return err
+}
+
+const listActiveUploadPrefixes = `-- name: ListActiveUploadPrefixes :many
+select (object_key || '.')::varchar as upload_prefix from upload_sessions
+where
+    upload_sessions.user_id = $1
+    and upload_sessions.status = $2
+    and upload_sessions.expires_at > current_timestamp
+`
+
+type ListActiveUploadPrefixesParams struct {
+	UserID       int32  `json:"user_id"`
+	ActiveStatus string `json:"active_status"`
+}
+
+// chunks of a resumable upload live under <object_key>.parts/ and the verified copy of an upload is written to
+// <object_key>.<format> before the session completes, an active session keeps all of them
+func (q *Queries) ListActiveUploadPrefixes(ctx context.Context, arg ListActiveUploadPrefixesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listActiveUploadPrefixes, arg.UserID, arg.ActiveStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var upload_prefix string
+		if err := rows.Scan(&upload_prefix); err != nil {
+			return nil, err
+		}
+		items = append(items, upload_prefix)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveUserIDs = `-- name: ListActiveUserIDs :many
+select id from users
+where id > $1 and deleted_at is null
+order by id
+limit $2
+`
+
+type ListActiveUserIDsParams struct {
+	AfterID   int32 `json:"after_id"`
+	PageLimit int32 `json:"page_limit"`
+}
+
+func (q *Queries) ListActiveUserIDs(ctx context.Context, arg ListActiveUserIDsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listActiveUserIDs, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReconcilerRuns = `-- name: ListReconcilerRuns :many
+select id, dry_run, files_scanned, files_repaired, files_deleted, orphans_found, errors, started_at, finished_at, objects_quarantined, objects_purged from reconciler_runs
+order by started_at desc, id desc
+limit $1
+`
+
+func (q *Queries) ListReconcilerRuns(ctx context.Context, pageLimit int32) ([]ReconcilerRun, error) {
+	rows, err := q.db.Query(ctx, listReconcilerRuns, pageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReconcilerRun{}
+	for rows.Next() {
+		var i ReconcilerRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.DryRun,
+			&i.FilesScanned,
+			&i.FilesRepaired,
+			&i.FilesDeleted,
+			&i.OrphansFound,
+			&i.Errors,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.ObjectsQuarantined,
+			&i.ObjectsPurged,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReferencedObjectKeys = `-- name: ListReferencedObjectKeys :many
+select object_key::varchar from file_registry
+where file_registry.user_id = $1 and object_key is not null
+union
+select object_key::varchar from upload_sessions
+where upload_sessions.user_id = $1 and upload_sessions.status = $2
+`
+
+type ListReferencedObjectKeysParams struct {
+	UserID       int32  `json:"user_id"`
+	ActiveStatus string `json:"active_status"`
+}
+
+// objects of uploads still in flight are referenced by their session until the registry row takes the key over,
+// a finished session no longer protects its key since a client can still write to it until the signed url expires
+func (q *Queries) ListReferencedObjectKeys(ctx context.Context, arg ListReferencedObjectKeysParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listReferencedObjectKeys, arg.UserID, arg.ActiveStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var object_key string
+		if err := rows.Scan(&object_key); err != nil {
+			return nil, err
+		}
+		items = append(items, object_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStuckFiles = `-- name: ListStuckFiles :many
+select f.id, f.user_id, f.object_key, f.upload_status, f.lock_status, f.updated_at
+from file_registry f
+join users u on u.id = f.user_id
+where
+    (f.lock_status or f.upload_status <> $1)
+    and f.updated_at < $2
+    and f.id > $3
+    and u.deleted_at is null
+    and not exists (
+        select 1 from upload_sessions s
+        where
+            s.file_id = f.id
+            and s.status = $4
+            and s.expires_at > current_timestamp
+    )
+order by f.id
+limit $5
+`
+
+type ListStuckFilesParams struct {
+	SuccessStatus       string             `json:"success_status"`
+	StuckBefore         pgtype.Timestamptz `json:"stuck_before"`
+	AfterID             int32              `json:"after_id"`
+	ActiveSessionStatus string             `json:"active_session_status"`
+	PageLimit           int32              `json:"page_limit"`
+}
+
+type ListStuckFilesRow struct {
+	ID           int32              `json:"id"`
+	UserID       int32              `json:"user_id"`
+	ObjectKey    pgtype.Text        `json:"object_key"`
+	UploadStatus string             `json:"upload_status"`
+	LockStatus   bool               `json:"lock_status"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+// every state other than unlocked SUCCESS is transient, rows left in one past stuck_before were abandoned by their request
+func (q *Queries) ListStuckFiles(ctx context.Context, arg ListStuckFilesParams) ([]ListStuckFilesRow, error) {
+	rows, err := q.db.Query(ctx, listStuckFiles,
+		arg.SuccessStatus,
+		arg.StuckBefore,
+		arg.AfterID,
+		arg.ActiveSessionStatus,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStuckFilesRow{}
+	for rows.Next() {
+		var i ListStuckFilesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ObjectKey,
+			&i.UploadStatus,
+			&i.LockStatus,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockReconcilerRuns = `-- name: LockReconcilerRuns :exec
+select pg_advisory_xact_lock(hashtext('reconciler_runs'))
+`
+
+// held until the transaction ends, it serialises instances deciding whether a pass is due
+func (q *Queries) LockReconcilerRuns(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockReconcilerRuns)
+	return err
+}
+
+const repairStuckFile = `-- name: RepairStuckFile :execrows
+update file_registry
+set
+    upload_status = $1,
+    lock_status = $2,
+    updated_at = current_timestamp
+where id = $3 and updated_at = $4
+`
+
+type RepairStuckFileParams struct {
+	UploadStatus string             `json:"upload_status"`
+	LockStatus   bool               `json:"lock_status"`
+	ID           int32              `json:"id"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) RepairStuckFile(ctx context.Context, arg RepairStuckFileParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repairStuckFile,
+		arg.UploadStatus,
+		arg.LockStatus,
+		arg.ID,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,10 +13,28 @@ type Store interface {
 	CreateEmptyFileTx(ctx context.Context, arg CreateEmptyFileParams) (*FileRegistry, error)
 	UpdateMetadataFileTx(ctx context.Context, arg UpdateFileMetadataTxParams) (*FileRegistry, error)
 	UpdateFileNameTx(ctx context.Context, userID int32, oldFilename, newFilename string) (*FileRegistry, error)
-	LockFileTx(ctx context.Context, userID int32, filename string) (*FileRegistry, error)
-	UnlockMultipleFilesTx(ctx context.Context, userID int32, ids []int32) error
-	DeleteFileTx(ctx context.Context, userId, id int32, updatedAt pgtype.Timestamptz) error
-	DeleteMultipleFilesTx(ctx context.Context, userID int32, ids []int32) error
+	TrashFileTx(ctx context.Context, userID int32, filename string) (*FileRegistry, error)
+	RestoreFileTx(ctx context.Context, userID, id int32) (*FileRegistry, error)
+	PurgeTrashedFileTx(ctx context.Context, userID, id int32, trashedBefore pgtype.Timestamptz) (string, error)
+	DeleteFileTx(ctx context.Context, userId, id int32, updatedAt pgtype.Timestamptz) (bool, error)
+	EnqueueMessageTx(ctx context.Context, topic string, payload []byte) (int64, error)
+	CreateTranscriptJobTx(ctx context.Context, userID, fileID int32) (*TranscriptJob, error)
+	UpdateTranscriptJobStatusTx(ctx context.Context, arg UpdateTranscriptJobStatusTxParams) (*TranscriptJob, error)
+	CreateUploadSessionTx(ctx context.Context, arg CreateUploadSessionTxParams) (*UploadSession, error)
+	AdvanceUploadSessionTx(ctx context.Context, userID int32, id string, expectedOffset, size int64, partKey string) (*UploadSession, error)
+	CompleteUploadSessionTx(ctx context.Context, arg CompleteUploadSessionTxParams) (*FileRegistry, error)
+	AbortUploadSessionTx(ctx context.Context, userID int32, id string) (*UploadSession, error)
+	RotateSigningKeyTx(ctx context.Context, arg RotateSigningKeyTxParams) (*RotateSigningKeyTxResult, error)
+	ConfirmRegistrationTx(ctx context.Context, arg ConfirmRegistrationTxParams) (*User, error)
+	RecoverAPIKeyTx(ctx context.Context, arg RecoverAPIKeyTxParams) (*RecoverAPIKeyTxResult, error)
+	RequestAccountDeletionTx(ctx context.Context, userID int32) (*AccountDeletion, error)
+	PurgeAccountTx(ctx context.Context, deletionID, userID int32) (*PurgeAccountTxResult, error)
+	StartReconcilerRunTx(ctx context.Context, dryRun bool, since time.Time) (*ReconcilerRun, error)
+	PurgeQuarantinedObjectTx(ctx context.Context, object QuarantinedObject, runID pgtype.Int4) error
+	CreateCollectionTx(ctx context.Context, userID int32, parentID pgtype.Int4, name string) (*Collection, error)
+	UpdateCollectionTx(ctx context.Context, userID, id int32, parentID pgtype.Int4, name string) (*Collection, error)
+	DeleteCollectionTx(ctx context.Context, userID, id int32) error
+	SetFileTagsTx(ctx context.Context, userID, fileID int32, names []string) ([]Tag, error)
 }
 
 type SQLStore struct {
@@ -0,0 +1,242 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: tag.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addFileTags = `-- name: AddFileTags :exec
+insert into file_tags (file_id, tag_id)
+select $1, unnest($2::int[])
+on conflict do nothing
+`
+
+type AddFileTagsParams struct {
+	FileID int32   `json:"file_id"`
+	TagIds []int32 `json:"tag_ids"`
+}
+
+func (q *Queries) AddFileTags(ctx context.Context, arg AddFileTagsParams) error {
+	_, err := q.db.Exec(ctx, addFileTags, arg.FileID, arg.TagIds)
+	return err
+}
+
+const createTag = `-- name: CreateTag :one
+insert into tags (
+    user_id,
+    name
+) values (
+    $1, $2
+) returning id, user_id, name, created_at
+`
+
+type CreateTagParams struct {
+	UserID int32  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, createTag, arg.UserID, arg.Name)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteFileTags = `-- name: DeleteFileTags :exec
+delete from file_tags
+where file_id = $1
+`
+
+func (q *Queries) DeleteFileTags(ctx context.Context, fileID int32) error {
+	_, err := q.db.Exec(ctx, deleteFileTags, fileID)
+	return err
+}
+
+const deleteTag = `-- name: DeleteTag :execrows
+delete from tags
+where id = $1 and user_id = $2
+`
+
+type DeleteTagParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTag, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserTags = `-- name: DeleteUserTags :exec
+delete from tags
+where user_id = $1
+`
+
+func (q *Queries) DeleteUserTags(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteUserTags, userID)
+	return err
+}
+
+const ensureTags = `-- name: EnsureTags :exec
+insert into tags (user_id, name)
+select $1, unnest($2::text[])
+on conflict (user_id, lower(name)) do nothing
+`
+
+type EnsureTagsParams struct {
+	UserID int32    `json:"user_id"`
+	Names  []string `json:"names"`
+}
+
+// tags are free-form, naming one on a file creates it
+func (q *Queries) EnsureTags(ctx context.Context, arg EnsureTagsParams) error {
+	_, err := q.db.Exec(ctx, ensureTags, arg.UserID, arg.Names)
+	return err
+}
+
+const getTagByLocking = `-- name: GetTagByLocking :one
+select id, user_id, name, created_at from tags
+where id = $1 and user_id = $2
+for update
+`
+
+type GetTagByLockingParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+func (q *Queries) GetTagByLocking(ctx context.Context, arg GetTagByLockingParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByLocking, arg.ID, arg.UserID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listTags = `-- name: ListTags :many
+select
+    t.id,
+    t.name,
+    t.created_at,
+    (
+        select count(*) from file_tags ft
+        join file_registry f on f.id = ft.file_id
+        where ft.tag_id = t.id and f.deleted_at is null
+    ) as file_count
+from tags t
+where t.user_id = $1
+order by lower(t.name), t.id
+`
+
+type ListTagsRow struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	FileCount int64              `json:"file_count"`
+}
+
+func (q *Queries) ListTags(ctx context.Context, userID int32) ([]ListTagsRow, error) {
+	rows, err := q.db.Query(ctx, listTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagsRow{}
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.FileCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTagsByName = `-- name: ListTagsByName :many
+select id, user_id, name, created_at from tags
+where
+    user_id = $1
+    and lower(name) in (select lower(n) from unnest($2::text[]) as n)
+order by lower(name), id
+`
+
+type ListTagsByNameParams struct {
+	UserID int32    `json:"user_id"`
+	Names  []string `json:"names"`
+}
+
+func (q *Queries) ListTagsByName(ctx context.Context, arg ListTagsByNameParams) ([]Tag, error) {
+	rows, err := q.db.Query(ctx, listTagsByName, arg.UserID, arg.Names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tag{}
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTagName = `-- name: UpdateTagName :one
+update tags
+set name = $1
+where id = $2 and user_id = $3
+returning id, user_id, name, created_at
+`
+
+type UpdateTagNameParams struct {
+	Name   string `json:"name"`
+	ID     int32  `json:"id"`
+	UserID int32  `json:"user_id"`
+}
+
+func (q *Queries) UpdateTagName(ctx context.Context, arg UpdateTagNameParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, updateTagName, arg.Name, arg.ID, arg.UserID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
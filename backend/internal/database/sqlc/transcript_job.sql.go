@@ -0,0 +1,260 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: transcript_job.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countActiveTranscriptJobs = `-- name: CountActiveTranscriptJobs :one
+select count(*) from transcript_jobs
+where
+    file_id = $1
+    and
+    status in ($2, $3)
+`
+
+type CountActiveTranscriptJobsParams struct {
+	FileID           int32  `json:"file_id"`
+	QueuedStatus     string `json:"queued_status"`
+	ProcessingStatus string `json:"processing_status"`
+}
+
+func (q *Queries) CountActiveTranscriptJobs(ctx context.Context, arg CountActiveTranscriptJobsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveTranscriptJobs, arg.FileID, arg.QueuedStatus, arg.ProcessingStatus)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createTranscriptJob = `-- name: CreateTranscriptJob :one
+insert into transcript_jobs (
+    user_id,
+    file_id,
+    status
+) values (
+    $1, $2, $3
+) returning id, user_id, file_id, status, error_message, started_at, completed_at, created_at, updated_at, progress, transcript_object_key
+`
+
+type CreateTranscriptJobParams struct {
+	UserID int32  `json:"user_id"`
+	FileID int32  `json:"file_id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) CreateTranscriptJob(ctx context.Context, arg CreateTranscriptJobParams) (TranscriptJob, error) {
+	row := q.db.QueryRow(ctx, createTranscriptJob, arg.UserID, arg.FileID, arg.Status)
+	var i TranscriptJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Progress,
+		&i.TranscriptObjectKey,
+	)
+	return i, err
+}
+
+const getTranscriptJob = `-- name: GetTranscriptJob :one
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where j.id = $1 and j.user_id = $2
+`
+
+type GetTranscriptJobParams struct {
+	ID     int32 `json:"id"`
+	UserID int32 `json:"user_id"`
+}
+
+type GetTranscriptJobRow struct {
+	ID           int32              `json:"id"`
+	FileID       int32              `json:"file_id"`
+	FileName     string             `json:"file_name"`
+	Status       string             `json:"status"`
+	Progress     int32              `json:"progress"`
+	ErrorMessage pgtype.Text        `json:"error_message"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetTranscriptJob(ctx context.Context, arg GetTranscriptJobParams) (GetTranscriptJobRow, error) {
+	row := q.db.QueryRow(ctx, getTranscriptJob, arg.ID, arg.UserID)
+	var i GetTranscriptJobRow
+	err := row.Scan(
+		&i.ID,
+		&i.FileID,
+		&i.FileName,
+		&i.Status,
+		&i.Progress,
+		&i.ErrorMessage,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTranscriptJobByLocking = `-- name: GetTranscriptJobByLocking :one
+select id, user_id, file_id, status, error_message, started_at, completed_at, created_at, updated_at, progress, transcript_object_key from transcript_jobs
+where id = $1
+for update
+`
+
+func (q *Queries) GetTranscriptJobByLocking(ctx context.Context, id int32) (TranscriptJob, error) {
+	row := q.db.QueryRow(ctx, getTranscriptJobByLocking, id)
+	var i TranscriptJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Progress,
+		&i.TranscriptObjectKey,
+	)
+	return i, err
+}
+
+const listTranscriptJobs = `-- name: ListTranscriptJobs :many
+select
+    j.id, j.file_id, f.file_name, j.status, j.progress, j.error_message,
+    j.started_at, j.completed_at, j.created_at, j.updated_at
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where
+    j.user_id = $1
+    and
+    ($2::varchar is null or j.status = $2::varchar)
+order by j.created_at desc, j.id desc
+limit $4
+offset $3
+`
+
+type ListTranscriptJobsParams struct {
+	UserID     int32       `json:"user_id"`
+	Status     pgtype.Text `json:"status"`
+	PageOffset int32       `json:"page_offset"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+type ListTranscriptJobsRow struct {
+	ID           int32              `json:"id"`
+	FileID       int32              `json:"file_id"`
+	FileName     string             `json:"file_name"`
+	Status       string             `json:"status"`
+	Progress     int32              `json:"progress"`
+	ErrorMessage pgtype.Text        `json:"error_message"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	CompletedAt  pgtype.Timestamptz `json:"completed_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) ListTranscriptJobs(ctx context.Context, arg ListTranscriptJobsParams) ([]ListTranscriptJobsRow, error) {
+	rows, err := q.db.Query(ctx, listTranscriptJobs,
+		arg.UserID,
+		arg.Status,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTranscriptJobsRow{}
+	for rows.Next() {
+		var i ListTranscriptJobsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileID,
+			&i.FileName,
+			&i.Status,
+			&i.Progress,
+			&i.ErrorMessage,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTranscriptJobStatus = `-- name: UpdateTranscriptJobStatus :one
+update transcript_jobs
+set
+    status = $1,
+    error_message = $2,
+    progress = coalesce($3, progress),
+    transcript_object_key = coalesce($4, transcript_object_key),
+    started_at = coalesce($5, started_at),
+    completed_at = coalesce($6, completed_at),
+    updated_at = current_timestamp
+where id = $7
+returning id, user_id, file_id, status, error_message, started_at, completed_at, created_at, updated_at, progress, transcript_object_key
+`
+
+type UpdateTranscriptJobStatusParams struct {
+	Status              string             `json:"status"`
+	ErrorMessage        pgtype.Text        `json:"error_message"`
+	Progress            pgtype.Int4        `json:"progress"`
+	TranscriptObjectKey pgtype.Text        `json:"transcript_object_key"`
+	StartedAt           pgtype.Timestamptz `json:"started_at"`
+	CompletedAt         pgtype.Timestamptz `json:"completed_at"`
+	ID                  int32              `json:"id"`
+}
+
+func (q *Queries) UpdateTranscriptJobStatus(ctx context.Context, arg UpdateTranscriptJobStatusParams) (TranscriptJob, error) {
+	row := q.db.QueryRow(ctx, updateTranscriptJobStatus,
+		arg.Status,
+		arg.ErrorMessage,
+		arg.Progress,
+		arg.TranscriptObjectKey,
+		arg.StartedAt,
+		arg.CompletedAt,
+		arg.ID,
+	)
+	var i TranscriptJob
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Progress,
+		&i.TranscriptObjectKey,
+	)
+	return i, err
+}
@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: transcript_segment.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateTranscriptSegmentsParams struct {
+	JobID        int32       `json:"job_id"`
+	SegmentIndex int32       `json:"segment_index"`
+	StartMs      int64       `json:"start_ms"`
+	EndMs        int64       `json:"end_ms"`
+	Speaker      pgtype.Text `json:"speaker"`
+	Content      string      `json:"content"`
+}
+
+const getLatestSucceededJobForFile = `-- name: GetLatestSucceededJobForFile :one
+select j.id, f.file_name
+from transcript_jobs j
+join file_registry f on f.id = j.file_id
+where
+    j.file_id = $1
+    and
+    j.user_id = $2
+    and
+    j.status = $3
+order by j.completed_at desc, j.id desc
+limit 1
+`
+
+type GetLatestSucceededJobForFileParams struct {
+	FileID int32  `json:"file_id"`
+	UserID int32  `json:"user_id"`
+	Status string `json:"status"`
+}
+
+type GetLatestSucceededJobForFileRow struct {
+	ID       int32  `json:"id"`
+	FileName string `json:"file_name"`
+}
+
+func (q *Queries) GetLatestSucceededJobForFile(ctx context.Context, arg GetLatestSucceededJobForFileParams) (GetLatestSucceededJobForFileRow, error) {
+	row := q.db.QueryRow(ctx, getLatestSucceededJobForFile, arg.FileID, arg.UserID, arg.Status)
+	var i GetLatestSucceededJobForFileRow
+	err := row.Scan(&i.ID, &i.FileName)
+	return i, err
+}
+
+const listTranscriptSegments = `-- name: ListTranscriptSegments :many
+select segment_index, start_ms, end_ms, speaker, content
+from transcript_segments
+where job_id = $1
+order by segment_index
+`
+
+type ListTranscriptSegmentsRow struct {
+	SegmentIndex int32       `json:"segment_index"`
+	StartMs      int64       `json:"start_ms"`
+	EndMs        int64       `json:"end_ms"`
+	Speaker      pgtype.Text `json:"speaker"`
+	Content      string      `json:"content"`
+}
+
+func (q *Queries) ListTranscriptSegments(ctx context.Context, jobID int32) ([]ListTranscriptSegmentsRow, error) {
+	rows, err := q.db.Query(ctx, listTranscriptSegments, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTranscriptSegmentsRow{}
+	for rows.Next() {
+		var i ListTranscriptSegmentsRow
+		if err := rows.Scan(
+			&i.SegmentIndex,
+			&i.StartMs,
+			&i.EndMs,
+			&i.Speaker,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
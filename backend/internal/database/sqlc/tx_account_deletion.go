@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+type PurgeAccountTxResult struct {
+	FilesDeleted int64
+	JobsDeleted  int64
+}
+
+// RequestAccountDeletionTx marks the user deleted and revokes every key, the rows and objects are left to the purge
+// which the returned tombstone tracks.
+func (store *SQLStore) RequestAccountDeletionTx(ctx context.Context, userID int32) (*AccountDeletion, error) {
+	var deletion AccountDeletion
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err := q.MarkUserDeleted(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrResourceConflict
+			}
+			return err
+		}
+
+		if _, err := q.DeleteAPIKeysByUser(ctx, userID); err != nil {
+			return err
+		}
+
+		// file tags went with the files, files no longer hold on to any collection
+		if err := q.DeleteUserTags(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUserCollections(ctx, userID); err != nil {
+			return err
+		}
+
+		emailHash := sha256.Sum256([]byte(strings.ToLower(user.Email)))
+
+		deletion, err = q.CreateAccountDeletion(ctx, CreateAccountDeletionParams{
+			UserID:    userID,
+			EmailHash: emailHash[:],
+			Status:    DeletionPending,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteUserEmailVerifications(ctx, user.Email)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &deletion, nil
+}
+
+// PurgeAccountTx removes every row of the user once their objects are gone and completes the tombstone,
+// segments and upload sessions go through the cascades of their jobs and files.
+func (store *SQLStore) PurgeAccountTx(ctx context.Context, deletionID, userID int32) (*PurgeAccountTxResult, error) {
+	var result PurgeAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if err := q.DeleteUserUsageEvents(ctx, userID); err != nil {
+			return err
+		}
+
+		result.JobsDeleted, err = q.DeleteUserTranscriptJobs(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		result.FilesDeleted, err = q.DeleteUserFiles(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := q.DeleteAPIKeysByUser(ctx, userID); err != nil {
+			return err
+		}
+
+		// file tags went with the files, files no longer hold on to any collection
+		if err := q.DeleteUserTags(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUserCollections(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUser(ctx, userID); err != nil {
+			return err
+		}
+
+		// whatever the garbage collector quarantined of the user goes with the next purge instead of after the retention
+		if err := q.ExpireUserQuarantine(ctx, userID); err != nil {
+			return err
+		}
+
+		return q.CompleteAccountDeletion(ctx, CompleteAccountDeletionParams{
+			ID:           deletionID,
+			Status:       DeletionCompleted,
+			FilesDeleted: int32(result.FilesDeleted),
+			JobsDeleted:  int32(result.JobsDeleted),
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
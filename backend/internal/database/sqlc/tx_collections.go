@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// lockParentCollection locks the collection a child is placed under, so it cannot be deleted meanwhile,
+// and returns its ancestry with the root first.
+func lockParentCollection(ctx context.Context, q *Queries, userID, parentID int32) ([]int32, error) {
+	_, err := q.GetCollectionByLocking(ctx, GetCollectionByLockingParams{
+		ID:     parentID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, custom_errors.ErrNoRecordFound
+		}
+		return nil, err
+	}
+
+	return q.ListCollectionAncestry(ctx, ListCollectionAncestryParams{
+		ID:     parentID,
+		UserID: userID,
+	})
+}
+
+func (store *SQLStore) CreateCollectionTx(ctx context.Context, userID int32, parentID pgtype.Int4, name string) (*Collection, error) {
+	var collection Collection
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if parentID.Valid {
+			ancestry, err := lockParentCollection(ctx, q, userID, parentID.Int32)
+			if err != nil {
+				return err
+			}
+
+			if len(ancestry)+1 > MaxCollectionDepth {
+				return custom_errors.ErrCollectionTooDeep
+			}
+		}
+
+		collection, err = q.CreateCollection(ctx, CreateCollectionParams{
+			UserID:   userID,
+			ParentID: parentID,
+			Name:     name,
+		})
+		if err != nil {
+			if ErrorCode(err) == UniqueViolation {
+				return custom_errors.ErrDuplicateData
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// UpdateCollectionTx renames a collection and moves it, with its whole subtree, under parentID or to the root.
+// Both collections are locked, so two moves racing to put collections inside each other cannot both pass the
+// cycle check.
+func (store *SQLStore) UpdateCollectionTx(ctx context.Context, userID, id int32, parentID pgtype.Int4, name string) (*Collection, error) {
+	var collection Collection
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		_, err = q.GetCollectionByLocking(ctx, GetCollectionByLockingParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		if parentID.Valid {
+			ancestry, err := lockParentCollection(ctx, q, userID, parentID.Int32)
+			if err != nil {
+				return err
+			}
+
+			if slices.Contains(ancestry, id) {
+				return custom_errors.ErrCollectionCycle
+			}
+
+			subtree, err := q.ListCollectionSubtree(ctx, ListCollectionSubtreeParams{
+				ID:     id,
+				UserID: userID,
+			})
+			if err != nil {
+				return err
+			}
+
+			// rows come ordered by depth, the last one is the deepest below the moved collection
+			height := int32(1)
+			if len(subtree) > 0 {
+				height = subtree[len(subtree)-1].Depth
+			}
+
+			if len(ancestry)+int(height) > MaxCollectionDepth {
+				return custom_errors.ErrCollectionTooDeep
+			}
+		}
+
+		collection, err = q.UpdateCollection(ctx, UpdateCollectionParams{
+			ID:       id,
+			UserID:   userID,
+			ParentID: parentID,
+			Name:     name,
+		})
+		if err != nil {
+			if ErrorCode(err) == UniqueViolation {
+				return custom_errors.ErrDuplicateData
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// DeleteCollectionTx deletes a collection without sub collections, its files move back to the root.
+func (store *SQLStore) DeleteCollectionTx(ctx context.Context, userID, id int32) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetCollectionByLocking(ctx, GetCollectionByLockingParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		children, err := q.CountChildCollections(ctx, pgtype.Int4{Valid: true, Int32: id})
+		if err != nil {
+			return err
+		}
+
+		if children != 0 {
+			return custom_errors.ErrCollectionNotEmpty
+		}
+
+		return q.DeleteCollection(ctx, DeleteCollectionParams{
+			ID:     id,
+			UserID: userID,
+		})
+	})
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RotateSigningKeyTxParams struct {
+	Purpose    string
+	PublicKey  string
+	PrivateKey []byte
+	// RetiresAt is when the outgoing signer stops verifying anything
+	RetiresAt time.Time
+	// Sign signs an api key secret hash with the incoming private key
+	Sign func(secretHash []byte) ([]byte, error)
+}
+
+type RotateSigningKeyTxResult struct {
+	Key          EncryptionKey
+	RetiredKeyID pgtype.Int4
+	Resigned     int
+}
+
+// RotateSigningKeyTx makes a new key the signer for its purpose and re-signs every api key with it,
+// the outgoing signer stays valid for verification until RetiresAt.
+func (store *SQLStore) RotateSigningKeyTx(ctx context.Context, arg RotateSigningKeyTxParams) (*RotateSigningKeyTxResult, error) {
+	var result RotateSigningKeyTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		activeID, err := q.LockActiveKeyBasedOnPurpose(ctx, arg.Purpose)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if err == nil {
+			err = q.UpdateKeyStatus(ctx, UpdateKeyStatusParams{
+				ID:        activeID,
+				IsActive:  pgtype.Bool{Bool: false, Valid: true},
+				RetiresAt: pgtype.Timestamptz{Time: arg.RetiresAt, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+
+			result.RetiredKeyID = pgtype.Int4{Int32: activeID, Valid: true}
+		}
+
+		result.Key, err = q.CreateEncryptionKeys(ctx, CreateEncryptionKeysParams{
+			PublicKey:  arg.PublicKey,
+			PrivateKey: arg.PrivateKey,
+			IsActive:   pgtype.Bool{Bool: true, Valid: true},
+			Purpose:    arg.Purpose,
+		})
+		if err != nil {
+			return err
+		}
+
+		apiKeys, err := q.ListAPIKeysForResign(ctx, result.Key.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, apiKey := range apiKeys {
+			signature, err := arg.Sign(apiKey.SecretHash)
+			if err != nil {
+				return err
+			}
+
+			err = q.UpdateAPIKeySignature(ctx, UpdateAPIKeySignatureParams{
+				ID:           apiKey.ID,
+				Signature:    signature,
+				SigningKeyID: result.Key.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Resigned = len(apiKeys)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -10,11 +10,43 @@ import (
 )
 
 type UpdateFileMetadataTxParams struct {
-	ID         int32
-	UserID     int32
-	ObjectKey  pgtype.Text
-	UpdatedAt  pgtype.Timestamptz
-	FileStatus string
+	ID              int32
+	UserID          int32
+	ObjectKey       pgtype.Text
+	UpdatedAt       pgtype.Timestamptz
+	FileStatus      string
+	ContentHash     pgtype.Text
+	DuplicatePolicy string
+}
+
+// resolveDuplicateContent looks for another uploaded file of the user with identical content. Under the reference
+// policy the returned key is that file's object, so the caller can drop the copy it just wrote. Trashed files only
+// count under the reference policy, rejecting an upload because of a file the user already deleted would be a surprise.
+func resolveDuplicateContent(ctx context.Context, q *Queries, userID, fileID int32, contentHash, objectKey pgtype.Text, policy string) (pgtype.Text, error) {
+	if !contentHash.Valid {
+		return objectKey, nil
+	}
+
+	existing, err := q.GetFileByContentHash(ctx, GetFileByContentHashParams{
+		UserID:         userID,
+		ContentHash:    contentHash,
+		ID:             fileID,
+		UploadStatus:   Success,
+		LockStatus:     Unlocked,
+		IncludeTrashed: policy != DuplicateReject,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return objectKey, nil
+		}
+		return objectKey, err
+	}
+
+	if policy == DuplicateReject {
+		return objectKey, custom_errors.ErrDuplicateContent
+	}
+
+	return existing.ObjectKey, nil
 }
 
 func (store *SQLStore) CreateEmptyFileTx(ctx context.Context, arg CreateEmptyFileParams) (*FileRegistry, error) {
@@ -30,6 +62,12 @@ func (store *SQLStore) CreateEmptyFileTx(ctx context.Context, arg CreateEmptyFil
 			FileName:     arg.FileName,
 			LockStatus:   Locked,
 			UploadStatus: Pending,
+			AudioFormat:  arg.AudioFormat,
+			Codec:        arg.Codec,
+			DurationMs:   arg.DurationMs,
+			SampleRate:   arg.SampleRate,
+			Channels:     arg.Channels,
+			SizeBytes:    arg.SizeBytes,
 		})
 
 		if err != nil {
@@ -39,7 +77,7 @@ func (store *SQLStore) CreateEmptyFileTx(ctx context.Context, arg CreateEmptyFil
 			return err
 		}
 
-		return nil
+		return reserveStorage(ctx, q, arg.UserID, arg.SizeBytes.Int64)
 
 	})
 
@@ -73,10 +111,19 @@ func (store *SQLStore) UpdateMetadataFileTx(ctx context.Context, arg UpdateFileM
 			return custom_errors.ErrResourceConflict
 		}
 
+		objectKey := arg.ObjectKey
+		if arg.FileStatus == Success {
+			objectKey, err = resolveDuplicateContent(ctx, q, arg.UserID, arg.ID, arg.ContentHash, arg.ObjectKey, arg.DuplicatePolicy)
+			if err != nil {
+				return err
+			}
+		}
+
 		file, err = q.UpdateFileMetadata(ctx, UpdateFileMetadataParams{
-			ObjectKey:    arg.ObjectKey,
+			ObjectKey:    objectKey,
 			UploadStatus: arg.FileStatus,
 			LockStatus:   Unlocked,
+			ContentHash:  arg.ContentHash,
 			ID:           arg.ID,
 			UserID:       arg.UserID,
 		})
@@ -146,7 +193,9 @@ func (store *SQLStore) UpdateFileNameTx(ctx context.Context, userID int32, oldFi
 	return &file, nil
 }
 
-func (store *SQLStore) LockFileTx(ctx context.Context, userID int32, filename string) (*FileRegistry, error) {
+// TrashFileTx moves an uploaded file to the trash. Its row and object stay in place, and keep counting against the
+// plan, until the trash purge removes them or the file is restored.
+func (store *SQLStore) TrashFileTx(ctx context.Context, userID int32, filename string) (*FileRegistry, error) {
 	var file FileRegistry
 
 	err := store.execTx(ctx, func(q *Queries) error {
@@ -173,14 +222,61 @@ func (store *SQLStore) LockFileTx(ctx context.Context, userID int32, filename st
 			return custom_errors.ErrUploadIssue
 		}
 
-		file, err = q.UnlockAndLockFile(ctx, UnlockAndLockFileParams{
-			LockStatus: Locked,
-			ID:         fileData.ID,
-			UserID:     userID,
-			Status:     Pending,
+		// the worker reports back against the file, it has to outlive the job
+		activeJobs, err := q.CountActiveTranscriptJobs(ctx, CountActiveTranscriptJobsParams{
+			FileID:           fileData.ID,
+			QueuedStatus:     JobQueued,
+			ProcessingStatus: JobProcessing,
+		})
+		if err != nil {
+			return err
+		}
+
+		if activeJobs != 0 {
+			return custom_errors.ErrJobInProgress
+		}
+
+		file, err = q.TrashFile(ctx, TrashFileParams{
+			ID:     fileData.ID,
+			UserID: userID,
 		})
 
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// RestoreFileTx takes a file back out of the trash, which fails when a live file has taken its name meanwhile.
+func (store *SQLStore) RestoreFileTx(ctx context.Context, userID, id int32) (*FileRegistry, error) {
+	var file FileRegistry
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		_, err = q.GetTrashedFileByLocking(ctx, GetTrashedFileByLockingParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		file, err = q.RestoreFile(ctx, RestoreFileParams{
+			ID:     id,
+			UserID: userID,
+		})
 		if err != nil {
+			if ErrorCode(err) == UniqueViolation {
+				return custom_errors.ErrDuplicateData
+			}
 			return err
 		}
 
@@ -194,29 +290,62 @@ func (store *SQLStore) LockFileTx(ctx context.Context, userID int32, filename st
 	return &file, nil
 }
 
-func (store *SQLStore) UnlockMultipleFilesTx(ctx context.Context, userID int32, ids []int32) error {
+// PurgeTrashedFileTx deletes a file trashed before trashedBefore. Like DeleteFileTx it reports the object key once
+// no other row references it, the key is empty otherwise. A file restored meanwhile gives ErrNoRecordFound.
+func (store *SQLStore) PurgeTrashedFileTx(ctx context.Context, userID, id int32, trashedBefore pgtype.Timestamptz) (string, error) {
+	var released string
+
 	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
+		objectKey, err := q.GetExpiredTrashedFileByLocking(ctx, GetExpiredTrashedFileByLockingParams{
+			ID:            id,
+			UserID:        userID,
+			TrashedBefore: trashedBefore,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
 
-		for _, item := range ids {
-			_, err := store.UnlockAndLockFile(ctx, UnlockAndLockFileParams{
-				Status:     Success,
-				LockStatus: Unlocked,
-				ID:         item,
-				UserID:     userID,
+		references := make([]int32, 0)
+		if objectKey.Valid {
+			references, err = q.LockObjectKeyReferences(ctx, LockObjectKeyReferencesParams{
+				UserID:    userID,
+				ObjectKey: objectKey,
 			})
 			if err != nil {
 				return err
 			}
 		}
 
-		return err
+		err = q.DeleteFiles(ctx, DeleteFilesParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if objectKey.Valid && len(references) <= 1 {
+			released = objectKey.String
+		}
+
+		return nil
 	})
 
-	return err
+	if err != nil {
+		return "", err
+	}
+
+	return released, nil
 }
 
-func (store *SQLStore) DeleteFileTx(ctx context.Context, userId, id int32, updatedAt pgtype.Timestamptz) error {
+// DeleteFileTx removes a file locked for deletion. Rows can share one object through deduplication, so every row
+// referencing the object is locked first and the returned flag tells the caller whether the object is now unreferenced
+// and safe to delete from storage.
+func (store *SQLStore) DeleteFileTx(ctx context.Context, userId, id int32, updatedAt pgtype.Timestamptz) (bool, error) {
+	var released bool
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
@@ -240,6 +369,17 @@ func (store *SQLStore) DeleteFileTx(ctx context.Context, userId, id int32, updat
 			return custom_errors.ErrUploadIssue
 		}
 
+		references := make([]int32, 0)
+		if fileData.ObjectKey.Valid {
+			references, err = q.LockObjectKeyReferences(ctx, LockObjectKeyReferencesParams{
+				UserID:    userId,
+				ObjectKey: fileData.ObjectKey,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		err = q.DeleteFiles(ctx, DeleteFilesParams{
 			ID:     id,
 			UserID: userId,
@@ -248,32 +388,14 @@ func (store *SQLStore) DeleteFileTx(ctx context.Context, userId, id int32, updat
 			return err
 		}
 
+		released = fileData.ObjectKey.Valid && len(references) <= 1
+
 		return nil
 	})
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
-}
-
-func (store *SQLStore) DeleteMultipleFilesTx(ctx context.Context, userID int32, ids []int32) error {
-	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
-
-		for _, item := range ids {
-			err = q.DeleteFiles(ctx, DeleteFilesParams{
-				UserID: userID,
-				ID:     item,
-			})
-			if err != nil {
-				return err
-			}
-		}
-
-		return err
-	})
-
-	return err
+	return released, nil
 }
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PurgeQuarantinedObjectTx records that the quarantined copy is gone together with its audit entry.
+func (store *SQLStore) PurgeQuarantinedObjectTx(ctx context.Context, object QuarantinedObject, runID pgtype.Int4) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.MarkQuarantinedObjectPurged(ctx, object.ID); err != nil {
+			return err
+		}
+
+		return q.CreateGCAuditEntry(ctx, CreateGCAuditEntryParams{
+			RunID:         runID,
+			Action:        GCActionPurged,
+			UserID:        object.UserID,
+			ObjectKey:     object.OriginalKey,
+			QuarantineKey: pgtype.Text{Valid: true, String: object.QuarantineKey},
+			SizeBytes:     pgtype.Int8{Valid: true, Int64: object.SizeBytes},
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"strconv"
+)
+
+func (store *SQLStore) EnqueueMessageTx(ctx context.Context, topic string, payload []byte) (int64, error) {
+	var messageID int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		messageID, err = q.EnqueueMessage(ctx, EnqueueMessageParams{
+			Topic:   topic,
+			Payload: payload,
+		})
+		if err != nil {
+			return err
+		}
+
+		// notify is only delivered once the transaction commits, so listeners never see an uncommitted row
+		return q.NotifyMessage(ctx, NotifyMessageParams{
+			Channel: topic,
+			Payload: strconv.FormatInt(messageID, 10),
+		})
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return messageID, nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// StartReconcilerRunTx records a new pass unless some instance already started one after since,
+// the returned run is nil when the pass is not due.
+func (store *SQLStore) StartReconcilerRunTx(ctx context.Context, dryRun bool, since time.Time) (*ReconcilerRun, error) {
+	var run *ReconcilerRun
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockReconcilerRuns(ctx); err != nil {
+			return err
+		}
+
+		recent, err := q.CountReconcilerRunsSince(ctx, since)
+		if err != nil {
+			return err
+		}
+
+		if recent > 0 {
+			return nil
+		}
+
+		created, err := q.CreateReconcilerRun(ctx, dryRun)
+		if err != nil {
+			return err
+		}
+
+		run = &created
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+// SetFileTagsTx replaces the tags of a file, names not yet used by the user become new tags. Names are expected to
+// be distinct regardless of case, an empty list clears the tags.
+func (store *SQLStore) SetFileTagsTx(ctx context.Context, userID, fileID int32, names []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(names))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		_, err = q.GetFileByID(ctx, GetFileByIDParams{
+			ID:     fileID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		if err := q.DeleteFileTags(ctx, fileID); err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return nil
+		}
+
+		if err := q.EnsureTags(ctx, EnsureTagsParams{
+			UserID: userID,
+			Names:  names,
+		}); err != nil {
+			return err
+		}
+
+		tags, err = q.ListTagsByName(ctx, ListTagsByNameParams{
+			UserID: userID,
+			Names:  names,
+		})
+		if err != nil {
+			return err
+		}
+
+		tagIDs := make([]int32, 0, len(tags))
+		for _, tag := range tags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+
+		return q.AddFileTags(ctx, AddFileTagsParams{
+			FileID: fileID,
+			TagIds: tagIDs,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
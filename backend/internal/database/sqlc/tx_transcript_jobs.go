@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type UpdateTranscriptJobStatusTxParams struct {
+	ID                  int32
+	Status              string
+	Progress            pgtype.Int4
+	ErrorMessage        pgtype.Text
+	TranscriptObjectKey pgtype.Text
+	Segments            []CreateTranscriptSegmentsParams
+}
+
+// allowedJobTransitions lists the states a job may move to from its current state,
+// processing -> processing is allowed so the worker can keep reporting progress.
+var allowedJobTransitions = map[string][]string{
+	JobQueued:     {JobProcessing, JobFailed},
+	JobProcessing: {JobProcessing, JobSucceeded, JobFailed},
+}
+
+func canTransitionJob(from, to string) bool {
+	for _, item := range allowedJobTransitions[from] {
+		if item == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (store *SQLStore) CreateTranscriptJobTx(ctx context.Context, userID, fileID int32) (*TranscriptJob, error) {
+	var job TranscriptJob
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		fileData, err := q.GetFileByID(ctx, GetFileByIDParams{
+			ID:     fileID,
+			UserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		if fileData.LockStatus {
+			return custom_errors.ErrResourceLocked
+		}
+
+		if fileData.UploadStatus != Success {
+			return custom_errors.ErrUploadIssue
+		}
+
+		activeJobs, err := q.CountActiveTranscriptJobs(ctx, CountActiveTranscriptJobsParams{
+			FileID:           fileID,
+			QueuedStatus:     JobQueued,
+			ProcessingStatus: JobProcessing,
+		})
+		if err != nil {
+			return err
+		}
+
+		if activeJobs != 0 {
+			return custom_errors.ErrJobInProgress
+		}
+
+		if err := checkTranscriptionQuota(ctx, q, userID, fileData.DurationMs); err != nil {
+			return err
+		}
+
+		job, err = q.CreateTranscriptJob(ctx, CreateTranscriptJobParams{
+			UserID: userID,
+			FileID: fileID,
+			Status: JobQueued,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateUsageEvent(ctx, CreateUsageEventParams{
+			UserID:   userID,
+			Kind:     UsageTranscription,
+			FileID:   pgtype.Int4{Int32: fileID, Valid: true},
+			JobID:    pgtype.Int4{Int32: job.ID, Valid: true},
+			Quantity: fileData.DurationMs.Int64,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (store *SQLStore) UpdateTranscriptJobStatusTx(ctx context.Context, arg UpdateTranscriptJobStatusTxParams) (*TranscriptJob, error) {
+	var job TranscriptJob
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		jobData, err := q.GetTranscriptJobByLocking(ctx, arg.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		if !canTransitionJob(jobData.Status, arg.Status) {
+			return custom_errors.ErrInvalidJobState
+		}
+
+		now := pgtype.Timestamptz{
+			Valid: true,
+			Time:  time.Now(),
+		}
+
+		params := UpdateTranscriptJobStatusParams{
+			ID:                  arg.ID,
+			Status:              arg.Status,
+			Progress:            arg.Progress,
+			ErrorMessage:        arg.ErrorMessage,
+			TranscriptObjectKey: arg.TranscriptObjectKey,
+		}
+
+		if arg.Status == JobProcessing && !jobData.StartedAt.Valid {
+			params.StartedAt = now
+		}
+
+		if arg.Status == JobSucceeded || arg.Status == JobFailed {
+			params.CompletedAt = now
+		}
+
+		if arg.Status == JobSucceeded {
+			params.Progress = pgtype.Int4{
+				Valid: true,
+				Int32: 100,
+			}
+		}
+
+		job, err = q.UpdateTranscriptJobStatus(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if arg.Status == JobSucceeded && len(arg.Segments) != 0 {
+			for i := range arg.Segments {
+				arg.Segments[i].JobID = job.ID
+			}
+
+			if _, err := q.CreateTranscriptSegments(ctx, arg.Segments); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateUploadSessionTxParams struct {
+	ID           string
+	UserID       int32
+	FileName     string
+	ObjectKey    string
+	TotalSize    int64
+	ExpiresAt    time.Time
+	UploadMethod string
+	ContentType  pgtype.Text
+}
+
+type CompleteUploadSessionTxParams struct {
+	UserID          int32
+	ID              string
+	ObjectKey       string
+	Metadata        UpdateFileAudioMetadataParams
+	ContentHash     pgtype.Text
+	DuplicatePolicy string
+}
+
+func getActiveUploadSession(ctx context.Context, q *Queries, userID int32, id string) (UploadSession, error) {
+	session, err := q.GetUploadSessionByLocking(ctx, GetUploadSessionByLockingParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return session, custom_errors.ErrNoRecordFound
+		}
+		return session, err
+	}
+
+	if session.Status != SessionActive || time.Now().After(session.ExpiresAt) {
+		return session, custom_errors.ErrUploadExpired
+	}
+
+	return session, nil
+}
+
+// CreateUploadSessionTx registers the file as PENDING and locked, exactly like a single request upload,
+// and opens the session that receives its bytes.
+func (store *SQLStore) CreateUploadSessionTx(ctx context.Context, arg CreateUploadSessionTxParams) (*UploadSession, error) {
+	var session UploadSession
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		file, err := q.CreateEmptyFile(ctx, CreateEmptyFileParams{
+			UserID:       arg.UserID,
+			FileName:     arg.FileName,
+			LockStatus:   Locked,
+			UploadStatus: Pending,
+			SizeBytes: pgtype.Int8{
+				Valid: true,
+				Int64: arg.TotalSize,
+			},
+		})
+		if err != nil {
+			if ErrorCode(err) == UniqueViolation {
+				return custom_errors.ErrDuplicateData
+			}
+			return err
+		}
+
+		if err := reserveStorage(ctx, q, arg.UserID, arg.TotalSize); err != nil {
+			return err
+		}
+
+		session, err = q.CreateUploadSession(ctx, CreateUploadSessionParams{
+			ID:           arg.ID,
+			UserID:       arg.UserID,
+			FileID:       file.ID,
+			ObjectKey:    arg.ObjectKey,
+			TotalSize:    arg.TotalSize,
+			Status:       SessionActive,
+			ExpiresAt:    arg.ExpiresAt,
+			UploadMethod: arg.UploadMethod,
+			ContentType:  arg.ContentType,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// AdvanceUploadSessionTx moves the offset past a received chunk and records the part holding it. Only one of
+// several requests racing at the same offset gets its part recorded, the others see ErrOffsetMismatch.
+func (store *SQLStore) AdvanceUploadSessionTx(ctx context.Context, userID int32, id string, expectedOffset, size int64, partKey string) (*UploadSession, error) {
+	var session UploadSession
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		sessionData, err := getActiveUploadSession(ctx, q, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if sessionData.UploadOffset != expectedOffset || expectedOffset+size > sessionData.TotalSize {
+			return custom_errors.ErrOffsetMismatch
+		}
+
+		session, err = q.UpdateUploadSessionOffset(ctx, UpdateUploadSessionOffsetParams{
+			UploadOffset: expectedOffset + size,
+			PartKey:      partKey,
+			ID:           id,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// CompleteUploadSessionTx flips the file to SUCCESS and unlocked, recording the audio metadata probed from the stored object.
+// When the content duplicates another file the returned row may point at that file's object instead of the session's.
+func (store *SQLStore) CompleteUploadSessionTx(ctx context.Context, arg CompleteUploadSessionTxParams) (*FileRegistry, error) {
+	var file FileRegistry
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		session, err := getActiveUploadSession(ctx, q, arg.UserID, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		// direct uploads never go through the offset bookkeeping, the caller verifies the object in the bucket instead
+		if session.UploadMethod == UploadResumable && session.UploadOffset != session.TotalSize {
+			return custom_errors.ErrUploadIncomplete
+		}
+
+		fileData, err := q.GetFileByID(ctx, GetFileByIDParams{
+			ID:     session.FileID,
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		if !fileData.LockStatus || fileData.UploadStatus != Pending {
+			return custom_errors.ErrResourceConflict
+		}
+
+		if _, err := q.UpdateUploadSessionStatus(ctx, UpdateUploadSessionStatusParams{
+			Status: SessionCompleted,
+			ID:     arg.ID,
+		}); err != nil {
+			return err
+		}
+
+		metadata := arg.Metadata
+		metadata.ID = session.FileID
+		metadata.UserID = arg.UserID
+
+		if err := q.UpdateFileAudioMetadata(ctx, metadata); err != nil {
+			return err
+		}
+
+		objectKey, err := resolveDuplicateContent(ctx, q, arg.UserID, session.FileID, arg.ContentHash, pgtype.Text{
+			Valid:  true,
+			String: arg.ObjectKey,
+		}, arg.DuplicatePolicy)
+		if err != nil {
+			return err
+		}
+
+		file, err = q.UpdateFileMetadata(ctx, UpdateFileMetadataParams{
+			ObjectKey:    objectKey,
+			UploadStatus: Success,
+			LockStatus:   Unlocked,
+			ContentHash:  arg.ContentHash,
+			ID:           session.FileID,
+			UserID:       arg.UserID,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// AbortUploadSessionTx removes the pending registry row, the session goes with it through the cascade.
+func (store *SQLStore) AbortUploadSessionTx(ctx context.Context, userID int32, id string) (*UploadSession, error) {
+	var session UploadSession
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		session, err = getActiveUploadSession(ctx, q, userID, id)
+		if err != nil && !errors.Is(err, custom_errors.ErrUploadExpired) {
+			return err
+		}
+
+		if session.Status == SessionCompleted {
+			return custom_errors.ErrUploadExpired
+		}
+
+		return q.DeleteFiles(ctx, DeleteFilesParams{
+			UserID: userID,
+			ID:     session.FileID,
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
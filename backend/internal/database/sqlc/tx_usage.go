@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const millisecondsPerMinute = 60 * 1000
+
+// UsagePeriodStart is the start of the billing period containing now, periods are calendar months in UTC.
+func UsagePeriodStart(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// reserveStorage counts one more file of size bytes against the user's plan,
+// the counters stay locked until the surrounding transaction ends so concurrent uploads cannot both slip under the cap.
+func reserveStorage(ctx context.Context, q *Queries, userID int32, size int64) error {
+	usage, err := q.LockUserUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	plan, err := q.GetUserPlan(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if plan.MaxFiles.Valid && usage.FileCount+1 > plan.MaxFiles.Int32 {
+		return fmt.Errorf("%w: the %s plan allows %d files", custom_errors.ErrQuotaExceeded, plan.Name, plan.MaxFiles.Int32)
+	}
+
+	if plan.MaxStorageBytes.Valid && usage.StorageBytes+size > plan.MaxStorageBytes.Int64 {
+		return fmt.Errorf("%w: the %s plan allows %d bytes of storage", custom_errors.ErrQuotaExceeded, plan.Name, plan.MaxStorageBytes.Int64)
+	}
+
+	return q.AddUserUsage(ctx, AddUserUsageParams{
+		UserID:       userID,
+		StorageBytes: size,
+		FileCount:    1,
+	})
+}
+
+// checkTranscriptionQuota refuses a transcription which would take the user past the minutes of the plan this period,
+// audio without a known duration is let through since nothing can be metered for it.
+func checkTranscriptionQuota(ctx context.Context, q *Queries, userID int32, durationMs pgtype.Int8) error {
+	if _, err := q.LockUserUsage(ctx, userID); err != nil {
+		return err
+	}
+
+	plan, err := q.GetUserPlan(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !plan.MaxTranscriptionMinutes.Valid {
+		return nil
+	}
+
+	used, err := q.SumUsageSince(ctx, SumUsageSinceParams{
+		UserID:       userID,
+		Kind:         UsageTranscription,
+		Since:        UsagePeriodStart(time.Now()),
+		FailedStatus: JobFailed,
+	})
+	if err != nil {
+		return err
+	}
+
+	if used+durationMs.Int64 > int64(plan.MaxTranscriptionMinutes.Int32)*millisecondsPerMinute {
+		return fmt.Errorf("%w: the %s plan allows %d transcription minutes per month", custom_errors.ErrQuotaExceeded, plan.Name, plan.MaxTranscriptionMinutes.Int32)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConfirmRegistrationTxParams struct {
+	VerificationID int32
+	Email          string
+	// APIKey is the first key of the account, its UserID is filled in once the user exists
+	APIKey CreateAPIKeyParams
+}
+
+// ConfirmRegistrationTx consumes the verification and creates the verified user together with its first api key,
+// consuming the row first serialises concurrent confirmations of the same code.
+func (store *SQLStore) ConfirmRegistrationTx(ctx context.Context, arg ConfirmRegistrationTxParams) (*User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		consumed, err := q.ConsumeEmailVerification(ctx, ConsumeEmailVerificationParams{
+			ID:          arg.VerificationID,
+			MaxAttempts: MaxVerificationAttempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		if consumed == 0 {
+			return custom_errors.ErrVerificationInvalid
+		}
+
+		_, err = q.GetUsersID(ctx, arg.Email)
+		if err == nil {
+			return custom_errors.ErrDuplicateData
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		user, err = q.CreateUsers(ctx, arg.Email)
+		if err != nil {
+			return err
+		}
+
+		apiKey := arg.APIKey
+		apiKey.UserID = user.ID
+
+		_, err = q.CreateAPIKey(ctx, apiKey)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+type RecoverAPIKeyTxParams struct {
+	VerificationID int32
+	Email          string
+	// APIKey replaces every key of the account, its UserID is filled in from the email
+	APIKey CreateAPIKeyParams
+}
+
+type RecoverAPIKeyTxResult struct {
+	UserID  int32
+	Revoked int64
+}
+
+// RecoverAPIKeyTx consumes a recovery verification, revokes every key of the user and issues the replacement,
+// a lost key cannot be told apart from the others so none of them survives.
+func (store *SQLStore) RecoverAPIKeyTx(ctx context.Context, arg RecoverAPIKeyTxParams) (*RecoverAPIKeyTxResult, error) {
+	var result RecoverAPIKeyTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		consumed, err := q.ConsumeEmailVerification(ctx, ConsumeEmailVerificationParams{
+			ID:          arg.VerificationID,
+			MaxAttempts: MaxVerificationAttempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		if consumed == 0 {
+			return custom_errors.ErrVerificationInvalid
+		}
+
+		result.UserID, err = q.GetUsersID(ctx, arg.Email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return custom_errors.ErrNoRecordFound
+			}
+			return err
+		}
+
+		result.Revoked, err = q.DeleteAPIKeysByUser(ctx, result.UserID)
+		if err != nil {
+			return err
+		}
+
+		apiKey := arg.APIKey
+		apiKey.UserID = result.UserID
+
+		_, err = q.CreateAPIKey(ctx, apiKey)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -10,3 +10,59 @@ const (
 	Failed  string = "FAILED"
 	Success string = "SUCCESS"
 )
+
+const (
+	JobQueued     string = "QUEUED"
+	JobProcessing string = "PROCESSING"
+	JobSucceeded  string = "SUCCEEDED"
+	JobFailed     string = "FAILED"
+)
+
+const (
+	SessionActive    string = "ACTIVE"
+	SessionCompleted string = "COMPLETED"
+	SessionAborted   string = "ABORTED"
+)
+
+const (
+	UploadResumable string = "RESUMABLE"
+	UploadDirect    string = "DIRECT"
+)
+
+const (
+	DuplicateReject    string = "reject"
+	DuplicateReference string = "reference"
+)
+
+const (
+	VerificationRegister string = "REGISTER"
+	VerificationRecovery string = "RECOVERY"
+)
+
+// MaxVerificationAttempts is how many wrong codes an email verification survives before a new one is needed.
+const MaxVerificationAttempts int32 = 5
+
+const (
+	RoleUser  string = "USER"
+	RoleAdmin string = "ADMIN"
+)
+
+const (
+	DeletionPending   string = "PENDING"
+	DeletionCompleted string = "COMPLETED"
+)
+
+const (
+	GCActionQuarantined string = "QUARANTINED"
+	GCActionPurged      string = "PURGED"
+)
+
+// MaxCollectionDepth is how many collections may be nested inside each other, a root collection being the first.
+const MaxCollectionDepth = 8
+
+// MaxTagsPerFile bounds how many tags a single file carries.
+const MaxTagsPerFile = 20
+
+const (
+	UsageTranscription string = "TRANSCRIPTION"
+)
@@ -0,0 +1,235 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: upload_session.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createUploadSession = `-- name: CreateUploadSession :one
+insert into upload_sessions (
+    id,
+    user_id,
+    file_id,
+    object_key,
+    total_size,
+    status,
+    expires_at,
+    upload_method,
+    content_type
+) values (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) returning id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys
+`
+
+type CreateUploadSessionParams struct {
+	ID           string      `json:"id"`
+	UserID       int32       `json:"user_id"`
+	FileID       int32       `json:"file_id"`
+	ObjectKey    string      `json:"object_key"`
+	TotalSize    int64       `json:"total_size"`
+	Status       string      `json:"status"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	UploadMethod string      `json:"upload_method"`
+	ContentType  pgtype.Text `json:"content_type"`
+}
+
+func (q *Queries) CreateUploadSession(ctx context.Context, arg CreateUploadSessionParams) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, createUploadSession,
+		arg.ID,
+		arg.UserID,
+		arg.FileID,
+		arg.ObjectKey,
+		arg.TotalSize,
+		arg.Status,
+		arg.ExpiresAt,
+		arg.UploadMethod,
+		arg.ContentType,
+	)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
+
+const getUploadSession = `-- name: GetUploadSession :one
+select id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys from upload_sessions
+where id = $1 and user_id = $2
+`
+
+type GetUploadSessionParams struct {
+	ID     string `json:"id"`
+	UserID int32  `json:"user_id"`
+}
+
+func (q *Queries) GetUploadSession(ctx context.Context, arg GetUploadSessionParams) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, getUploadSession, arg.ID, arg.UserID)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
+
+const getUploadSessionByLocking = `-- name: GetUploadSessionByLocking :one
+select id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys from upload_sessions
+where id = $1 and user_id = $2
+for update
+`
+
+type GetUploadSessionByLockingParams struct {
+	ID     string `json:"id"`
+	UserID int32  `json:"user_id"`
+}
+
+func (q *Queries) GetUploadSessionByLocking(ctx context.Context, arg GetUploadSessionByLockingParams) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, getUploadSessionByLocking, arg.ID, arg.UserID)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
+
+const getUploadSessionByObjectKey = `-- name: GetUploadSessionByObjectKey :one
+select id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys from upload_sessions
+where object_key = $1
+order by created_at desc
+limit 1
+`
+
+func (q *Queries) GetUploadSessionByObjectKey(ctx context.Context, objectKey string) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, getUploadSessionByObjectKey, objectKey)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
+
+const updateUploadSessionOffset = `-- name: UpdateUploadSessionOffset :one
+update upload_sessions
+set
+    upload_offset = $1,
+    part_keys = array_append(part_keys, $2::text),
+    updated_at = current_timestamp
+where id = $3
+returning id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys
+`
+
+type UpdateUploadSessionOffsetParams struct {
+	UploadOffset int64  `json:"upload_offset"`
+	PartKey      string `json:"part_key"`
+	ID           string `json:"id"`
+}
+
+func (q *Queries) UpdateUploadSessionOffset(ctx context.Context, arg UpdateUploadSessionOffsetParams) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, updateUploadSessionOffset, arg.UploadOffset, arg.PartKey, arg.ID)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
+
+const updateUploadSessionStatus = `-- name: UpdateUploadSessionStatus :one
+update upload_sessions
+set
+    status = $1,
+    updated_at = current_timestamp
+where id = $2
+returning id, user_id, file_id, object_key, total_size, upload_offset, status, expires_at, created_at, updated_at, upload_method, content_type, part_keys
+`
+
+type UpdateUploadSessionStatusParams struct {
+	Status string `json:"status"`
+	ID     string `json:"id"`
+}
+
+func (q *Queries) UpdateUploadSessionStatus(ctx context.Context, arg UpdateUploadSessionStatusParams) (UploadSession, error) {
+	row := q.db.QueryRow(ctx, updateUploadSessionStatus, arg.Status, arg.ID)
+	var i UploadSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FileID,
+		&i.ObjectKey,
+		&i.TotalSize,
+		&i.UploadOffset,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UploadMethod,
+		&i.ContentType,
+		&i.PartKeys,
+	)
+	return i, err
+}
@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: usage.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addUserUsage = `-- name: AddUserUsage :exec
+insert into user_usage (
+    user_id,
+    storage_bytes,
+    file_count
+) values (
+    $1, $2, $3
+)
+on conflict (user_id) do update
+set
+    storage_bytes = user_usage.storage_bytes + excluded.storage_bytes,
+    file_count = user_usage.file_count + excluded.file_count,
+    updated_at = current_timestamp
+`
+
+type AddUserUsageParams struct {
+	UserID       int32 `json:"user_id"`
+	StorageBytes int64 `json:"storage_bytes"`
+	FileCount    int32 `json:"file_count"`
+}
+
+func (q *Queries) AddUserUsage(ctx context.Context, arg AddUserUsageParams) error {
+	_, err := q.db.Exec(ctx, addUserUsage, arg.UserID, arg.StorageBytes, arg.FileCount)
+	return err
+}
+
+const createUsageEvent = `-- name: CreateUsageEvent :one
+insert into usage_events (
+    user_id,
+    kind,
+    file_id,
+    job_id,
+    quantity
+) values (
+    $1, $2, $3, $4, $5
+) returning id, user_id, kind, file_id, job_id, quantity, created_at
+`
+
+type CreateUsageEventParams struct {
+	UserID   int32       `json:"user_id"`
+	Kind     string      `json:"kind"`
+	FileID   pgtype.Int4 `json:"file_id"`
+	JobID    pgtype.Int4 `json:"job_id"`
+	Quantity int64       `json:"quantity"`
+}
+
+func (q *Queries) CreateUsageEvent(ctx context.Context, arg CreateUsageEventParams) (UsageEvent, error) {
+	row := q.db.QueryRow(ctx, createUsageEvent,
+		arg.UserID,
+		arg.Kind,
+		arg.FileID,
+		arg.JobID,
+		arg.Quantity,
+	)
+	var i UsageEvent
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Kind,
+		&i.FileID,
+		&i.JobID,
+		&i.Quantity,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserPlan = `-- name: GetUserPlan :one
+select p.name, p.max_storage_bytes, p.max_files, p.max_transcription_minutes, p.created_at from users u
+join plans p on p.name = u.plan
+where u.id = $1
+`
+
+func (q *Queries) GetUserPlan(ctx context.Context, userID int32) (Plan, error) {
+	row := q.db.QueryRow(ctx, getUserPlan, userID)
+	var i Plan
+	err := row.Scan(
+		&i.Name,
+		&i.MaxStorageBytes,
+		&i.MaxFiles,
+		&i.MaxTranscriptionMinutes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserUsage = `-- name: GetUserUsage :one
+select user_id, storage_bytes, file_count, updated_at from user_usage
+where user_id = $1
+`
+
+func (q *Queries) GetUserUsage(ctx context.Context, userID int32) (UserUsage, error) {
+	row := q.db.QueryRow(ctx, getUserUsage, userID)
+	var i UserUsage
+	err := row.Scan(
+		&i.UserID,
+		&i.StorageBytes,
+		&i.FileCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const lockUserUsage = `-- name: LockUserUsage :one
+insert into user_usage (
+    user_id
+) values (
+    $1
+)
+on conflict (user_id) do update
+set user_id = excluded.user_id
+returning user_id, storage_bytes, file_count, updated_at
+`
+
+// creates the counters on first use, the no-op update takes the row lock which serialises quota checks of a user
+func (q *Queries) LockUserUsage(ctx context.Context, userID int32) (UserUsage, error) {
+	row := q.db.QueryRow(ctx, lockUserUsage, userID)
+	var i UserUsage
+	err := row.Scan(
+		&i.UserID,
+		&i.StorageBytes,
+		&i.FileCount,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const sumUsageSince = `-- name: SumUsageSince :one
+select coalesce(sum(e.quantity), 0)::bigint
+from usage_events e
+left join transcript_jobs j on j.id = e.job_id
+where
+    e.user_id = $1
+    and e.kind = $2
+    and e.created_at >= $3
+    and (j.status is null or j.status <> $4)
+`
+
+type SumUsageSinceParams struct {
+	UserID       int32     `json:"user_id"`
+	Kind         string    `json:"kind"`
+	Since        time.Time `json:"since"`
+	FailedStatus string    `json:"failed_status"`
+}
+
+// usage of failed transcript jobs is not billed
+func (q *Queries) SumUsageSince(ctx context.Context, arg SumUsageSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumUsageSince,
+		arg.UserID,
+		arg.Kind,
+		arg.Since,
+		arg.FailedStatus,
+	)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
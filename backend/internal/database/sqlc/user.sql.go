@@ -7,14 +7,17 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUsers = `-- name: CreateUsers :one
 insert into users (
-    email
+    email,
+    email_verified_at
 ) values (
-    $1
-) returning id, email, created_at, updated_at
+    $1, current_timestamp
+) returning id, email, created_at, updated_at, email_verified_at, plan, role, suspended_at, suspension_reason, deleted_at
 `
 
 func (q *Queries) CreateUsers(ctx context.Context, email string) (User, error) {
@@ -25,6 +28,12 @@ func (q *Queries) CreateUsers(ctx context.Context, email string) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.Plan,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -41,6 +50,58 @@ func (q *Queries) GetUser(ctx context.Context, id int32) (string, error) {
 	return email, err
 }
 
+const getUserDetails = `-- name: GetUserDetails :one
+select id, email, role, plan, email_verified_at, suspended_at, suspension_reason, created_at, updated_at from users
+where id = $1
+`
+
+type GetUserDetailsRow struct {
+	ID               int32              `json:"id"`
+	Email            string             `json:"email"`
+	Role             string             `json:"role"`
+	Plan             string             `json:"plan"`
+	EmailVerifiedAt  pgtype.Timestamptz `json:"email_verified_at"`
+	SuspendedAt      pgtype.Timestamptz `json:"suspended_at"`
+	SuspensionReason pgtype.Text        `json:"suspension_reason"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+func (q *Queries) GetUserDetails(ctx context.Context, id int32) (GetUserDetailsRow, error) {
+	row := q.db.QueryRow(ctx, getUserDetails, id)
+	var i GetUserDetailsRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Role,
+		&i.Plan,
+		&i.EmailVerifiedAt,
+		&i.SuspendedAt,
+		&i.SuspensionReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserStatus = `-- name: GetUserStatus :one
+select role, suspended_at, deleted_at from users
+where id = $1
+`
+
+type GetUserStatusRow struct {
+	Role        string             `json:"role"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+}
+
+func (q *Queries) GetUserStatus(ctx context.Context, id int32) (GetUserStatusRow, error) {
+	row := q.db.QueryRow(ctx, getUserStatus, id)
+	var i GetUserStatusRow
+	err := row.Scan(&i.Role, &i.SuspendedAt, &i.DeletedAt)
+	return i, err
+}
+
 const getUsersID = `-- name: GetUsersID :one
 select id from users
 where email = $1
@@ -52,3 +113,170 @@ func (q *Queries) GetUsersID(ctx context.Context, email string) (int32, error) {
 	err := row.Scan(&id)
 	return id, err
 }
+
+const listUsers = `-- name: ListUsers :many
+select id, email, role, plan, email_verified_at, suspended_at, created_at from users
+where
+    ($1::varchar is null or email ilike '%' || $1::varchar || '%')
+    and
+    ($2::varchar is null or role = $2::varchar)
+    and
+    ($3::boolean is null or (suspended_at is not null) = $3::boolean)
+order by id
+limit $5
+offset $4
+`
+
+type ListUsersParams struct {
+	Email      pgtype.Text `json:"email"`
+	Role       pgtype.Text `json:"role"`
+	Suspended  pgtype.Bool `json:"suspended"`
+	PageOffset int32       `json:"page_offset"`
+	PageLimit  int32       `json:"page_limit"`
+}
+
+type ListUsersRow struct {
+	ID              int32              `json:"id"`
+	Email           string             `json:"email"`
+	Role            string             `json:"role"`
+	Plan            string             `json:"plan"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	SuspendedAt     pgtype.Timestamptz `json:"suspended_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Email,
+		arg.Role,
+		arg.Suspended,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUsersRow{}
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.Plan,
+			&i.EmailVerifiedAt,
+			&i.SuspendedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const promoteUsersByEmail = `-- name: PromoteUsersByEmail :many
+update users
+set
+    role = $1,
+    updated_at = current_timestamp
+where
+    lower(email) = any($2::text[])
+    and role <> $1
+    and deleted_at is null
+returning id, email
+`
+
+type PromoteUsersByEmailParams struct {
+	Role   string   `json:"role"`
+	Emails []string `json:"emails"`
+}
+
+type PromoteUsersByEmailRow struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) PromoteUsersByEmail(ctx context.Context, arg PromoteUsersByEmailParams) ([]PromoteUsersByEmailRow, error) {
+	rows, err := q.db.Query(ctx, promoteUsersByEmail, arg.Role, arg.Emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PromoteUsersByEmailRow{}
+	for rows.Next() {
+		var i PromoteUsersByEmailRow
+		if err := rows.Scan(&i.ID, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const suspendUser = `-- name: SuspendUser :execrows
+update users
+set
+    suspended_at = coalesce(suspended_at, current_timestamp),
+    suspension_reason = $1,
+    updated_at = current_timestamp
+where id = $2
+`
+
+type SuspendUserParams struct {
+	Reason pgtype.Text `json:"reason"`
+	ID     int32       `json:"id"`
+}
+
+func (q *Queries) SuspendUser(ctx context.Context, arg SuspendUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, suspendUser, arg.Reason, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unsuspendUser = `-- name: UnsuspendUser :execrows
+update users
+set
+    suspended_at = null,
+    suspension_reason = null,
+    updated_at = current_timestamp
+where id = $1
+`
+
+func (q *Queries) UnsuspendUser(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.Exec(ctx, unsuspendUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserRole = `-- name: UpdateUserRole :execrows
+update users
+set
+    role = $1,
+    updated_at = current_timestamp
+where id = $2
+`
+
+type UpdateUserRoleParams struct {
+	Role string `json:"role"`
+	ID   int32  `json:"id"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserRole, arg.Role, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
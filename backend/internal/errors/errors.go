@@ -3,9 +3,20 @@ package custom_errors
 import "errors"
 
 var (
-	ErrDuplicateData    error = errors.New("data already exists")
-	ErrNoRecordFound    error = errors.New("no record found")
-	ErrResourceConflict error = errors.New("resource tampered")
-	ErrUploadIssue      error = errors.New("upload issue, either it is pending or failed")
-	ErrResourceLocked   error = errors.New("resource locked please try later")
+	ErrDuplicateData       error = errors.New("data already exists")
+	ErrNoRecordFound       error = errors.New("no record found")
+	ErrResourceConflict    error = errors.New("resource tampered")
+	ErrUploadIssue         error = errors.New("upload issue, either it is pending or failed")
+	ErrResourceLocked      error = errors.New("resource locked please try later")
+	ErrJobInProgress       error = errors.New("transcript job already queued or processing")
+	ErrInvalidJobState     error = errors.New("invalid transcript job status transition")
+	ErrOffsetMismatch      error = errors.New("upload offset does not match")
+	ErrUploadExpired       error = errors.New("upload session expired or no longer active")
+	ErrUploadIncomplete    error = errors.New("upload session has not received every byte")
+	ErrDuplicateContent    error = errors.New("file with identical content already exists")
+	ErrVerificationInvalid error = errors.New("verification code invalid, expired or already used")
+	ErrQuotaExceeded       error = errors.New("plan quota exceeded")
+	ErrCollectionCycle     error = errors.New("collection cannot be moved into itself or one of its sub collections")
+	ErrCollectionTooDeep   error = errors.New("collections nested too deep")
+	ErrCollectionNotEmpty  error = errors.New("collection still has sub collections")
 )
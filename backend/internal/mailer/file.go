@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer drops every message as an .eml file into a directory, handy for tests which need to read the codes.
+type FileMailer struct {
+	root string
+	from string
+}
+
+func NewFileMailer(root, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+
+	return &FileMailer{
+		root: root,
+		from: from,
+	}, nil
+}
+
+func (fm *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	return os.WriteFile(filepath.Join(fm.root, name), format(fm.from, msg, now), 0o600)
+}
+
+func (fm *FileMailer) Close() error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+)
+
+// LogMailer writes every message to the log instead of delivering it, meant for local runs.
+type LogMailer struct {
+	baseLogger *logger.Logger
+}
+
+func NewLogMailer(baseLogger *logger.Logger) *LogMailer {
+	return &LogMailer{
+		baseLogger: baseLogger,
+	}
+}
+
+func (lm *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+
+	lm.baseLogger.Info().Str("to", msg.To).Str("subject", msg.Subject).Str("body", msg.Body).Msg("mail not delivered, log mailer in use")
+
+	return nil
+}
+
+func (lm *LogMailer) Close() error {
+	return nil
+}
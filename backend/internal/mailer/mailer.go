@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+)
+
+const (
+	LogBackend  = "log"
+	FileBackend = "file"
+	SMTPBackend = "smtp"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers the account emails, verification codes and recovery tokens, to the user.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+	Close() error
+}
+
+func NewMailer(config *utils.Config, baseLogger *logger.Logger) (Mailer, error) {
+	switch config.MailerBackend {
+	case LogBackend:
+		return NewLogMailer(baseLogger), nil
+	case FileBackend:
+		return NewFileMailer(config.MailerFilePath, config.MailFrom)
+	case SMTPBackend:
+		return NewSMTPMailer(SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.MailFrom,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer backend: %s", config.MailerBackend)
+	}
+}
+
+// format renders the message as an RFC 5322 plain text email.
+func format(from string, msg Message, now time.Time) []byte {
+	var builder strings.Builder
+
+	builder.WriteString("From: " + from + "\r\n")
+	builder.WriteString("To: " + msg.To + "\r\n")
+	builder.WriteString("Subject: " + msg.Subject + "\r\n")
+	builder.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(builder.String())
+}
+
+// validHeader keeps user supplied addresses from smuggling extra headers into the message.
+func validHeader(values ...string) error {
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid mail header value: %q", value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer delivers through an SMTP relay, net/smtp upgrades to STARTTLS whenever the server offers it.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := validHeader(msg.To, msg.Subject); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if sm.config.Username != "" {
+		auth = smtp.PlainAuth("", sm.config.Username, sm.config.Password, sm.config.Host)
+	}
+
+	address := net.JoinHostPort(sm.config.Host, strconv.Itoa(sm.config.Port))
+
+	// smtp.SendMail takes no context, run it aside so a hung relay cannot outlive the request
+	result := make(chan error, 1)
+	go func() {
+		result <- smtp.SendMail(address, auth, sm.config.From, []string{msg.To}, format(sm.config.From, msg, time.Now()))
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (sm *SMTPMailer) Close() error {
+	return nil
+}
@@ -1,61 +1,264 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rsa"
+	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
 	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
-func Authenticate(config utils.Config, store database.Store) gin.HandlerFunc {
+const bearerPrefix = "Bearer "
+
+// Authenticate accepts either the api key itself or a jwt access token exchanged for one as "Bearer <token>",
+// access tokens are verified statelessly against the cached signing keys.
+func Authenticate(config utils.Config, store database.Store, keyCache *token.KeyCache) gin.HandlerFunc {
+	accessTokenOptions := token.AccessTokenOptionsFromConfig(config)
+
 	return func(ctx *gin.Context) {
 
-		key, err := token.GetKeyBasedOnPurpose(ctx, store, config.KeysPurpose)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error getting active jwt keys": err.Error()})
+		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide token for authorization"})
 			ctx.Abort()
 			return
 		}
 
-		publicKey, err := token.GetPublicKey([]byte(key.PublicKey))
+		if accessToken, ok := strings.CutPrefix(authHeader, bearerPrefix); ok {
+			authenticateAccessToken(ctx, keyCache, accessTokenOptions, accessToken)
+			return
+		}
+
+		parsedKey, err := token.ParseAPIKey(authHeader)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error getting public key: ": err.Error()})
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide valid API Key"})
 			ctx.Abort()
 			return
 		}
 
-		authHeader := ctx.GetHeader("Authorization")
-		if authHeader == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide token for authorization"})
+		cached, ok := keyCache.APIKey(parsedKey)
+		if !ok {
+			apiDetails, err := lookupAPIKey(ctx, store, parsedKey)
+			if err != nil {
+				log.Printf("Error: %s", err.Error())
+				ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide valid API Key"})
+				ctx.Abort()
+				return
+			}
+
+			publicKey, ok := verificationKey(ctx, keyCache, apiDetails.SigningKeyID)
+			if !ok {
+				return
+			}
+
+			err = token.VerifyAPIKey(parsedKey, apiDetails.SecretHash, apiDetails.Signature, publicKey)
+			if err != nil {
+				ctx.JSON(http.StatusUnauthorized, gin.H{
+					"error": "invalid api key",
+				})
+				ctx.Abort()
+				return
+			}
+
+			cached = keyCache.StoreAPIKey(parsedKey, apiDetails)
+		} else if _, ok := verificationKey(ctx, keyCache, cached.Details.SigningKeyID); !ok {
+			// the signature was verified when the key got cached, only the signer's retirement can change since
+			return
+		}
+
+		apiDetails := cached.Details
+
+		now := time.Now()
+
+		if apiDetails.ExpiresAt.Valid && !apiDetails.ExpiresAt.Time.After(now) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "api key expired"})
 			ctx.Abort()
 			return
 		}
 
-		apiDetails, err := store.GetAPIKey(ctx, []byte(authHeader))
-		if err != nil {
-			log.Printf("Error: %s", err.Error())
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide valid API Key"})
+		// a failed touch must not fail the request
+		if cached.ShouldTouch(now) {
+			if err := store.TouchAPIKey(ctx, apiDetails.ID); err != nil {
+				log.Printf("Error updating api key last use: %s", err.Error())
+			}
+		}
+
+		payload := token.Payload{
+			APIKey: authHeader,
+			UserID: int(apiDetails.UserID),
+			KeyID:  int(apiDetails.ID),
+			Scopes: apiDetails.Scopes,
+		}
+
+		if apiDetails.ExpiresAt.Valid {
+			payload.KeyExpiresAt = apiDetails.ExpiresAt.Time
+		}
+
+		if !loadUserStatus(ctx, keyCache, &payload) {
+			return
+		}
+
+		ctx.Set(constants.PayloadKey, payload)
+
+		ctx.Next()
+	}
+}
+
+func authenticateAccessToken(ctx *gin.Context, keyCache *token.KeyCache, opts token.AccessTokenOptions, accessToken string) {
+	claims, err := token.VerifyAccessToken(accessToken, func(keyID int32) (*rsa.PublicKey, error) {
+		return keyCache.VerificationKey(ctx, keyID)
+	}, opts, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, token.ErrExpiredToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "access token expired"})
+		case errors.Is(err, token.ErrInvalidToken), errors.Is(err, pgx.ErrNoRows):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error getting verification key: ": err.Error()})
+		}
+		ctx.Abort()
+		return
+	}
+
+	payload, err := claims.Payload()
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+		ctx.Abort()
+		return
+	}
+
+	// the token dies with its api key, revoked or recovered keys are gone from the table
+	keyStatus, err := keyCache.APIKeyStatus(ctx, int32(payload.KeyID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "api key of the access token was revoked"})
 			ctx.Abort()
 			return
 		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error getting api key status: ": err.Error()})
+		ctx.Abort()
+		return
+	}
 
-		err = token.VerifyAPIKey(authHeader, apiDetails.Signature, publicKey)
-		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid api key",
-			})
+	if keyStatus.UserID != int32(payload.UserID) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+		ctx.Abort()
+		return
+	}
+
+	if keyStatus.ExpiresAt.Valid && !keyStatus.ExpiresAt.Time.After(time.Now()) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "api key of the access token expired"})
+		ctx.Abort()
+		return
+	}
+
+	if !loadUserStatus(ctx, keyCache, &payload) {
+		return
+	}
+
+	ctx.Set(constants.PayloadKey, payload)
+
+	ctx.Next()
+}
+
+// loadUserStatus fills in the role of the user and aborts the request when the account is suspended or deleted,
+// access tokens are refused too since they outlive the suspension otherwise.
+func loadUserStatus(ctx *gin.Context, keyCache *token.KeyCache, payload *token.Payload) bool {
+	status, err := keyCache.UserStatus(ctx, int32(payload.UserID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user no longer exists"})
+			ctx.Abort()
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error getting user status: ": err.Error()})
+		ctx.Abort()
+		return false
+	}
+
+	if status.DeletedAt.Valid {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "account deleted"})
+		ctx.Abort()
+		return false
+	}
+
+	if status.SuspendedAt.Valid {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "account suspended"})
+		ctx.Abort()
+		return false
+	}
+
+	payload.Role = status.Role
+
+	return true
+}
+
+// verificationKey aborts the request itself when the signer cannot be used.
+func verificationKey(ctx *gin.Context, keyCache *token.KeyCache, signingKeyID int32) (*rsa.PublicKey, bool) {
+	publicKey, err := keyCache.VerificationKey(ctx, signingKeyID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "api key was signed by a retired key, please issue a new one"})
+			ctx.Abort()
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error getting verification key: ": err.Error()})
+		ctx.Abort()
+		return nil, false
+	}
+
+	return publicKey, true
+}
+
+// lookupAPIKey finds the row by key id, legacy keys issued before the tg_ format have none and go by their hash.
+func lookupAPIKey(ctx context.Context, store database.Store, parsedKey *token.ParsedAPIKey) (database.GetAPIKeyRow, error) {
+	if !parsedKey.IsLegacy() {
+		return store.GetAPIKey(ctx, parsedKey.KeyID)
+	}
+
+	legacy, err := store.GetLegacyAPIKey(ctx, parsedKey.SecretHash)
+	if err != nil {
+		return database.GetAPIKeyRow{}, err
+	}
+
+	return database.GetAPIKeyRow(legacy), nil
+}
+
+// RequireScope rejects requests whose api key was not granted the scope, it must run after Authenticate.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+		if !payload.HasScope(scope) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "api key lacks required scope: " + scope})
 			ctx.Abort()
 			return
 		}
 
-		ctx.Set(constants.PayloadKey, token.Payload{
-			APIKey: authHeader,
-			UserID: int(apiDetails.UserID),
-		})
+		ctx.Next()
+	}
+}
+
+// RequireRole rejects requests of users without the role, it must run after Authenticate.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+
+		if payload.Role != role {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "requires role: " + role})
+			ctx.Abort()
+			return
+		}
 
 		ctx.Next()
 	}
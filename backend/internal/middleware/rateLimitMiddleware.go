@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/constants"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/ratelimit"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimitKey picks the bucket a request draws from.
+type RateLimitKey func(ctx *gin.Context) string
+
+// ByClientIP is meant for routes reachable without an api key.
+func ByClientIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// ByAPIKey gives every api key its own bucket, access tokens draw from the bucket of the key they were exchanged for.
+// It must run after Authenticate.
+func ByAPIKey(ctx *gin.Context) string {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	return "key:" + strconv.Itoa(payload.KeyID)
+}
+
+// ByUser shares one bucket between every key of a user, it must run after Authenticate.
+func ByUser(ctx *gin.Context) string {
+	payload := ctx.MustGet(constants.PayloadKey).(token.Payload)
+	return "user:" + strconv.Itoa(payload.UserID)
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
+
+// RateLimit throttles a route group with a token bucket, name keeps the buckets of different groups apart.
+// A failing limiter lets the request through rather than taking the api down with it.
+func RateLimit(limiter ratelimit.Limiter, name string, limit ratelimit.Limit, key RateLimitKey) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !TakeRateLimit(ctx, limiter, name, limit, key, 1) {
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// TakeRateLimit takes cost tokens from the same bucket RateLimit uses, for handlers whose cost is only known once
+// they have looked at the request. It sets the rate limit headers and reports false after responding with 429.
+func TakeRateLimit(ctx *gin.Context, limiter ratelimit.Limiter, name string, limit ratelimit.Limit, key RateLimitKey, cost int) bool {
+	if !limit.Enabled() {
+		return true
+	}
+
+	result, err := limiter.Allow(ctx, name+":"+key(ctx), limit, cost)
+	if err != nil {
+		log.Printf("Error checking rate limit: %s", err.Error())
+		return true
+	}
+
+	ctx.Header(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+	ctx.Header(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+	ctx.Header(RateLimitResetHeader, ceilSeconds(result.ResetAfter))
+
+	if !result.Allowed {
+		ctx.Header("Retry-After", ceilSeconds(result.RetryAfter))
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded, please retry later"})
+		return false
+	}
+
+	return true
+}
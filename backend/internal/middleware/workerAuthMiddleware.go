@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	WorkerTimestampHeader = "X-Worker-Timestamp"
+	WorkerSignatureHeader = "X-Worker-Signature"
+
+	maxWorkerBodySize = 10 * 1024 * 1024
+)
+
+// VerifyWorkerSignature guards the internal routes which only the transcription service may call,
+// the request body is restored so handlers can bind it as usual.
+func VerifyWorkerSignature(secret string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timestamp := ctx.GetHeader(WorkerTimestampHeader)
+		signature := ctx.GetHeader(WorkerSignatureHeader)
+
+		if timestamp == "" || signature == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "please provide worker signature"})
+			ctx.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWorkerBodySize))
+		if err != nil {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+			ctx.Abort()
+			return
+		}
+
+		err = token.VerifyWorkerPayload([]byte(secret), timestamp, signature, ctx.Request.Method, ctx.Request.URL.Path, body, time.Now())
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid worker signature"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,145 @@
+package objectgc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Options struct {
+	// Prefix is where quarantined objects are moved to, outside of every user prefix
+	Prefix string
+	// Retention is how long a quarantined object is kept before it is deleted for good
+	Retention time.Duration
+}
+
+func OptionsFromConfig(config utils.Config) Options {
+	return Options{
+		Prefix:    config.QuarantinePrefix,
+		Retention: time.Duration(config.QuarantineRetention) * time.Hour,
+	}
+}
+
+// Collector removes objects nothing references in two steps: an orphan is first moved under the quarantine prefix,
+// where it can still be recovered by hand, and only deleted once the retention has passed. Every move and deletion
+// is written to the audit table.
+type Collector struct {
+	store       database.Store
+	objectStore objectstore.ObjectStore
+	logger      *logger.Logger
+	opts        Options
+}
+
+func NewCollector(store database.Store, objectStore objectstore.ObjectStore, baseLogger *logger.Logger, opts Options) *Collector {
+	return &Collector{
+		store:       store,
+		objectStore: objectStore,
+		logger:      baseLogger,
+		opts:        opts,
+	}
+}
+
+// Quarantine moves an orphaned object of the user under the quarantine prefix. It reports false when the object
+// gained a reference since it was found and was left in place.
+func (c *Collector) Quarantine(ctx context.Context, runID pgtype.Int4, userID int32, object objectstore.ObjectAttrs) (bool, error) {
+	// the listing is a snapshot, an upload may have claimed the key since
+	referenced, err := c.store.IsObjectKeyReferenced(ctx, database.IsObjectKeyReferencedParams{
+		UserID:       userID,
+		ObjectKey:    object.Key,
+		ActiveStatus: database.SessionActive,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if referenced {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+
+	// the row goes in first, a crash after the copy then still leaves the quarantined object to be purged
+	quarantined, err := c.store.CreateQuarantinedObject(ctx, database.CreateQuarantinedObjectParams{
+		UserID:        userID,
+		OriginalKey:   object.Key,
+		QuarantineKey: fmt.Sprintf("%s%s/%s", c.opts.Prefix, now.Format("20060102T150405Z"), object.Key),
+		SizeBytes:     object.Size,
+		PurgeAfter:    now.Add(c.opts.Retention),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error recording quarantined object: %w", err)
+	}
+
+	if err := c.objectStore.Copy(ctx, object.Key, quarantined.QuarantineKey); err != nil {
+		if deleteErr := c.store.DeleteQuarantinedObject(context.WithoutCancel(ctx), quarantined.ID); deleteErr != nil {
+			c.logger.Error().Err(deleteErr).Int32("quarantine_id", quarantined.ID).Msg("error while removing record of failed quarantine")
+		}
+		return false, fmt.Errorf("error copying object to quarantine: %w", err)
+	}
+
+	// a failed delete leaves the original for the next pass, the extra copy is purged like any other
+	if err := c.objectStore.Delete(ctx, object.Key); err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+		return false, fmt.Errorf("error deleting quarantined original: %w", err)
+	}
+
+	if err := c.store.CreateGCAuditEntry(ctx, database.CreateGCAuditEntryParams{
+		RunID:         runID,
+		Action:        database.GCActionQuarantined,
+		UserID:        userID,
+		ObjectKey:     object.Key,
+		QuarantineKey: pgtype.Text{Valid: true, String: quarantined.QuarantineKey},
+		SizeBytes:     pgtype.Int8{Valid: true, Int64: object.Size},
+	}); err != nil {
+		return true, fmt.Errorf("error writing audit entry: %w", err)
+	}
+
+	c.logger.Info().
+		Int32("user_id", userID).
+		Str("object_key", object.Key).
+		Str("quarantine_key", quarantined.QuarantineKey).
+		Time("purge_after", quarantined.PurgeAfter).
+		Msg("quarantined orphaned object")
+
+	return true, nil
+}
+
+// PurgeExpired deletes quarantined objects past their retention, at most limit of them, and reports how many it purged.
+func (c *Collector) PurgeExpired(ctx context.Context, runID pgtype.Int4, limit int32) (int, error) {
+	objects, err := c.store.ListExpiredQuarantinedObjects(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, object := range objects {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+
+		err := c.objectStore.Delete(ctx, object.QuarantineKey)
+		if err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+			return purged, fmt.Errorf("error deleting quarantined object %s: %w", object.QuarantineKey, err)
+		}
+
+		if err := c.store.PurgeQuarantinedObjectTx(ctx, object, runID); err != nil {
+			return purged, fmt.Errorf("error recording purge of %s: %w", object.QuarantineKey, err)
+		}
+
+		purged++
+
+		c.logger.Info().
+			Int32("user_id", object.UserID).
+			Str("object_key", object.OriginalKey).
+			Str("quarantine_key", object.QuarantineKey).
+			Msg("purged quarantined object")
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,180 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+type GCSStore struct {
+	client     *storage.Client
+	bucketName string
+}
+
+type gcsWriter struct {
+	*storage.Writer
+	cancel context.CancelFunc
+}
+
+func NewGCSStore(ctx context.Context, bucketName string) (*GCSStore, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCSStore{
+		client:     client,
+		bucketName: bucketName,
+	}, nil
+}
+
+func (w *gcsWriter) Close() error {
+	defer w.cancel()
+	return w.Writer.Close()
+}
+
+func (w *gcsWriter) Abort() error {
+	// cancelling the context passed to NewWriter is how the gcs sdk discards a pending upload
+	w.cancel()
+	return nil
+}
+
+func (gs *GCSStore) object(key string) *storage.ObjectHandle {
+	return gs.client.Bucket(gs.bucketName).Object(key)
+}
+
+func (gs *GCSStore) NewWriter(ctx context.Context, key string) ObjectWriter {
+	writerCtx, cancel := context.WithCancel(ctx)
+
+	return &gcsWriter{
+		Writer: gs.object(key).NewWriter(writerCtx),
+		cancel: cancel,
+	}
+}
+
+func (gs *GCSStore) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := gs.object(key).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (gs *GCSStore) NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	reader, err := gs.object(key).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (gs *GCSStore) Attrs(ctx context.Context, key string) (*ObjectAttrs, error) {
+	attrs, err := gs.object(key).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+
+	return &ObjectAttrs{
+		Key:         attrs.Name,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		Updated:     attrs.Updated,
+	}, nil
+}
+
+func (gs *GCSStore) List(ctx context.Context, prefix string) ([]ObjectAttrs, error) {
+	objects := make([]ObjectAttrs, 0)
+
+	it := gs.client.Bucket(gs.bucketName).Objects(ctx, &storage.Query{
+		Prefix: prefix,
+	})
+
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, ObjectAttrs{
+			Key:         attrs.Name,
+			Size:        attrs.Size,
+			ContentType: attrs.ContentType,
+			Updated:     attrs.Updated,
+		})
+	}
+
+	return objects, nil
+}
+
+func (gs *GCSStore) Delete(ctx context.Context, key string) error {
+	err := gs.object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrObjectNotExist
+	}
+
+	return err
+}
+
+func (gs *GCSStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	_, err := gs.object(dstKey).CopierFrom(gs.object(srcKey)).Run(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrObjectNotExist
+	}
+
+	return err
+}
+
+// SignedURL relies on the client credentials being able to sign, a service account key or the iam signBlob permission.
+func (gs *GCSStore) SignedURL(_ context.Context, key string, opts SignedURLOptions) (*SignedRequest, error) {
+	headers := make(map[string]string)
+	if opts.ContentType != "" {
+		headers["Content-Type"] = opts.ContentType
+	}
+	if opts.ContentLength > 0 {
+		headers["x-goog-content-length-range"] = fmt.Sprintf("%d,%d", opts.ContentLength, opts.ContentLength)
+	}
+
+	signedHeaders := make([]string, 0, len(headers))
+	for name, value := range headers {
+		if name != "Content-Type" {
+			signedHeaders = append(signedHeaders, name+":"+value)
+		}
+	}
+
+	signedURL, err := gs.client.Bucket(gs.bucketName).SignedURL(key, &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      opts.Method,
+		Expires:     time.Now().Add(opts.Expires),
+		ContentType: opts.ContentType,
+		Headers:     signedHeaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedRequest{URL: signedURL, Headers: headers}, nil
+}
+
+func (gs *GCSStore) Close() error {
+	return gs.client.Close()
+}
@@ -0,0 +1,370 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalSignedURLPath is where the api server mounts the handler that serves signed urls of the local store.
+const LocalSignedURLPath = "/server/storage/"
+
+// LocalStore keeps objects as plain files under a root directory, the object key is the relative path.
+// Nothing but the bytes is persisted, so ObjectAttrs.ContentType is always empty for local objects.
+// It stands in for a bucket's signed urls by signing urls that point back at the api server.
+type LocalStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+type localWriter struct {
+	file *os.File
+	path string
+	done bool
+}
+
+func NewLocalStore(root, baseURL, secret string) (*LocalStore, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(absRoot, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating local storage directory: %w", err)
+	}
+
+	return &LocalStore{
+		root:    absRoot,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+func (ls *LocalStore) objectPath(key string) (string, error) {
+	path := filepath.Join(ls.root, filepath.FromSlash(key))
+
+	if path != ls.root && !strings.HasPrefix(path, ls.root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("object key escapes storage root: %s", key)
+	}
+
+	return path, nil
+}
+
+func (w *localWriter) Write(p []byte) (int, error) {
+	return w.file.Write(p)
+}
+
+func (w *localWriter) Close() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+
+	return nil
+}
+
+func (w *localWriter) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	w.file.Close()
+	return os.Remove(w.file.Name())
+}
+
+// errWriter defers a failure in NewWriter to the first Write or Close, mirroring how the cloud sdks report errors.
+type errWriter struct {
+	err error
+}
+
+func (w *errWriter) Write([]byte) (int, error) { return 0, w.err }
+func (w *errWriter) Close() error              { return w.err }
+func (w *errWriter) Abort() error              { return nil }
+
+func (ls *LocalStore) NewWriter(_ context.Context, key string) ObjectWriter {
+	path, err := ls.objectPath(key)
+	if err != nil {
+		return &errWriter{err: err}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return &errWriter{err: err}
+	}
+
+	// write into a temp file next to the destination so a partial upload is never visible under the key
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return &errWriter{err: err}
+	}
+
+	return &localWriter{
+		file: file,
+		path: path,
+	}
+}
+
+func (ls *LocalStore) NewReader(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := ls.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+type localRangeReader struct {
+	io.Reader
+	file *os.File
+}
+
+func (r *localRangeReader) Close() error {
+	return r.file.Close()
+}
+
+func (ls *LocalStore) NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	reader, err := ls.NewReader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	file := reader.(*os.File)
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if length < 0 {
+		return file, nil
+	}
+
+	return &localRangeReader{
+		Reader: io.LimitReader(file, length),
+		file:   file,
+	}, nil
+}
+
+func (ls *LocalStore) Attrs(_ context.Context, key string) (*ObjectAttrs, error) {
+	path, err := ls.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrObjectNotExist
+		}
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return nil, ErrObjectNotExist
+	}
+
+	return &ObjectAttrs{
+		Key:     key,
+		Size:    info.Size(),
+		Updated: info.ModTime(),
+	}, nil
+}
+
+func (ls *LocalStore) List(_ context.Context, prefix string) ([]ObjectAttrs, error) {
+	objects := make([]ObjectAttrs, 0)
+
+	err := filepath.WalkDir(ls.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(ls.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relPath)
+
+		if entry.IsDir() {
+			// skip directories which can never contain keys with the requested prefix
+			if path != ls.root && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasPrefix(entry.Name(), ".upload-") || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectAttrs{
+			Key:     key,
+			Size:    info.Size(),
+			Updated: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (ls *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := ls.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrObjectNotExist
+	}
+
+	return err
+}
+
+// Copy goes through a regular writer, so the destination only appears once it is complete.
+func (ls *LocalStore) Copy(ctx context.Context, srcKey, dstKey string) error {
+	reader, err := ls.NewReader(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	writer := ls.NewWriter(ctx, dstKey)
+	if _, err := io.Copy(writer, reader); err != nil {
+		writer.Abort()
+		return err
+	}
+
+	return writer.Close()
+}
+
+func (ls *LocalStore) sign(method, key, expires, contentType, contentLength string) string {
+	mac := hmac.New(sha256.New, ls.secret)
+	mac.Write([]byte(strings.Join([]string{method, key, expires, contentType, contentLength}, "\n")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (ls *LocalStore) SignedURL(_ context.Context, key string, opts SignedURLOptions) (*SignedRequest, error) {
+	if len(ls.secret) == 0 {
+		return nil, errors.New("local storage signing secret is not configured")
+	}
+
+	if _, err := ls.objectPath(key); err != nil {
+		return nil, err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(opts.Expires).Unix(), 10)
+
+	var contentLength string
+	if opts.ContentLength > 0 {
+		contentLength = strconv.FormatInt(opts.ContentLength, 10)
+	}
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	if opts.ContentType != "" {
+		query.Set("content_type", opts.ContentType)
+	}
+	if contentLength != "" {
+		query.Set("content_length", contentLength)
+	}
+	query.Set("signature", ls.sign(opts.Method, key, expires, opts.ContentType, contentLength))
+
+	path := (&url.URL{Path: LocalSignedURLPath + key}).EscapedPath()
+
+	headers := make(map[string]string)
+	if opts.ContentType != "" {
+		headers["Content-Type"] = opts.ContentType
+	}
+
+	return &SignedRequest{URL: ls.baseURL + path + "?" + query.Encode(), Headers: headers}, nil
+}
+
+// LocalSignedRequest is what a verified signed url of the local store allows, a zero ContentLength means
+// the url was not bound to a size.
+type LocalSignedRequest struct {
+	ContentType   string
+	ContentLength int64
+}
+
+// VerifySignedURL checks a request against a url produced by SignedURL, it returns the content type and
+// length the url was signed for so a PUT can be held to them.
+func (ls *LocalStore) VerifySignedURL(method, key string, query url.Values) (*LocalSignedRequest, error) {
+	if len(ls.secret) == 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	expires := query.Get("expires")
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, ErrInvalidSignature
+	}
+
+	contentType := query.Get("content_type")
+	contentLength := query.Get("content_length")
+
+	var length int64
+	if contentLength != "" {
+		length, err = strconv.ParseInt(contentLength, 10, 64)
+		if err != nil || length <= 0 {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(ls.sign(method, key, expires, contentType, contentLength))
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidSignature
+	}
+
+	return &LocalSignedRequest{ContentType: contentType, ContentLength: length}, nil
+}
+
+func (ls *LocalStore) Close() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+)
+
+const (
+	GCSBackend   = "gcs"
+	LocalBackend = "local"
+	S3Backend    = "s3"
+)
+
+var (
+	ErrObjectNotExist   = errors.New("object does not exist")
+	ErrInvalidSignature = errors.New("invalid or expired signed url")
+)
+
+type ObjectAttrs struct {
+	Key         string
+	Size        int64
+	ContentType string
+	Updated     time.Time
+}
+
+// SignedURLOptions describes the single request a signed url authorizes, ContentType and ContentLength are
+// bound into the signature of PUT urls so the client has to send exactly that header and that many bytes.
+type SignedURLOptions struct {
+	Method        string
+	Expires       time.Duration
+	ContentType   string
+	ContentLength int64
+}
+
+// SignedRequest is a signed url along with the headers the client has to send for the signature to hold.
+type SignedRequest struct {
+	URL     string
+	Headers map[string]string
+}
+
+// ObjectWriter commits the object on Close, Abort discards whatever was written so far.
+type ObjectWriter interface {
+	io.WriteCloser
+	Abort() error
+}
+
+type ObjectStore interface {
+	NewWriter(ctx context.Context, key string) ObjectWriter
+	NewReader(ctx context.Context, key string) (io.ReadCloser, error)
+	// NewRangeReader reads length bytes starting at offset, a negative length reads to the end of the object.
+	NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Attrs(ctx context.Context, key string) (*ObjectAttrs, error)
+	List(ctx context.Context, prefix string) ([]ObjectAttrs, error)
+	Delete(ctx context.Context, key string) error
+	// Copy duplicates an object within the bucket, server side where the backend supports it.
+	Copy(ctx context.Context, srcKey, dstKey string) error
+	SignedURL(ctx context.Context, key string, opts SignedURLOptions) (*SignedRequest, error)
+	Close() error
+}
+
+func NewObjectStore(ctx context.Context, config *utils.Config) (ObjectStore, error) {
+	switch config.StorageBackend {
+	case GCSBackend:
+		return NewGCSStore(ctx, config.BucketName)
+	case LocalBackend:
+		baseURL := config.LocalStorageBaseURL
+		if baseURL == "" {
+			baseURL = "http://localhost:" + config.Port
+		}
+		return NewLocalStore(config.LocalStoragePath, baseURL, config.StorageSigningSecret)
+	case S3Backend:
+		return NewS3Store(ctx, S3Config{
+			Endpoint:   config.S3Endpoint,
+			Region:     config.S3Region,
+			AccessKey:  config.S3AccessKey,
+			SecretKey:  config.S3SecretKey,
+			UseSSL:     config.S3UseSSL,
+			BucketName: config.BucketName,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", config.StorageBackend)
+	}
+}
@@ -0,0 +1,120 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testStores returns every backend that can run here, the local store always and the remote ones when their
+// environment points at a bucket, an emulator such as fake-gcs-server or minio will do.
+func testStores(t *testing.T) map[string]ObjectStore {
+	t.Helper()
+
+	ctx := context.Background()
+
+	local, err := NewLocalStore(t.TempDir(), "http://localhost", "secret")
+	if err != nil {
+		t.Fatalf("creating local store: %v", err)
+	}
+
+	stores := map[string]ObjectStore{LocalBackend: local}
+
+	if bucket := os.Getenv("OBJECTSTORE_TEST_GCS_BUCKET"); bucket != "" {
+		store, err := NewGCSStore(ctx, bucket)
+		if err != nil {
+			t.Fatalf("creating gcs store: %v", err)
+		}
+		stores[GCSBackend] = store
+	}
+
+	if bucket := os.Getenv("OBJECTSTORE_TEST_S3_BUCKET"); bucket != "" {
+		store, err := NewS3Store(ctx, S3Config{
+			Endpoint:   os.Getenv("OBJECTSTORE_TEST_S3_ENDPOINT"),
+			Region:     os.Getenv("OBJECTSTORE_TEST_S3_REGION"),
+			AccessKey:  os.Getenv("OBJECTSTORE_TEST_S3_ACCESS_KEY"),
+			SecretKey:  os.Getenv("OBJECTSTORE_TEST_S3_SECRET_KEY"),
+			UseSSL:     os.Getenv("OBJECTSTORE_TEST_S3_USE_SSL") == "true",
+			BucketName: bucket,
+		})
+		if err != nil {
+			t.Fatalf("creating s3 store: %v", err)
+		}
+		stores[S3Backend] = store
+	}
+
+	for _, store := range stores {
+		t.Cleanup(func() { store.Close() })
+	}
+
+	return stores
+}
+
+func TestNewRangeReader(t *testing.T) {
+	const content = "0123456789"
+
+	cases := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{name: "whole object", offset: 0, length: -1, want: content},
+		{name: "from offset to end", offset: 3, length: -1, want: "3456789"},
+		{name: "from last byte to end", offset: 9, length: -1, want: "9"},
+		{name: "prefix", offset: 0, length: 4, want: "0123"},
+		{name: "single byte", offset: 0, length: 1, want: "0"},
+		{name: "middle", offset: 3, length: 4, want: "3456"},
+		{name: "past the end", offset: 8, length: 10, want: "89"},
+		{name: "empty from start", offset: 0, length: 0, want: ""},
+		{name: "empty at offset", offset: 5, length: 0, want: ""},
+	}
+
+	for backend, store := range testStores(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			key := "objectstore-test/" + uuid.New().String()
+
+			writer := store.NewWriter(ctx, key)
+			if _, err := io.WriteString(writer, content); err != nil {
+				t.Fatalf("writing object: %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("closing writer: %v", err)
+			}
+			t.Cleanup(func() { store.Delete(context.Background(), key) })
+
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					reader, err := store.NewRangeReader(ctx, key, tc.offset, tc.length)
+					if err != nil {
+						t.Fatalf("NewRangeReader(%d, %d): %v", tc.offset, tc.length, err)
+					}
+					defer reader.Close()
+
+					got, err := io.ReadAll(reader)
+					if err != nil {
+						t.Fatalf("reading range: %v", err)
+					}
+
+					if string(got) != tc.want {
+						t.Errorf("NewRangeReader(%d, %d) read %q, want %q", tc.offset, tc.length, got, tc.want)
+					}
+				})
+			}
+
+			t.Run("missing object", func(t *testing.T) {
+				for _, length := range []int64{-1, 0, 4} {
+					_, err := store.NewRangeReader(ctx, key+"-missing", 0, length)
+					if !errors.Is(err, ErrObjectNotExist) {
+						t.Errorf("NewRangeReader with length %d on a missing object returned %v, want ErrObjectNotExist", length, err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const noSuchKey = "NoSuchKey"
+
+type S3Config struct {
+	Endpoint   string
+	Region     string
+	AccessKey  string
+	SecretKey  string
+	UseSSL     bool
+	BucketName string
+}
+
+// S3Store talks to any S3 compatible service, AWS S3 as well as on-prem MinIO.
+type S3Store struct {
+	client     *minio.Client
+	bucketName string
+}
+
+type s3Writer struct {
+	pipeWriter *io.PipeWriter
+	result     chan error
+	done       bool
+}
+
+func NewS3Store(ctx context.Context, config S3Config) (*S3Store, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, config.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking s3 bucket: %w", err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("s3 bucket does not exist: %s", config.BucketName)
+	}
+
+	return &S3Store{
+		client:     client,
+		bucketName: config.BucketName,
+	}, nil
+}
+
+func mapS3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == noSuchKey {
+		return ErrObjectNotExist
+	}
+	return err
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.pipeWriter.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	w.pipeWriter.Close()
+	return <-w.result
+}
+
+func (w *s3Writer) Abort() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+
+	w.pipeWriter.CloseWithError(fmt.Errorf("upload aborted"))
+	<-w.result
+	return nil
+}
+
+func (ss *S3Store) NewWriter(ctx context.Context, key string) ObjectWriter {
+	pipeReader, pipeWriter := io.Pipe()
+
+	writer := &s3Writer{
+		pipeWriter: pipeWriter,
+		result:     make(chan error, 1),
+	}
+
+	go func() {
+		_, err := ss.client.PutObject(ctx, ss.bucketName, key, pipeReader, -1, minio.PutObjectOptions{})
+		pipeReader.CloseWithError(err)
+		writer.result <- err
+	}()
+
+	return writer
+}
+
+func (ss *S3Store) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := ss.client.GetObject(ctx, ss.bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+
+	// GetObject is lazy, stat it so a missing key is reported here instead of on the first read
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, mapS3Error(err)
+	}
+
+	return object, nil
+}
+
+func (ss *S3Store) NewRangeReader(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	// an http range cannot be empty, so a zero length only checks that the object exists
+	if length == 0 {
+		if _, err := ss.client.StatObject(ctx, ss.bucketName, key, minio.StatObjectOptions{}); err != nil {
+			return nil, mapS3Error(err)
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	opts := minio.GetObjectOptions{}
+
+	// SetRange reads an end of zero as open ended only past the first byte, from the start no range is the whole object
+	var err error
+	switch {
+	case length > 0:
+		err = opts.SetRange(offset, offset+length-1)
+	case offset > 0:
+		err = opts.SetRange(offset, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := ss.client.GetObject(ctx, ss.bucketName, key, opts)
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, mapS3Error(err)
+	}
+
+	return object, nil
+}
+
+func (ss *S3Store) Attrs(ctx context.Context, key string) (*ObjectAttrs, error) {
+	info, err := ss.client.StatObject(ctx, ss.bucketName, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, mapS3Error(err)
+	}
+
+	return &ObjectAttrs{
+		Key:         info.Key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		Updated:     info.LastModified,
+	}, nil
+}
+
+func (ss *S3Store) List(ctx context.Context, prefix string) ([]ObjectAttrs, error) {
+	objects := make([]ObjectAttrs, 0)
+
+	for info := range ss.client.ListObjects(ctx, ss.bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+
+		objects = append(objects, ObjectAttrs{
+			Key:         info.Key,
+			Size:        info.Size,
+			ContentType: info.ContentType,
+			Updated:     info.LastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+func (ss *S3Store) Delete(ctx context.Context, key string) error {
+	// s3 treats deleting a missing key as success, stat first to keep the gcs semantics
+	if _, err := ss.Attrs(ctx, key); err != nil {
+		return err
+	}
+
+	return ss.client.RemoveObject(ctx, ss.bucketName, key, minio.RemoveObjectOptions{})
+}
+
+func (ss *S3Store) Copy(ctx context.Context, srcKey, dstKey string) error {
+	_, err := ss.client.CopyObject(ctx, minio.CopyDestOptions{
+		Bucket: ss.bucketName,
+		Object: dstKey,
+	}, minio.CopySrcOptions{
+		Bucket: ss.bucketName,
+		Object: srcKey,
+	})
+
+	return mapS3Error(err)
+}
+
+func (ss *S3Store) SignedURL(ctx context.Context, key string, opts SignedURLOptions) (*SignedRequest, error) {
+	headers := make(http.Header)
+	if opts.ContentType != "" {
+		headers.Set("Content-Type", opts.ContentType)
+	}
+	if opts.ContentLength > 0 {
+		headers.Set("Content-Length", strconv.FormatInt(opts.ContentLength, 10))
+	}
+
+	signedURL, err := ss.client.PresignHeader(ctx, opts.Method, ss.bucketName, key, opts.Expires, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := &SignedRequest{URL: signedURL.String(), Headers: make(map[string]string, len(headers))}
+	for name := range headers {
+		signed.Headers[name] = headers.Get(name)
+	}
+
+	return signed, nil
+}
+
+func (ss *S3Store) Close() error {
+	return nil
+}
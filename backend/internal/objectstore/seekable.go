@@ -0,0 +1,93 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// window fetched per range request, large enough that header parsing rarely needs a second round trip
+const seekableWindow = 256 * 1024
+
+type seekableReader struct {
+	ctx         context.Context
+	store       ObjectStore
+	key         string
+	size        int64
+	offset      int64
+	window      []byte
+	windowStart int64
+}
+
+// NewSeekableReader exposes an object as an io.ReadSeeker backed by range reads, so callers that only
+// touch a few regions of a large object, like a header parser, never download all of it.
+func NewSeekableReader(ctx context.Context, store ObjectStore, key string, size int64) io.ReadSeeker {
+	return &seekableReader{
+		ctx:   ctx,
+		store: store,
+		key:   key,
+		size:  size,
+	}
+}
+
+func (r *seekableReader) fill() error {
+	length := min(seekableWindow, r.size-r.offset)
+
+	reader, err := r.store.NewRangeReader(r.ctx, r.key, r.offset, length)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	window := make([]byte, length)
+	n, err := io.ReadFull(reader, window)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	r.window = window[:n]
+	r.windowStart = r.offset
+
+	return nil
+}
+
+func (r *seekableReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.offset < r.windowStart || r.offset >= r.windowStart+int64(len(r.window)) {
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+
+		if len(r.window) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+
+	n := copy(p, r.window[r.offset-r.windowStart:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+func (r *seekableReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	r.offset = offset
+
+	return offset, nil
+}
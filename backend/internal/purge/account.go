@@ -0,0 +1,190 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	accountPurgeInterval = time.Minute
+	// accountPurgeLease bounds how long a claimed deletion stays hidden from other instances,
+	// one purge attempt is cut off when it runs out so the lease is never outlived
+	accountPurgeLease = 15 * time.Minute
+)
+
+// AccountPurger works off pending account deletions in the background: the user's objects go first, then every row
+// in one transaction. A crash in between leaves the deletion pending and the next attempt starts over,
+// deleting an object twice is harmless.
+type AccountPurger struct {
+	store       database.Store
+	objectStore objectstore.ObjectStore
+	logger      *logger.Logger
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAccountPurger(store database.Store, objectStore objectstore.ObjectStore, baseLogger *logger.Logger) *AccountPurger {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ap := &AccountPurger{
+		store:       store,
+		objectStore: objectStore,
+		logger:      baseLogger,
+		wake:        make(chan struct{}, 1),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	ap.wg.Add(1)
+	go ap.run()
+
+	return ap
+}
+
+// Wake starts a pass right away instead of at the next tick, used when a deletion was just requested.
+func (ap *AccountPurger) Wake() {
+	select {
+	case ap.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ap *AccountPurger) run() {
+	defer ap.wg.Done()
+
+	ticker := time.NewTicker(accountPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		ap.purgePending()
+
+		select {
+		case <-ap.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ap.wake:
+		}
+	}
+}
+
+// purgePending purges claimable deletions until none is left.
+func (ap *AccountPurger) purgePending() {
+	for ap.ctx.Err() == nil {
+		deletion, err := ap.store.ClaimAccountDeletion(ap.ctx, database.ClaimAccountDeletionParams{
+			Status: database.DeletionPending,
+			LeaseExpiresAt: pgtype.Timestamptz{
+				Valid: true,
+				Time:  time.Now().Add(accountPurgeLease),
+			},
+		})
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) && ap.ctx.Err() == nil {
+				ap.logger.Error().Err(err).Msg("error while claiming account deletion")
+			}
+			return
+		}
+
+		if err := ap.purge(deletion); err != nil {
+			ap.logger.Error().Err(err).Int32("deletion_id", deletion.ID).Int32("user_id", deletion.UserID).Msg("error while purging account")
+
+			// the context of the failed attempt may be gone already
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := ap.store.RecordAccountDeletionFailure(ctx, database.RecordAccountDeletionFailureParams{
+				ID:        deletion.ID,
+				LastError: pgtype.Text{Valid: true, String: err.Error()},
+			}); err != nil {
+				ap.logger.Error().Err(err).Int32("deletion_id", deletion.ID).Msg("error while recording account purge failure")
+			}
+			cancel()
+
+			// a failing deletion is retried on the next tick rather than spun on
+			return
+		}
+	}
+}
+
+func (ap *AccountPurger) purge(deletion database.AccountDeletion) error {
+	ctx, cancel := context.WithTimeout(ap.ctx, accountPurgeLease)
+	defer cancel()
+
+	// locked rows keep the user's remaining requests away from the objects being removed
+	if err := ap.store.LockUserFiles(ctx, database.LockUserFilesParams{
+		UserID:     deletion.UserID,
+		LockStatus: database.Locked,
+	}); err != nil {
+		return fmt.Errorf("error locking files: %w", err)
+	}
+
+	prefixes := []string{
+		fmt.Sprintf("%d/", deletion.UserID),
+		fmt.Sprintf("transcripts/%d/", deletion.UserID),
+	}
+
+	for _, prefix := range prefixes {
+		deleted, err := ap.deletePrefix(ctx, prefix)
+		if deleted > 0 {
+			if recordErr := ap.store.AddDeletedObjects(ctx, database.AddDeletedObjectsParams{
+				ID:      deletion.ID,
+				Objects: int32(deleted),
+			}); recordErr != nil && err == nil {
+				err = recordErr
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("error deleting objects under %s: %w", prefix, err)
+		}
+	}
+
+	result, err := ap.store.PurgeAccountTx(ctx, deletion.ID, deletion.UserID)
+	if err != nil {
+		return fmt.Errorf("error deleting rows: %w", err)
+	}
+
+	ap.logger.Info().
+		Int32("deletion_id", deletion.ID).
+		Int32("user_id", deletion.UserID).
+		Int64("files_deleted", result.FilesDeleted).
+		Int64("jobs_deleted", result.JobsDeleted).
+		Msg("account purged")
+
+	return nil
+}
+
+// deletePrefix removes every object under prefix and reports how many it removed, even when it fails part way.
+func (ap *AccountPurger) deletePrefix(ctx context.Context, prefix string) (int, error) {
+	objects, err := ap.objectStore.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, object := range objects {
+		err := ap.objectStore.Delete(ctx, object.Key)
+		if err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+			return deleted, err
+		}
+		if err == nil {
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func (ap *AccountPurger) Close() error {
+	ap.cancel()
+	ap.wg.Wait()
+	return nil
+}
@@ -0,0 +1,133 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	custom_errors "github.com/DEVunderdog/transcript-generator-backend/internal/errors"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	trashPurgeInterval  = 10 * time.Minute
+	trashPurgeBatchSize = 100
+)
+
+// TrashPurger deletes files that have been in the trash for longer than the retention. The row goes first, as with
+// a direct delete, so a crash before the object is removed only leaves an orphan for the garbage collector.
+type TrashPurger struct {
+	store       database.Store
+	objectStore objectstore.ObjectStore
+	logger      *logger.Logger
+	retention   time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTrashPurger(store database.Store, objectStore objectstore.ObjectStore, baseLogger *logger.Logger, retention time.Duration) *TrashPurger {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tp := &TrashPurger{
+		store:       store,
+		objectStore: objectStore,
+		logger:      baseLogger,
+		retention:   retention,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	tp.wg.Add(1)
+	go tp.run()
+
+	return tp
+}
+
+func (tp *TrashPurger) run() {
+	defer tp.wg.Done()
+
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		tp.purgeExpired()
+
+		select {
+		case <-tp.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpired purges expired files batch by batch until none is left or a batch fails entirely.
+func (tp *TrashPurger) purgeExpired() {
+	trashedBefore := pgtype.Timestamptz{
+		Valid: true,
+		Time:  time.Now().Add(-tp.retention),
+	}
+
+	for tp.ctx.Err() == nil {
+		files, err := tp.store.ListExpiredTrashedFiles(tp.ctx, database.ListExpiredTrashedFilesParams{
+			TrashedBefore: trashedBefore,
+			PageLimit:     trashPurgeBatchSize,
+		})
+		if err != nil {
+			if tp.ctx.Err() == nil {
+				tp.logger.Error().Err(err).Msg("error while listing expired trashed files")
+			}
+			return
+		}
+
+		purged := 0
+		for _, file := range files {
+			if tp.ctx.Err() != nil {
+				return
+			}
+
+			if err := tp.purge(file, trashedBefore); err != nil {
+				tp.logger.Error().Err(err).Int32("file_id", file.ID).Int32("user_id", file.UserID).Msg("error while purging trashed file")
+				continue
+			}
+			purged++
+		}
+
+		// a failing file would otherwise be listed again and again, it is retried on the next tick
+		if len(files) < trashPurgeBatchSize || purged == 0 {
+			return
+		}
+	}
+}
+
+func (tp *TrashPurger) purge(file database.ListExpiredTrashedFilesRow, trashedBefore pgtype.Timestamptz) error {
+	released, err := tp.store.PurgeTrashedFileTx(tp.ctx, file.UserID, file.ID, trashedBefore)
+	if err != nil {
+		// restored since it was listed
+		if errors.Is(err, custom_errors.ErrNoRecordFound) {
+			return nil
+		}
+		return err
+	}
+
+	if released != "" {
+		if err := tp.objectStore.Delete(tp.ctx, released); err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+			tp.logger.Error().Err(err).Msgf("error deleting unreferenced object %s", released)
+		}
+	}
+
+	tp.logger.Info().Int32("file_id", file.ID).Int32("user_id", file.UserID).Msg("purged trashed file")
+
+	return nil
+}
+
+func (tp *TrashPurger) Close() error {
+	tp.cancel()
+	tp.wg.Wait()
+	return nil
+}
@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
+)
+
+var (
+	ErrPublisherClosed = errors.New("publisher is closed")
+	ErrQueueFull       = errors.New("in-memory queue is full")
+)
+
+// MemoryPublisher is an in-process queue for integration tests, which read the published requests from Messages.
+// The transcription service runs in its own process and cannot consume it, so nothing is transcribed with this
+// backend. Publishing fails with ErrQueueFull instead of blocking the request once the buffer is full.
+type MemoryPublisher struct {
+	mu       sync.RWMutex
+	closed   bool
+	messages chan *pb.TopicMessage
+}
+
+func NewMemoryPublisher(bufferSize int) *MemoryPublisher {
+	return &MemoryPublisher{
+		messages: make(chan *pb.TopicMessage, bufferSize),
+	}
+}
+
+func (mp *MemoryPublisher) Publish(_ context.Context, msg *pb.TopicMessage) error {
+	mp.mu.RLock()
+	defer mp.mu.RUnlock()
+
+	if mp.closed {
+		return ErrPublisherClosed
+	}
+
+	select {
+	case mp.messages <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (mp *MemoryPublisher) Messages() <-chan *pb.TopicMessage {
+	return mp.messages
+}
+
+func (mp *MemoryPublisher) Close() error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if !mp.closed {
+		mp.closed = true
+		close(mp.messages)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNATSPublisher(url, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSPublisher{
+		conn:    conn,
+		subject: subject,
+	}, nil
+}
+
+func (np *NATSPublisher) Publish(ctx context.Context, msg *pb.TopicMessage) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error while encoding binary case proto.Marshal: %w", err)
+	}
+
+	if err := np.conn.Publish(np.subject, data); err != nil {
+		return fmt.Errorf("error while publishing the message to subject: %w", err)
+	}
+
+	// publish is buffered by the client, flush so the request is not lost if we crash right after responding
+	if err := np.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("error while flushing the message to nats: %w", err)
+	}
+
+	return nil
+}
+
+func (np *NATSPublisher) Close() error {
+	return np.conn.Drain()
+}
@@ -0,0 +1,42 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// PostgresPublisher stores messages in the message_queue table and raises a NOTIFY on the topic channel,
+// the transcription service LISTENs on the channel, claims rows with for update skip locked and deletes them once
+// acknowledged.
+type PostgresPublisher struct {
+	store database.Store
+	topic string
+}
+
+func NewPostgresPublisher(store database.Store, topic string) *PostgresPublisher {
+	return &PostgresPublisher{
+		store: store,
+		topic: topic,
+	}
+}
+
+func (pp *PostgresPublisher) Publish(ctx context.Context, msg *pb.TopicMessage) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error while encoding binary case proto.Marshal: %w", err)
+	}
+
+	if _, err := pp.store.EnqueueMessageTx(ctx, pp.topic, data); err != nil {
+		return fmt.Errorf("error while enqueueing message: %w", err)
+	}
+
+	return nil
+}
+
+func (pp *PostgresPublisher) Close() error {
+	return nil
+}
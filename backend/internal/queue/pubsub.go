@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type PubSubPublisher struct {
+	client   *pubsub.Client
+	topic    *pubsub.Topic
+	encoding pubsub.SchemaEncoding
+}
+
+// NewPubSubPublisher resolves the topic schema encoding once, publishing then only marshals and sends.
+func NewPubSubPublisher(ctx context.Context, topicID, projectID string) (*PubSubPublisher, error) {
+	client, err := pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := client.Topic(topicID)
+
+	topicConfig, err := topic.Config(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error while configuring topic.Config: %w", err)
+	}
+
+	// topics without a schema carry raw bytes, which is what the transcription service parses
+	encoding := pubsub.EncodingBinary
+	if topicConfig.SchemaSettings != nil {
+		encoding = topicConfig.SchemaSettings.Encoding
+	}
+
+	switch encoding {
+	case pubsub.EncodingJSON, pubsub.EncodingBinary:
+	default:
+		client.Close()
+		return nil, fmt.Errorf("unknown encoding: %v", encoding)
+	}
+
+	return &PubSubPublisher{
+		client:   client,
+		topic:    topic,
+		encoding: encoding,
+	}, nil
+}
+
+func (ps *PubSubPublisher) Publish(ctx context.Context, msg *pb.TopicMessage) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch ps.encoding {
+	case pubsub.EncodingJSON:
+		data, err = protojson.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("error while encoding json case protojson.Marshal: %w", err)
+		}
+	default:
+		data, err = proto.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("error while encoding binary case proto.Marshal: %w", err)
+		}
+	}
+
+	result := ps.topic.Publish(ctx, &pubsub.Message{
+		Data: data,
+	})
+
+	_, err = result.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("error while publishing the message to topic: %w", err)
+	}
+
+	return nil
+}
+
+func (ps *PubSubPublisher) Close() error {
+	ps.topic.Stop()
+	return ps.client.Close()
+}
@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/proto/pb"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+)
+
+// the transcription service consumes the pubsub, postgres and nats backends, the memory backend is only for tests
+const (
+	PubSubBackend   = "pubsub"
+	MemoryBackend   = "memory"
+	PostgresBackend = "postgres"
+	NATSBackend     = "nats"
+)
+
+// Publisher hands transcript requests over to whatever queue the transcription service consumes.
+type Publisher interface {
+	Publish(ctx context.Context, msg *pb.TopicMessage) error
+	Close() error
+}
+
+func NewPublisher(ctx context.Context, config *utils.Config, store database.Store) (Publisher, error) {
+	switch config.QueueBackend {
+	case PubSubBackend:
+		return NewPubSubPublisher(ctx, config.TopicID, config.ProjectID)
+	case MemoryBackend:
+		return NewMemoryPublisher(config.QueueBufferSize), nil
+	case PostgresBackend:
+		return NewPostgresPublisher(store, config.QueueTopic), nil
+	case NATSBackend:
+		return NewNATSPublisher(config.NATSURL, config.QueueTopic)
+	default:
+		return nil, fmt.Errorf("unknown queue backend: %s", config.QueueBackend)
+	}
+}
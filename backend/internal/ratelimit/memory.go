@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// idleBucketTTL is how long an untouched bucket is kept, any bucket idle that long has refilled anyway.
+const idleBucketTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (ml *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (Result, error) {
+	now := time.Now()
+
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	ml.sweep(now)
+
+	b, ok := ml.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.burst(), updatedAt: now}
+		ml.buckets[key] = b
+	}
+
+	b.tokens = math.Min(limit.burst(), b.tokens+now.Sub(b.updatedAt).Seconds()*limit.rate())
+	b.updatedAt = now
+
+	allowed := b.tokens >= float64(cost)
+	if allowed {
+		b.tokens -= float64(cost)
+	}
+
+	return newResult(limit, b.tokens, cost, allowed), nil
+}
+
+// sweep drops idle buckets at most once per idleBucketTTL so the map does not grow with every client ever seen.
+func (ml *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(ml.lastSweep) < idleBucketTTL {
+		return
+	}
+
+	for key, b := range ml.buckets {
+		if now.Sub(b.updatedAt) > idleBucketTTL {
+			delete(ml.buckets, key)
+		}
+	}
+
+	ml.lastSweep = now
+}
+
+func (ml *MemoryLimiter) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+)
+
+const staleBucketSweepInterval = 10 * time.Minute
+
+// PostgresLimiter keeps the buckets in an unlogged table so every instance shares them,
+// each request costs one upsert which refills and takes its tokens atomically.
+type PostgresLimiter struct {
+	store database.Store
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewPostgresLimiter(store database.Store) *PostgresLimiter {
+	pl := &PostgresLimiter{
+		store: store,
+		done:  make(chan struct{}),
+	}
+
+	pl.wg.Add(1)
+	go pl.sweep()
+
+	return pl
+}
+
+func (pl *PostgresLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (Result, error) {
+	row, err := pl.store.TakeRateLimitToken(ctx, database.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: limit.burst(),
+		Rate:  limit.rate(),
+		Cost:  float64(cost),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return newResult(limit, row.Tokens, cost, row.Allowed), nil
+}
+
+// sweep removes buckets idle long enough to have refilled, they behave exactly like missing ones.
+func (pl *PostgresLimiter) sweep() {
+	defer pl.wg.Done()
+
+	ticker := time.NewTicker(staleBucketSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pl.done:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			pl.store.DeleteStaleRateLimitBuckets(ctx, time.Now().Add(-idleBucketTTL))
+			cancel()
+		}
+	}
+}
+
+func (pl *PostgresLimiter) Close() error {
+	close(pl.done)
+	pl.wg.Wait()
+	return nil
+}
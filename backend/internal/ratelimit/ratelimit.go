@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+)
+
+const (
+	MemoryBackend   = "memory"
+	PostgresBackend = "postgres"
+)
+
+// Limit is a token bucket holding Requests tokens which refills completely over Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// PerMinute is how route group limits are configured.
+func PerMinute(requests int) Limit {
+	return Limit{
+		Requests: requests,
+		Period:   time.Minute,
+	}
+}
+
+func (limit Limit) Enabled() bool {
+	return limit.Requests > 0 && limit.Period > 0
+}
+
+func (limit Limit) rate() float64 {
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+func (limit Limit) burst() float64 {
+	return float64(limit.Requests)
+}
+
+// Result describes the bucket after a request took, or failed to take, its tokens from it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the bucket holds the tokens the request asked for, zero when it was allowed
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+func newResult(limit Limit, tokens float64, cost int, allowed bool) Result {
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(math.Max(0, math.Floor(tokens))),
+		ResetAfter: time.Duration((limit.burst() - tokens) / limit.rate() * float64(time.Second)),
+	}
+
+	if !allowed {
+		result.RetryAfter = time.Duration((float64(cost) - tokens) / limit.rate() * float64(time.Second))
+	}
+
+	return result
+}
+
+// Limiter keeps token buckets by key, the memory backend is per instance while postgres is shared by every instance.
+// Allow takes cost tokens at once, or none when fewer are left, a cost above the limit's Requests is never allowed.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit, cost int) (Result, error)
+	Close() error
+}
+
+func NewLimiter(config *utils.Config, store database.Store) (Limiter, error) {
+	switch config.RateLimitBackend {
+	case MemoryBackend:
+		return NewMemoryLimiter(), nil
+	case PostgresBackend:
+		return NewPostgresLimiter(store), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend: %s", config.RateLimitBackend)
+	}
+}
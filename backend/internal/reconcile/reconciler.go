@@ -0,0 +1,375 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/logger"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectgc"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/objectstore"
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Options struct {
+	Interval time.Duration
+	// StuckAfter is how long a row may sit in a transient state, or an object without a row, before it is acted on
+	StuckAfter time.Duration
+	BatchSize  int32
+	// DryRun only counts and logs what a pass would change
+	DryRun bool
+}
+
+func OptionsFromConfig(config utils.Config) Options {
+	return Options{
+		Interval:   time.Duration(config.ReconcilerInterval) * time.Minute,
+		StuckAfter: time.Duration(config.ReconcilerStuckAfter) * time.Minute,
+		BatchSize:  int32(config.ReconcilerBatchSize),
+		DryRun:     config.ReconcilerDryRun,
+	}
+}
+
+// Metrics counts what one pass did, in dry-run mode what it would have done.
+type Metrics struct {
+	FilesScanned       int
+	FilesRepaired      int
+	FilesDeleted       int
+	OrphansFound       int
+	ObjectsQuarantined int
+	ObjectsPurged      int
+	Errors             int
+}
+
+// Reconciler settles registry rows abandoned by their request against the object store: a row whose object exists
+// is marked uploaded and unlocked, a row without one is deleted. It also reports objects no row or upload session
+// references and hands them to the collector, when there is one. Passes are recorded in reconciler_runs and only one
+// instance runs a pass per interval.
+type Reconciler struct {
+	store       database.Store
+	objectStore objectstore.ObjectStore
+	collector   *objectgc.Collector
+	logger      *logger.Logger
+	opts        Options
+}
+
+// NewReconciler builds a reconciler, with a nil collector orphaned objects are only reported.
+func NewReconciler(store database.Store, objectStore objectstore.ObjectStore, collector *objectgc.Collector, baseLogger *logger.Logger, opts Options) *Reconciler {
+	return &Reconciler{
+		store:       store,
+		objectStore: objectStore,
+		collector:   collector,
+		logger:      baseLogger,
+		opts:        opts,
+	}
+}
+
+// Run reconciles every interval until ctx is done, a pass in progress stops at the next row.
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error().Err(err).Msg("error while reconciling files")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs a pass unless another instance ran one within the last interval, the metrics are nil then.
+func (r *Reconciler) RunOnce(ctx context.Context) (*Metrics, error) {
+	// a little slack so instances ticking at almost the same time do not skip every other pass
+	run, err := r.store.StartReconcilerRunTx(ctx, r.opts.DryRun, time.Now().Add(-r.opts.Interval*9/10))
+	if err != nil {
+		return nil, fmt.Errorf("error starting reconciler run: %w", err)
+	}
+
+	if run == nil {
+		return nil, nil
+	}
+
+	started := time.Now()
+	metrics := &Metrics{}
+
+	if err := r.reconcileStuckFiles(ctx, metrics); err != nil {
+		r.logger.Error().Err(err).Msg("error while reconciling stuck files")
+		metrics.Errors++
+	}
+
+	runID := pgtype.Int4{Valid: true, Int32: run.ID}
+
+	if err := r.findOrphanedObjects(ctx, runID, metrics); err != nil {
+		r.logger.Error().Err(err).Msg("error while looking for orphaned objects")
+		metrics.Errors++
+	}
+
+	if r.collector != nil && !r.opts.DryRun {
+		purged, err := r.collector.PurgeExpired(ctx, runID, r.opts.BatchSize)
+		metrics.ObjectsPurged += purged
+		if err != nil {
+			r.logger.Error().Err(err).Msg("error while purging quarantined objects")
+			metrics.Errors++
+		}
+	}
+
+	// the pass may have been cut short by shutdown, its counts are still worth keeping
+	finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if err := r.store.FinishReconcilerRun(finishCtx, database.FinishReconcilerRunParams{
+		ID:                 run.ID,
+		FilesScanned:       int32(metrics.FilesScanned),
+		FilesRepaired:      int32(metrics.FilesRepaired),
+		FilesDeleted:       int32(metrics.FilesDeleted),
+		OrphansFound:       int32(metrics.OrphansFound),
+		ObjectsQuarantined: int32(metrics.ObjectsQuarantined),
+		ObjectsPurged:      int32(metrics.ObjectsPurged),
+		Errors:             int32(metrics.Errors),
+	}); err != nil {
+		return metrics, fmt.Errorf("error finishing reconciler run: %w", err)
+	}
+
+	r.logger.Info().
+		Int32("run_id", run.ID).
+		Bool("dry_run", r.opts.DryRun).
+		Int("files_scanned", metrics.FilesScanned).
+		Int("files_repaired", metrics.FilesRepaired).
+		Int("files_deleted", metrics.FilesDeleted).
+		Int("orphans_found", metrics.OrphansFound).
+		Int("objects_quarantined", metrics.ObjectsQuarantined).
+		Int("objects_purged", metrics.ObjectsPurged).
+		Int("errors", metrics.Errors).
+		Dur("duration", time.Since(started)).
+		Msg("reconciler run finished")
+
+	return metrics, nil
+}
+
+func (r *Reconciler) reconcileStuckFiles(ctx context.Context, metrics *Metrics) error {
+	stuckBefore := pgtype.Timestamptz{
+		Valid: true,
+		Time:  time.Now().Add(-r.opts.StuckAfter),
+	}
+
+	var afterID int32
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		files, err := r.store.ListStuckFiles(ctx, database.ListStuckFilesParams{
+			SuccessStatus:       database.Success,
+			StuckBefore:         stuckBefore,
+			AfterID:             afterID,
+			ActiveSessionStatus: database.SessionActive,
+			PageLimit:           r.opts.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			metrics.FilesScanned++
+
+			if err := r.reconcileFile(ctx, file, metrics); err != nil {
+				r.logger.Error().Err(err).Int32("file_id", file.ID).Msg("error while reconciling file")
+				metrics.Errors++
+			}
+		}
+
+		if len(files) < int(r.opts.BatchSize) {
+			return nil
+		}
+
+		afterID = files[len(files)-1].ID
+	}
+}
+
+// reconcileFile settles one row, rows sharing a deduplicated object are settled independently and objects are never
+// deleted here, so an object stays in place for every row still referencing it.
+func (r *Reconciler) reconcileFile(ctx context.Context, file database.ListStuckFilesRow, metrics *Metrics) error {
+	exists := false
+	if file.ObjectKey.Valid {
+		_, err := r.objectStore.Attrs(ctx, file.ObjectKey.String)
+		if err != nil && !errors.Is(err, objectstore.ErrObjectNotExist) {
+			return err
+		}
+		exists = err == nil
+	}
+
+	event := r.logger.Info().
+		Int32("file_id", file.ID).
+		Int32("user_id", file.UserID).
+		Str("upload_status", file.UploadStatus).
+		Bool("locked", file.LockStatus).
+		Bool("dry_run", r.opts.DryRun)
+
+	if exists {
+		if !r.opts.DryRun {
+			rows, err := r.store.RepairStuckFile(ctx, database.RepairStuckFileParams{
+				ID:           file.ID,
+				UpdatedAt:    file.UpdatedAt,
+				UploadStatus: database.Success,
+				LockStatus:   database.Unlocked,
+			})
+			if err != nil {
+				return err
+			}
+			// a request touched the row since the scan, it is no longer abandoned
+			if rows == 0 {
+				return nil
+			}
+		}
+
+		metrics.FilesRepaired++
+		event.Msg("repaired stuck file")
+		return nil
+	}
+
+	if !r.opts.DryRun {
+		rows, err := r.store.DeleteStuckFile(ctx, database.DeleteStuckFileParams{
+			ID:        file.ID,
+			UpdatedAt: file.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return nil
+		}
+	}
+
+	metrics.FilesDeleted++
+	event.Msg("deleted stuck file without object")
+	return nil
+}
+
+// findOrphanedObjects walks the prefix of every user for objects nothing references. Young objects are skipped,
+// a single request upload writes its object before the registry row learns the key.
+func (r *Reconciler) findOrphanedObjects(ctx context.Context, runID pgtype.Int4, metrics *Metrics) error {
+	orphanedBefore := time.Now().Add(-r.opts.StuckAfter)
+
+	var afterID int32
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		userIDs, err := r.store.ListActiveUserIDs(ctx, database.ListActiveUserIDsParams{
+			AfterID:   afterID,
+			PageLimit: r.opts.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			orphans, err := r.orphanedObjectsOf(ctx, userID, orphanedBefore)
+			if err != nil {
+				r.logger.Error().Err(err).Int32("user_id", userID).Msg("error while looking for orphaned objects of user")
+				metrics.Errors++
+				continue
+			}
+
+			for _, orphan := range orphans {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				metrics.OrphansFound++
+
+				if r.collector == nil || r.opts.DryRun {
+					r.logger.Warn().
+						Int32("user_id", userID).
+						Str("object_key", orphan.Key).
+						Int64("size", orphan.Size).
+						Time("updated", orphan.Updated).
+						Bool("dry_run", r.opts.DryRun).
+						Msg("found orphaned object")
+					continue
+				}
+
+				quarantined, err := r.collector.Quarantine(ctx, runID, userID, orphan)
+				if quarantined {
+					metrics.ObjectsQuarantined++
+				}
+				if err != nil {
+					r.logger.Error().Err(err).Int32("user_id", userID).Str("object_key", orphan.Key).Msg("error while quarantining orphaned object")
+					metrics.Errors++
+				}
+			}
+		}
+
+		if len(userIDs) < int(r.opts.BatchSize) {
+			return nil
+		}
+
+		afterID = userIDs[len(userIDs)-1]
+	}
+}
+
+func (r *Reconciler) orphanedObjectsOf(ctx context.Context, userID int32, orphanedBefore time.Time) ([]objectstore.ObjectAttrs, error) {
+	objects, err := r.objectStore.List(ctx, fmt.Sprintf("%d/", userID))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	keys, err := r.store.ListReferencedObjectKeys(ctx, database.ListReferencedObjectKeysParams{
+		UserID:       userID,
+		ActiveStatus: database.SessionActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		referenced[key] = struct{}{}
+	}
+
+	// parts of a paused or long running resumable upload can be far older than orphanedBefore
+	uploadPrefixes, err := r.store.ListActiveUploadPrefixes(ctx, database.ListActiveUploadPrefixesParams{
+		UserID:       userID,
+		ActiveStatus: database.SessionActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]objectstore.ObjectAttrs, 0)
+	for _, object := range objects {
+		if _, ok := referenced[object.Key]; ok || !object.Updated.Before(orphanedBefore) {
+			continue
+		}
+		if slices.ContainsFunc(uploadPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(object.Key, prefix)
+		}) {
+			continue
+		}
+		orphans = append(orphans, object)
+	}
+
+	return orphans, nil
+}
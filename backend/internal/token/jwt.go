@@ -0,0 +1,223 @@
+package token
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DEVunderdog/transcript-generator-backend/internal/utils"
+)
+
+const jwtAlgorithm = "RS256"
+
+// jwtLeeway tolerates clock drift between the services verifying our tokens.
+const jwtLeeway = 30 * time.Second
+
+var (
+	ErrInvalidToken = errors.New("invalid access token")
+	ErrExpiredToken = errors.New("access token expired")
+)
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// Claims of an access token, scopes are space separated like OAuth scope claims.
+type Claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+	IssuedAt  int64  `json:"iat"`
+	ID        string `json:"jti"`
+	APIKeyID  int    `json:"akid"`
+	Scope     string `json:"scope"`
+}
+
+// AccessTokenOptions describes a token to mint, the issuer and audience come from the config.
+type AccessTokenOptions struct {
+	Issuer   string
+	Audience string
+	Type     string
+	Duration time.Duration
+}
+
+// JWK is the public half of a signing key in the format other services expect from a JWKS endpoint.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// AccessTokenOptionsFromConfig reads TOKEN_DURATION as minutes.
+func AccessTokenOptionsFromConfig(config utils.Config) AccessTokenOptions {
+	return AccessTokenOptions{
+		Issuer:   config.Issuer,
+		Audience: config.Audience,
+		Type:     config.TokenType,
+		Duration: time.Duration(config.TokenDuration) * time.Minute,
+	}
+}
+
+func (claims Claims) Scopes() []string {
+	return strings.Fields(claims.Scope)
+}
+
+func (claims Claims) Payload() (Payload, error) {
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return Payload{}, ErrInvalidToken
+	}
+
+	return Payload{
+		UserID:      userID,
+		KeyID:       claims.APIKeyID,
+		Scopes:      claims.Scopes(),
+		AccessToken: true,
+	}, nil
+}
+
+func signingKeyID(id int32) string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// SignAccessToken mints an RS256 jwt for the payload, kid names the signer so verifiers can pick its public key.
+func SignAccessToken(privateKey *rsa.PrivateKey, keyID int32, payload Payload, opts AccessTokenOptions, now time.Time) (string, *Claims, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", nil, fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	claims := &Claims{
+		Issuer:    opts.Issuer,
+		Subject:   strconv.Itoa(payload.UserID),
+		Audience:  opts.Audience,
+		ExpiresAt: now.Add(opts.Duration).Unix(),
+		NotBefore: now.Unix(),
+		IssuedAt:  now.Unix(),
+		ID:        hex.EncodeToString(jti),
+		APIKeyID:  payload.KeyID,
+		Scope:     strings.Join(payload.Scopes, " "),
+	}
+
+	header, err := json.Marshal(jwtHeader{
+		Algorithm: jwtAlgorithm,
+		Type:      opts.Type,
+		KeyID:     signingKeyID(keyID),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+
+	hash := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), claims, nil
+}
+
+// VerifyAccessToken checks the signature with the key named by kid and validates the registered claims,
+// keyFunc is expected to refuse retired keys.
+func VerifyAccessToken(accessToken string, keyFunc func(keyID int32) (*rsa.PublicKey, error), opts AccessTokenOptions, now time.Time) (*Claims, error) {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// pinning the algorithm keeps a forged header from picking a weaker one
+	if header.Algorithm != jwtAlgorithm {
+		return nil, ErrInvalidToken
+	}
+
+	keyID, err := strconv.ParseInt(header.KeyID, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	publicKey, err := keyFunc(int32(keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.Issuer != opts.Issuer || claims.Audience != opts.Audience {
+		return nil, ErrInvalidToken
+	}
+
+	if now.Add(-jwtLeeway).Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	if now.Add(jwtLeeway).Unix() < claims.NotBefore {
+		return nil, ErrInvalidToken
+	}
+
+	return &claims, nil
+}
+
+// NewJWK describes an RSA verification key by its signer id.
+func NewJWK(keyID int32, publicKey *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwtAlgorithm,
+		KeyID:     signingKeyID(keyID),
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}
+}
@@ -0,0 +1,242 @@
+package token
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+)
+
+// touchInterval matches the throttle of the TouchAPIKey query so cached keys skip the write in between.
+const touchInterval = time.Minute
+
+type cachedVerificationKey struct {
+	publicKey *rsa.PublicKey
+	retiresAt time.Time
+	fetchedAt time.Time
+}
+
+// CachedAPIKey is an api key row whose signature was already verified,
+// only its secret hash still has to be compared for every request.
+type CachedAPIKey struct {
+	Details     database.GetAPIKeyRow
+	cachedAt    time.Time
+	lastTouched atomic.Int64
+}
+
+type cachedUserStatus struct {
+	status    database.GetUserStatusRow
+	fetchedAt time.Time
+}
+
+type cachedAPIKeyStatus struct {
+	status    database.GetAPIKeyStatusRow
+	fetchedAt time.Time
+}
+
+// KeyCache keeps parsed verification keys and recently verified api keys in memory
+// so authenticating a request does not cost a database round trip.
+// Entries live for ttl at most, which bounds how long another instance's revocation or rotation goes unnoticed,
+// the local instance drops them right away through the Invalidate hooks.
+type KeyCache struct {
+	store   database.Store
+	purpose string
+	ttl     time.Duration
+
+	mu               sync.RWMutex
+	verificationKeys map[int32]cachedVerificationKey
+
+	apiKeys     *lruCache[string, *CachedAPIKey]
+	users       *lruCache[int32, cachedUserStatus]
+	keyStatuses *lruCache[int32, cachedAPIKeyStatus]
+}
+
+func NewKeyCache(store database.Store, purpose string, ttl time.Duration, size int) *KeyCache {
+	return &KeyCache{
+		store:            store,
+		purpose:          purpose,
+		ttl:              ttl,
+		verificationKeys: make(map[int32]cachedVerificationKey),
+		apiKeys:          newLRUCache[string, *CachedAPIKey](size),
+		users:            newLRUCache[int32, cachedUserStatus](size),
+		keyStatuses:      newLRUCache[int32, cachedAPIKeyStatus](size),
+	}
+}
+
+// VerificationKey returns the public key of a signer, retired keys are refused even while cached.
+func (cache *KeyCache) VerificationKey(ctx context.Context, id int32) (*rsa.PublicKey, error) {
+	now := time.Now()
+
+	cache.mu.RLock()
+	entry, ok := cache.verificationKeys[id]
+	cache.mu.RUnlock()
+
+	if ok && now.Sub(entry.fetchedAt) < cache.ttl && (entry.retiresAt.IsZero() || now.Before(entry.retiresAt)) {
+		return entry.publicKey, nil
+	}
+
+	key, err := cache.store.GetVerificationKey(ctx, database.GetVerificationKeyParams{
+		ID:      id,
+		Purpose: cache.purpose,
+	})
+	if err != nil {
+		cache.InvalidateVerificationKey(id)
+		return nil, err
+	}
+
+	publicKey, err := GetPublicKey([]byte(key.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	entry = cachedVerificationKey{
+		publicKey: publicKey,
+		fetchedAt: now,
+	}
+
+	if key.RetiresAt.Valid {
+		entry.retiresAt = key.RetiresAt.Time
+	}
+
+	cache.mu.Lock()
+	cache.verificationKeys[id] = entry
+	cache.mu.Unlock()
+
+	return publicKey, nil
+}
+
+func (cache *KeyCache) InvalidateVerificationKey(id int32) {
+	cache.mu.Lock()
+	delete(cache.verificationKeys, id)
+	cache.mu.Unlock()
+}
+
+// APIKey returns the cached row of a presented key when its secret matches, a mismatch is reported as a miss.
+func (cache *KeyCache) APIKey(parsed *ParsedAPIKey) (*CachedAPIKey, bool) {
+	entry, ok := cache.apiKeys.Get(parsed.cacheKey())
+	if !ok {
+		return nil, false
+	}
+
+	if time.Since(entry.cachedAt) >= cache.ttl {
+		cache.apiKeys.Remove(parsed.cacheKey())
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare(parsed.SecretHash, entry.Details.SecretHash) != 1 {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// StoreAPIKey remembers a row once its signature has been verified.
+func (cache *KeyCache) StoreAPIKey(parsed *ParsedAPIKey, details database.GetAPIKeyRow) *CachedAPIKey {
+	entry := &CachedAPIKey{
+		Details:  details,
+		cachedAt: time.Now(),
+	}
+
+	cache.apiKeys.Add(parsed.cacheKey(), entry)
+
+	return entry
+}
+
+// InvalidateAPIKey drops a revoked key by its row id.
+func (cache *KeyCache) InvalidateAPIKey(id int32) {
+	cache.apiKeys.RemoveFunc(func(_ string, entry *CachedAPIKey) bool {
+		return entry.Details.ID == id
+	})
+
+	cache.keyStatuses.Remove(id)
+}
+
+// APIKeyStatus returns the owner and expiry of the api key an access token was exchanged for, a revoked key
+// yields pgx.ErrNoRows. Like the other entries it is cached for ttl, which bounds how long a token outlives its key.
+func (cache *KeyCache) APIKeyStatus(ctx context.Context, id int32) (database.GetAPIKeyStatusRow, error) {
+	entry, ok := cache.keyStatuses.Get(id)
+	if ok && time.Since(entry.fetchedAt) < cache.ttl {
+		return entry.status, nil
+	}
+
+	status, err := cache.store.GetAPIKeyStatus(ctx, id)
+	if err != nil {
+		cache.keyStatuses.Remove(id)
+		return status, err
+	}
+
+	cache.keyStatuses.Add(id, cachedAPIKeyStatus{
+		status:    status,
+		fetchedAt: time.Now(),
+	})
+
+	return status, nil
+}
+
+// UserStatus returns the role and suspension of the user behind a request, both change rarely enough to be cached for ttl.
+func (cache *KeyCache) UserStatus(ctx context.Context, userID int32) (database.GetUserStatusRow, error) {
+	entry, ok := cache.users.Get(userID)
+	if ok && time.Since(entry.fetchedAt) < cache.ttl {
+		return entry.status, nil
+	}
+
+	status, err := cache.store.GetUserStatus(ctx, userID)
+	if err != nil {
+		cache.users.Remove(userID)
+		return status, err
+	}
+
+	cache.users.Add(userID, cachedUserStatus{
+		status:    status,
+		fetchedAt: time.Now(),
+	})
+
+	return status, nil
+}
+
+// InvalidateUser drops every cached key of a user along with their status.
+func (cache *KeyCache) InvalidateUser(userID int32) {
+	cache.apiKeys.RemoveFunc(func(_ string, entry *CachedAPIKey) bool {
+		return entry.Details.UserID == userID
+	})
+
+	cache.users.Remove(userID)
+
+	cache.keyStatuses.RemoveFunc(func(_ int32, entry cachedAPIKeyStatus) bool {
+		return entry.status.UserID == userID
+	})
+}
+
+// InvalidateAll forgets everything, used after a rotation re-signed every api key.
+func (cache *KeyCache) InvalidateAll() {
+	cache.mu.Lock()
+	cache.verificationKeys = make(map[int32]cachedVerificationKey)
+	cache.mu.Unlock()
+
+	cache.apiKeys.Purge()
+	cache.users.Purge()
+	cache.keyStatuses.Purge()
+}
+
+// ShouldTouch reports whether last_used_at is due for a write and claims that write for the caller.
+func (entry *CachedAPIKey) ShouldTouch(now time.Time) bool {
+	last := entry.lastTouched.Load()
+	if now.Sub(time.Unix(0, last)) < touchInterval {
+		return false
+	}
+
+	return entry.lastTouched.CompareAndSwap(last, now.UnixNano())
+}
+
+func (parsed ParsedAPIKey) cacheKey() string {
+	if parsed.IsLegacy() {
+		return "legacy:" + hex.EncodeToString(parsed.SecretHash)
+	}
+
+	return parsed.KeyID
+}
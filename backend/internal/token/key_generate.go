@@ -5,42 +5,131 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 )
 
-func GenerateAndSignAPIKey(privateKey *rsa.PrivateKey) (string, []byte, error) {
-	apiKeyBytes := make([]byte, 32)
-	_, err := rand.Read(apiKeyBytes)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate random bytes in API key: %w", err)
+const (
+	APIKeyPrefix = "tg"
+
+	apiKeyIDBytes     = 8
+	apiKeySecretBytes = 32
+)
+
+var ErrMalformedAPIKey = errors.New("malformed api key")
+
+// GeneratedAPIKey is handed out to the user exactly once, only the hash and the signature are persisted.
+type GeneratedAPIKey struct {
+	Key        string
+	KeyID      string
+	SecretHash []byte
+	Signature  []byte
+}
+
+// ParsedAPIKey is an api key presented by a client, KeyID is empty for keys issued before the tg_ format.
+type ParsedAPIKey struct {
+	KeyID      string
+	SecretHash []byte
+}
+
+func (parsed ParsedAPIKey) IsLegacy() bool {
+	return parsed.KeyID == ""
+}
+
+// DisplayPrefix is the non secret part of an api key, safe to show so users can tell their keys apart.
+func DisplayPrefix(keyID string) string {
+	return APIKeyPrefix + "_" + keyID
+}
+
+// GenerateAndSignAPIKey issues a key of the form tg_<keyid>_<secret>,
+// the signature covers the sha256 of the secret so a row cannot be forged by writing a hash alone.
+func GenerateAndSignAPIKey(privateKey *rsa.PrivateKey) (*GeneratedAPIKey, error) {
+	keyIDBytes := make([]byte, apiKeyIDBytes)
+	if _, err := rand.Read(keyIDBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate random bytes in API key id: %w", err)
 	}
 
-	hash := sha256.Sum256(apiKeyBytes)
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate random bytes in API key: %w", err)
+	}
+
+	hash := sha256.Sum256(secret)
 
-	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hash[:])
+	signature, err := signSecretHash(privateKey, hash[:])
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to sign API key: %w", err)
+		return nil, err
 	}
 
-	apiKey := base64.StdEncoding.EncodeToString(apiKeyBytes)
+	keyID := hex.EncodeToString(keyIDBytes)
 
-	return apiKey, signature, nil
+	return &GeneratedAPIKey{
+		Key:        DisplayPrefix(keyID) + "_" + base64.RawURLEncoding.EncodeToString(secret),
+		KeyID:      keyID,
+		SecretHash: hash[:],
+		Signature:  signature,
+	}, nil
 }
 
-func VerifyAPIKey(apiKey string, signature []byte, publicKey *rsa.PublicKey) error {
-
-	apiKeyBytes, err := base64.StdEncoding.DecodeString(apiKey)
+func signSecretHash(privateKey *rsa.PrivateKey, secretHash []byte) ([]byte, error) {
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, secretHash)
 	if err != nil {
-		return fmt.Errorf("failed to decode API key: %w", err)
+		return nil, fmt.Errorf("failed to sign API key: %w", err)
 	}
 
-	hash := sha256.Sum256(apiKeyBytes)
-	
-	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature)
+	return signature, nil
+}
+
+// ParseAPIKey splits a presented key into its id and the hash of its secret,
+// keys without the tg_ prefix are treated as legacy base64 keys.
+func ParseAPIKey(apiKey string) (*ParsedAPIKey, error) {
+	if !strings.HasPrefix(apiKey, APIKeyPrefix+"_") {
+		secret, err := base64.StdEncoding.DecodeString(apiKey)
+		if err != nil || len(secret) != apiKeySecretBytes {
+			return nil, ErrMalformedAPIKey
+		}
+
+		hash := sha256.Sum256(secret)
+		return &ParsedAPIKey{SecretHash: hash[:]}, nil
+	}
+
+	parts := strings.SplitN(apiKey, "_", 3)
+	if len(parts) != 3 || len(parts[1]) != hex.EncodedLen(apiKeyIDBytes) {
+		return nil, ErrMalformedAPIKey
+	}
+
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return nil, ErrMalformedAPIKey
+	}
+
+	secret, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(secret) != apiKeySecretBytes {
+		return nil, ErrMalformedAPIKey
+	}
+
+	hash := sha256.Sum256(secret)
+
+	return &ParsedAPIKey{
+		KeyID:      parts[1],
+		SecretHash: hash[:],
+	}, nil
+}
+
+// VerifyAPIKey compares the presented secret hash with the stored one in constant time
+// and checks the stored hash was signed by the server.
+func VerifyAPIKey(parsed *ParsedAPIKey, storedHash []byte, signature []byte, publicKey *rsa.PublicKey) error {
+	if subtle.ConstantTimeCompare(parsed.SecretHash, storedHash) != 1 {
+		return errors.New("api key secret does not match")
+	}
+
+	err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, storedHash, signature)
 	if err != nil {
 		return fmt.Errorf("failed to verify signature: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -11,8 +11,10 @@ import (
 	"encoding/pem"
 	"errors"
 	"io"
+	"time"
 
 	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/crypto/pbkdf2"
 )
@@ -20,6 +22,7 @@ import (
 const iter = 100000
 
 type JWTKeyResponse struct {
+	ID         int32
 	PublicKey  string
 	PrivateKey []byte
 }
@@ -128,25 +131,35 @@ func encodePublicKey(publicKey *rsa.PublicKey) ([]byte, error) {
 	return pubBytes, nil
 }
 
-func generateAndStoreKeys(passphrase string, store database.Store, ctx context.Context, keyPurpose string) error {
+// generateKeyMaterial returns the PEM public key and the passphrase encrypted private key ready to be stored.
+func generateKeyMaterial(passphrase string) (string, []byte, *rsa.PrivateKey, error) {
 	privateKey, publicKey, err := generateRSAKeys()
 
 	if err != nil {
-		return err
+		return "", nil, nil, err
 	}
 
 	encryptedPrivateKey, err := encryptPrivateKey(privateKey, []byte(passphrase))
 	if err != nil {
-		return err
+		return "", nil, nil, err
 	}
 
 	publicKeyPEM, err := encodePublicKey(publicKey)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return string(publicKeyPEM), encryptedPrivateKey, privateKey, nil
+}
+
+func generateAndStoreKeys(passphrase string, store database.Store, ctx context.Context, keyPurpose string) error {
+	publicKeyPEM, encryptedPrivateKey, _, err := generateKeyMaterial(passphrase)
 	if err != nil {
 		return err
 	}
 
 	args := database.CreateEncryptionKeysParams{
-		PublicKey:  string(publicKeyPEM),
+		PublicKey:  publicKeyPEM,
 		PrivateKey: encryptedPrivateKey,
 		IsActive: pgtype.Bool{
 			Valid: true,
@@ -163,17 +176,37 @@ func generateAndStoreKeys(passphrase string, store database.Store, ctx context.C
 	return nil
 }
 
+// InitializeJWTKeys creates the first signer of a purpose, purposes which already have an active key are left alone.
 func InitializeJWTKeys(passphrase string, store database.Store, ctx context.Context, keyPurpose string) error {
-	count, err := store.CountEncryptionKeys(ctx)
-	if err != nil {
+	_, err := store.GetActiveKeyIDBasedOnPurpose(ctx, keyPurpose)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
 		return err
 	}
 
-	if count == 0 {
-		return generateAndStoreKeys(passphrase, store, ctx, keyPurpose)
+	return generateAndStoreKeys(passphrase, store, ctx, keyPurpose)
+}
+
+// RotateSigningKey generates a new signer for the purpose and re-signs every api key with it,
+// the previous signer keeps verifying until retiresAt.
+func RotateSigningKey(ctx context.Context, store database.Store, passphrase, keyPurpose string, retiresAt time.Time) (*database.RotateSigningKeyTxResult, error) {
+	publicKeyPEM, encryptedPrivateKey, privateKey, err := generateKeyMaterial(passphrase)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return store.RotateSigningKeyTx(ctx, database.RotateSigningKeyTxParams{
+		Purpose:    keyPurpose,
+		PublicKey:  publicKeyPEM,
+		PrivateKey: encryptedPrivateKey,
+		RetiresAt:  retiresAt,
+		Sign: func(secretHash []byte) ([]byte, error) {
+			return signSecretHash(privateKey, secretHash)
+		},
+	})
 }
 
 func GetKeyBasedOnPurpose(ctx context.Context, store database.Store, purpose string) (*JWTKeyResponse, error) {
@@ -183,6 +216,7 @@ func GetKeyBasedOnPurpose(ctx context.Context, store database.Store, purpose str
 	}
 
 	data := &JWTKeyResponse{
+		ID:         jwtStruct.ID,
 		PublicKey:  jwtStruct.PublicKey,
 		PrivateKey: jwtStruct.PrivateKey,
 	}
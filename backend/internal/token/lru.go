@@ -0,0 +1,97 @@
+package token
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// lruCache is a fixed size map which evicts the least recently used entry once full.
+type lruCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List
+}
+
+func newLRUCache[K comparable, V any](capacity int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+func (cache *lruCache[K, V]) Get(key K) (V, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	cache.order.MoveToFront(element)
+
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+func (cache *lruCache[K, V]) Add(key K, value V) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.items[key] = cache.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+
+	if cache.order.Len() > cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.items, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+func (cache *lruCache[K, V]) Remove(key K) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.items[key]; ok {
+		cache.order.Remove(element)
+		delete(cache.items, key)
+	}
+}
+
+// RemoveFunc drops every entry the predicate matches, it walks the whole cache so keep it off hot paths.
+func (cache *lruCache[K, V]) RemoveFunc(match func(key K, value V) bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for element := cache.order.Front(); element != nil; {
+		next := element.Next()
+
+		entry := element.Value.(*lruEntry[K, V])
+		if match(entry.key, entry.value) {
+			cache.order.Remove(element)
+			delete(cache.items, entry.key)
+		}
+
+		element = next
+	}
+}
+
+func (cache *lruCache[K, V]) Purge() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.items = make(map[K]*list.Element, cache.capacity)
+	cache.order.Init()
+}
@@ -1,6 +1,22 @@
 package token
 
+import "time"
+
 type Payload struct {
 	APIKey string
 	UserID int
+	KeyID  int
+	Scopes []string
+	// KeyExpiresAt is when the api key behind the request expires, zero when it never does
+	KeyExpiresAt time.Time
+	// Role is the role of the user, loaded along with their suspension for every request
+	Role string
+	// AccessToken is set when the request carried a jwt instead of the api key itself
+	AccessToken bool
+}
+
+// HasScope reports whether the api key behind the request was granted the scope,
+// the admin scope implies every other one.
+func (payload Payload) HasScope(scope string) bool {
+	return HasScope(payload.Scopes, scope)
 }
@@ -0,0 +1,35 @@
+package token
+
+import "slices"
+
+const (
+	ScopeFilesRead         = "files:read"
+	ScopeFilesWrite        = "files:write"
+	ScopeTranscriptRequest = "transcript:request"
+	ScopeAdmin             = "admin"
+)
+
+// DefaultScopes are granted to keys created without an explicit scope list, admin is never implied.
+var DefaultScopes = []string{ScopeFilesRead, ScopeFilesWrite, ScopeTranscriptRequest}
+
+var validScopes = []string{ScopeFilesRead, ScopeFilesWrite, ScopeTranscriptRequest, ScopeAdmin}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(validScopes, scope)
+}
+
+func HasScope(granted []string, scope string) bool {
+	return slices.Contains(granted, scope) || slices.Contains(granted, ScopeAdmin)
+}
+
+// HasScopes reports whether every requested scope is covered by the granted ones,
+// used so a key can never mint or revoke a key more privileged than itself.
+func HasScopes(granted []string, requested []string) bool {
+	for _, scope := range requested {
+		if !HasScope(granted, scope) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,18 +1,90 @@
 package token
 
 import (
+	"context"
 	"crypto/rsa"
+	"sync"
+	"time"
+
+	database "github.com/DEVunderdog/transcript-generator-backend/internal/database/sqlc"
 )
 
+// TokenMaker holds the private key of the active signer, it reloads the key
+// whenever another signer becomes active so every instance follows a rotation.
 type TokenMaker struct {
-	PublicKey  *rsa.PublicKey
-	PrivateKey *rsa.PrivateKey
+	store      database.Store
+	passphrase string
+	purpose    string
+
+	mu         sync.RWMutex
+	keyID      int32
+	privateKey *rsa.PrivateKey
 }
 
-func NewTokenMaker(publicKey *rsa.PublicKey, privateKey *rsa.PrivateKey) *TokenMaker {
-	return &TokenMaker{
-		PublicKey:  publicKey,
-		PrivateKey: privateKey,
+func NewTokenMaker(ctx context.Context, store database.Store, passphrase, purpose string) (*TokenMaker, error) {
+	maker := &TokenMaker{
+		store:      store,
+		passphrase: passphrase,
+		purpose:    purpose,
+	}
+
+	if err := maker.load(ctx); err != nil {
+		return nil, err
 	}
+
+	return maker, nil
 }
 
+func (maker *TokenMaker) load(ctx context.Context) error {
+	key, err := GetKeyBasedOnPurpose(ctx, maker.store, maker.purpose)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := GetPrivateKey(key.PrivateKey, []byte(maker.passphrase))
+	if err != nil {
+		return err
+	}
+
+	maker.mu.Lock()
+	maker.keyID = key.ID
+	maker.privateKey = privateKey
+	maker.mu.Unlock()
+
+	return nil
+}
+
+// Signer returns the id and private key of the active signer, decrypting it again only after a rotation.
+func (maker *TokenMaker) Signer(ctx context.Context) (int32, *rsa.PrivateKey, error) {
+	activeID, err := maker.store.GetActiveKeyIDBasedOnPurpose(ctx, maker.purpose)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	maker.mu.RLock()
+	keyID, privateKey := maker.keyID, maker.privateKey
+	maker.mu.RUnlock()
+
+	if keyID == activeID {
+		return keyID, privateKey, nil
+	}
+
+	if err := maker.load(ctx); err != nil {
+		return 0, nil, err
+	}
+
+	maker.mu.RLock()
+	defer maker.mu.RUnlock()
+
+	return maker.keyID, maker.privateKey, nil
+}
+
+// CreateAccessToken mints a jwt for the payload with the active signer.
+func (maker *TokenMaker) CreateAccessToken(ctx context.Context, payload Payload, opts AccessTokenOptions) (string, *Claims, error) {
+	keyID, privateKey, err := maker.Signer(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return SignAccessToken(privateKey, keyID, payload, opts, time.Now())
+}
@@ -0,0 +1,56 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WorkerSignatureMaxSkew bounds how old a signed worker request may be, which limits replaying a captured callback.
+const WorkerSignatureMaxSkew = 5 * time.Minute
+
+func workerMAC(secret []byte, timestamp, method, path string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(method))
+	mac.Write([]byte("."))
+	mac.Write([]byte(path))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// SignWorkerPayload returns the hex encoded HMAC-SHA256 of "<timestamp>.<method>.<path>.<body>" which the
+// transcription service sends along with every callback. Signing the path binds the callback to its job, so a
+// captured callback cannot be replayed against another one.
+func SignWorkerPayload(secret []byte, timestamp, method, path string, body []byte) string {
+	return hex.EncodeToString(workerMAC(secret, timestamp, method, path, body))
+}
+
+func VerifyWorkerPayload(secret []byte, timestamp, signature, method, path string, body []byte, now time.Time) error {
+	unixSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse signature timestamp: %w", err)
+	}
+
+	signedAt := time.Unix(unixSeconds, 0)
+	if now.Sub(signedAt) > WorkerSignatureMaxSkew || signedAt.Sub(now) > WorkerSignatureMaxSkew {
+		return errors.New("signature timestamp outside of allowed window")
+	}
+
+	providedMAC, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	if !hmac.Equal(providedMAC, workerMAC(secret, timestamp, method, path, body)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package transcriptformat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FormatText = "txt"
+	FormatSRT  = "srt"
+	FormatVTT  = "vtt"
+	FormatJSON = "json"
+)
+
+type Segment struct {
+	Start   time.Duration
+	End     time.Duration
+	Speaker string
+	Text    string
+}
+
+// ContentType returns the media type a rendered transcript should be served with.
+func ContentType(format string) string {
+	switch format {
+	case FormatSRT:
+		return "application/x-subrip; charset=utf-8"
+	case FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// formatTimestamp renders d as HH:MM:SS<sep>mmm, srt uses a comma before the milliseconds while webvtt uses a dot.
+func formatTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+
+	totalMillis := d.Milliseconds()
+	hours := totalMillis / int64(time.Hour/time.Millisecond)
+	minutes := totalMillis / int64(time.Minute/time.Millisecond) % 60
+	seconds := totalMillis / int64(time.Second/time.Millisecond) % 60
+	millis := totalMillis % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, sep, millis)
+}
+
+func SRTTimestamp(d time.Duration) string {
+	return formatTimestamp(d, ",")
+}
+
+func VTTTimestamp(d time.Duration) string {
+	return formatTimestamp(d, ".")
+}
+
+// cueText collapses blank lines, a blank line inside a cue would end the cue early in both srt and webvtt.
+func cueText(text string) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func RenderText(segments []Segment) string {
+	var builder strings.Builder
+
+	for _, segment := range segments {
+		text := strings.TrimSpace(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		if segment.Speaker != "" {
+			builder.WriteString(segment.Speaker)
+			builder.WriteString(": ")
+		}
+		builder.WriteString(text)
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func RenderSRT(segments []Segment) string {
+	var builder strings.Builder
+
+	cue := 0
+	for _, segment := range segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+		cue++
+
+		if segment.Speaker != "" {
+			text = segment.Speaker + ": " + text
+		}
+
+		fmt.Fprintf(&builder, "%d\n%s --> %s\n%s\n\n", cue, SRTTimestamp(segment.Start), SRTTimestamp(segment.End), text)
+	}
+
+	return builder.String()
+}
+
+// vttEscaper escapes the characters webvtt reads as markup, in cue text as well as in the voice span annotation.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func RenderVTT(segments []Segment) string {
+	var builder strings.Builder
+
+	builder.WriteString("WEBVTT\n\n")
+
+	for _, segment := range segments {
+		text := cueText(segment.Text)
+		if text == "" {
+			continue
+		}
+
+		text = vttEscaper.Replace(text)
+
+		// a line break would end the voice span annotation early
+		if speaker := strings.Join(strings.Fields(segment.Speaker), " "); speaker != "" {
+			text = "<v " + vttEscaper.Replace(speaker) + ">" + text
+		}
+
+		fmt.Fprintf(&builder, "%s --> %s\n%s\n\n", VTTTimestamp(segment.Start), VTTTimestamp(segment.End), text)
+	}
+
+	return builder.String()
+}
+
+// Render produces the textual formats, json is left to the caller so it can share the api response envelope.
+func Render(format string, segments []Segment) (string, error) {
+	switch format {
+	case FormatText:
+		return RenderText(segments), nil
+	case FormatSRT:
+		return RenderSRT(segments), nil
+	case FormatVTT:
+		return RenderVTT(segments), nil
+	default:
+		return "", fmt.Errorf("unsupported transcript format: %s", format)
+	}
+}
@@ -7,17 +7,75 @@ import (
 )
 
 type Config struct {
-	Port          string `mapstructure:"SERVER_PORT"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	Passphrase    string `mapstructure:"PASSPHRASE"`
-	Audience      string `mapstructure:"AUDIENCE"`
-	Issuer        string `mapstructure:"ISSUER"`
-	BucketName    string `mapstructure:"BUCKET_NAME"`
-	TokenType     string `mapstructure:"TOKEN_TYPE"`
-	TokenDuration int    `mapstructure:"TOKEN_DURATION"`
-	KeysPurpose   string `mapstructure:"KEYS_PURPOSE"`
-	TopicID       string `mapstructure:"TOPIC_ID"`
-	ProjectID     string `mapstructure:"PROJECT_ID"`
+	Port                  string `mapstructure:"SERVER_PORT"`
+	DBSource              string `mapstructure:"DB_SOURCE"`
+	Passphrase            string `mapstructure:"PASSPHRASE"`
+	Audience              string `mapstructure:"AUDIENCE"`
+	Issuer                string `mapstructure:"ISSUER"`
+	BucketName            string `mapstructure:"BUCKET_NAME"`
+	TokenType             string `mapstructure:"TOKEN_TYPE"`
+	TokenDuration         int    `mapstructure:"TOKEN_DURATION"`
+	KeysPurpose           string `mapstructure:"KEYS_PURPOSE"`
+	TopicID               string `mapstructure:"TOPIC_ID"`
+	ProjectID             string `mapstructure:"PROJECT_ID"`
+	StorageBackend        string `mapstructure:"STORAGE_BACKEND"`
+	LocalStoragePath      string `mapstructure:"LOCAL_STORAGE_PATH"`
+	S3Endpoint            string `mapstructure:"S3_ENDPOINT"`
+	S3Region              string `mapstructure:"S3_REGION"`
+	S3AccessKey           string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey           string `mapstructure:"S3_SECRET_KEY"`
+	S3UseSSL              bool   `mapstructure:"S3_USE_SSL"`
+	QueueBackend          string `mapstructure:"QUEUE_BACKEND"`
+	QueueTopic            string `mapstructure:"QUEUE_TOPIC"`
+	QueueBufferSize       int    `mapstructure:"QUEUE_BUFFER_SIZE"`
+	NATSURL               string `mapstructure:"NATS_URL"`
+	WorkerSecret          string `mapstructure:"WORKER_SECRET"`
+	MaxUploadSize         int64  `mapstructure:"MAX_RESUMABLE_UPLOAD_SIZE"`
+	UploadExpiry          int    `mapstructure:"UPLOAD_SESSION_EXPIRY_HOURS"`
+	LocalStorageBaseURL   string `mapstructure:"LOCAL_STORAGE_BASE_URL"`
+	StorageSigningSecret  string `mapstructure:"STORAGE_SIGNING_SECRET"`
+	SignedURLExpiry       int    `mapstructure:"SIGNED_URL_EXPIRY_MINUTES"`
+	DuplicateUploadPolicy string `mapstructure:"DUPLICATE_UPLOAD_POLICY"`
+	SigningKeyRetirement  int    `mapstructure:"SIGNING_KEY_RETIREMENT_HOURS"`
+	AuthCacheTTL          int    `mapstructure:"AUTH_CACHE_TTL_SECONDS"`
+	AuthCacheSize         int    `mapstructure:"AUTH_CACHE_SIZE"`
+	MailerBackend         string `mapstructure:"MAILER_BACKEND"`
+	MailerFilePath        string `mapstructure:"MAILER_FILE_PATH"`
+	MailFrom              string `mapstructure:"MAIL_FROM"`
+	SMTPHost              string `mapstructure:"SMTP_HOST"`
+	SMTPPort              int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername          string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword          string `mapstructure:"SMTP_PASSWORD"`
+	VerificationExpiry    int    `mapstructure:"EMAIL_VERIFICATION_EXPIRY_MINUTES"`
+	RateLimitBackend      string `mapstructure:"RATE_LIMIT_BACKEND"`
+	RateLimitPublic       int    `mapstructure:"RATE_LIMIT_PUBLIC_PER_MINUTE"`
+	RateLimitAPI          int    `mapstructure:"RATE_LIMIT_API_PER_MINUTE"`
+	RateLimitTranscript   int    `mapstructure:"RATE_LIMIT_TRANSCRIPT_PER_MINUTE"`
+	ReconcilerEnabled     bool   `mapstructure:"RECONCILER_ENABLED"`
+	ReconcilerInterval    int    `mapstructure:"RECONCILER_INTERVAL_MINUTES"`
+	ReconcilerStuckAfter  int    `mapstructure:"RECONCILER_STUCK_AFTER_MINUTES"`
+	ReconcilerBatchSize   int    `mapstructure:"RECONCILER_BATCH_SIZE"`
+	ReconcilerDryRun      bool   `mapstructure:"RECONCILER_DRY_RUN"`
+	OrphanGCEnabled       bool   `mapstructure:"ORPHAN_GC_ENABLED"`
+	QuarantinePrefix      string `mapstructure:"ORPHAN_QUARANTINE_PREFIX"`
+	QuarantineRetention   int    `mapstructure:"ORPHAN_QUARANTINE_RETENTION_HOURS"`
+	TrashRetention        int    `mapstructure:"FILE_TRASH_RETENTION_HOURS"`
+	// AdminEmails are promoted to the admin role at startup, a comma separated list that bootstraps the first admin
+	AdminEmails []string `mapstructure:"ADMIN_EMAILS"`
+	// TrustedProxies are the comma separated addresses or cidrs whose X-Forwarded-For is believed, TrustedPlatform
+	// names a header the platform sets to the client address instead, either one is needed behind a load balancer
+	TrustedProxies  []string `mapstructure:"TRUSTED_PROXIES"`
+	TrustedPlatform string   `mapstructure:"TRUSTED_PLATFORM_HEADER"`
+}
+
+func checkRequired(keys ...string) error {
+	for _, v := range keys {
+		if !viper.IsSet(v) {
+			return fmt.Errorf("missing required environment variable: %s", v)
+		}
+	}
+
+	return nil
 }
 
 func LoadProdConfig() (config *Config, err error) {
@@ -37,6 +95,81 @@ func LoadProdConfig() (config *Config, err error) {
 	viper.BindEnv("KEYS_PURPOSE")
 	viper.BindEnv("TOPIC_ID")
 	viper.BindEnv("PROJECT_ID")
+	viper.BindEnv("STORAGE_BACKEND")
+	viper.BindEnv("LOCAL_STORAGE_PATH")
+	viper.BindEnv("S3_ENDPOINT")
+	viper.BindEnv("S3_REGION")
+	viper.BindEnv("S3_ACCESS_KEY")
+	viper.BindEnv("S3_SECRET_KEY")
+	viper.BindEnv("S3_USE_SSL")
+	viper.BindEnv("QUEUE_BACKEND")
+	viper.BindEnv("QUEUE_TOPIC")
+	viper.BindEnv("QUEUE_BUFFER_SIZE")
+	viper.BindEnv("NATS_URL")
+	viper.BindEnv("WORKER_SECRET")
+	viper.BindEnv("MAX_RESUMABLE_UPLOAD_SIZE")
+	viper.BindEnv("UPLOAD_SESSION_EXPIRY_HOURS")
+	viper.BindEnv("LOCAL_STORAGE_BASE_URL")
+	viper.BindEnv("STORAGE_SIGNING_SECRET")
+	viper.BindEnv("SIGNED_URL_EXPIRY_MINUTES")
+	viper.BindEnv("DUPLICATE_UPLOAD_POLICY")
+	viper.BindEnv("SIGNING_KEY_RETIREMENT_HOURS")
+	viper.BindEnv("AUTH_CACHE_TTL_SECONDS")
+	viper.BindEnv("AUTH_CACHE_SIZE")
+	viper.BindEnv("MAILER_BACKEND")
+	viper.BindEnv("MAILER_FILE_PATH")
+	viper.BindEnv("MAIL_FROM")
+	viper.BindEnv("SMTP_HOST")
+	viper.BindEnv("SMTP_PORT")
+	viper.BindEnv("SMTP_USERNAME")
+	viper.BindEnv("SMTP_PASSWORD")
+	viper.BindEnv("EMAIL_VERIFICATION_EXPIRY_MINUTES")
+	viper.BindEnv("RATE_LIMIT_BACKEND")
+	viper.BindEnv("RATE_LIMIT_PUBLIC_PER_MINUTE")
+	viper.BindEnv("RATE_LIMIT_API_PER_MINUTE")
+	viper.BindEnv("RATE_LIMIT_TRANSCRIPT_PER_MINUTE")
+	viper.BindEnv("RECONCILER_ENABLED")
+	viper.BindEnv("RECONCILER_INTERVAL_MINUTES")
+	viper.BindEnv("RECONCILER_STUCK_AFTER_MINUTES")
+	viper.BindEnv("RECONCILER_BATCH_SIZE")
+	viper.BindEnv("RECONCILER_DRY_RUN")
+	viper.BindEnv("ORPHAN_GC_ENABLED")
+	viper.BindEnv("ORPHAN_QUARANTINE_PREFIX")
+	viper.BindEnv("ORPHAN_QUARANTINE_RETENTION_HOURS")
+	viper.BindEnv("FILE_TRASH_RETENTION_HOURS")
+	viper.BindEnv("ADMIN_EMAILS")
+	viper.BindEnv("TRUSTED_PROXIES")
+	viper.BindEnv("TRUSTED_PLATFORM_HEADER")
+
+	viper.SetDefault("STORAGE_BACKEND", "gcs")
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("QUEUE_BACKEND", "pubsub")
+	viper.SetDefault("QUEUE_TOPIC", "transcript_requests")
+	viper.SetDefault("QUEUE_BUFFER_SIZE", 100)
+	viper.SetDefault("MAX_RESUMABLE_UPLOAD_SIZE", 5*1024*1024*1024)
+	viper.SetDefault("UPLOAD_SESSION_EXPIRY_HOURS", 24)
+	viper.SetDefault("SIGNED_URL_EXPIRY_MINUTES", 15)
+	viper.SetDefault("DUPLICATE_UPLOAD_POLICY", "reference")
+	viper.SetDefault("SIGNING_KEY_RETIREMENT_HOURS", 168)
+	viper.SetDefault("AUTH_CACHE_TTL_SECONDS", 30)
+	viper.SetDefault("AUTH_CACHE_SIZE", 10000)
+	viper.SetDefault("MAILER_BACKEND", "log")
+	viper.SetDefault("MAIL_FROM", "no-reply@transcript-generator.local")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("EMAIL_VERIFICATION_EXPIRY_MINUTES", 15)
+	viper.SetDefault("RATE_LIMIT_BACKEND", "memory")
+	viper.SetDefault("RATE_LIMIT_PUBLIC_PER_MINUTE", 10)
+	viper.SetDefault("RATE_LIMIT_API_PER_MINUTE", 300)
+	viper.SetDefault("RATE_LIMIT_TRANSCRIPT_PER_MINUTE", 10)
+	viper.SetDefault("RECONCILER_ENABLED", true)
+	viper.SetDefault("RECONCILER_INTERVAL_MINUTES", 10)
+	viper.SetDefault("RECONCILER_STUCK_AFTER_MINUTES", 60)
+	viper.SetDefault("RECONCILER_BATCH_SIZE", 200)
+	viper.SetDefault("RECONCILER_DRY_RUN", false)
+	viper.SetDefault("ORPHAN_GC_ENABLED", true)
+	viper.SetDefault("ORPHAN_QUARANTINE_PREFIX", "quarantine/")
+	viper.SetDefault("ORPHAN_QUARANTINE_RETENTION_HOURS", 168)
+	viper.SetDefault("FILE_TRASH_RETENTION_HOURS", 720)
 
 	required := []string{
 		"SERVER_PORT",
@@ -44,23 +177,74 @@ func LoadProdConfig() (config *Config, err error) {
 		"PASSPHRASE",
 		"AUDIENCE",
 		"ISSUER",
-		"BUCKET_NAME",
 		"TOKEN_TYPE",
 		"TOKEN_DURATION",
 		"KEYS_PURPOSE",
-		"TOPIC_ID",
-		"PROJECT_ID",
+		"WORKER_SECRET",
 	}
 
-	for _, v := range required {
-		if !viper.IsSet(v) {
-			return nil, fmt.Errorf("missing required environment variable: %s", v)
-		}
+	if err := checkRequired(required...); err != nil {
+		return nil, err
+	}
+
+	switch viper.GetString("STORAGE_BACKEND") {
+	case "gcs":
+		err = checkRequired("BUCKET_NAME")
+	case "local":
+		err = checkRequired("LOCAL_STORAGE_PATH", "STORAGE_SIGNING_SECRET")
+	case "s3":
+		err = checkRequired("BUCKET_NAME", "S3_ENDPOINT", "S3_ACCESS_KEY", "S3_SECRET_KEY")
+	default:
+		err = fmt.Errorf("unsupported STORAGE_BACKEND: %s", viper.GetString("STORAGE_BACKEND"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch viper.GetString("QUEUE_BACKEND") {
+	case "pubsub":
+		err = checkRequired("TOPIC_ID", "PROJECT_ID")
+	case "memory", "postgres":
+	case "nats":
+		err = checkRequired("NATS_URL")
+	default:
+		err = fmt.Errorf("unsupported QUEUE_BACKEND: %s", viper.GetString("QUEUE_BACKEND"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch viper.GetString("MAILER_BACKEND") {
+	case "log":
+	case "file":
+		err = checkRequired("MAILER_FILE_PATH")
+	case "smtp":
+		err = checkRequired("SMTP_HOST")
+	default:
+		err = fmt.Errorf("unsupported MAILER_BACKEND: %s", viper.GetString("MAILER_BACKEND"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch viper.GetString("RATE_LIMIT_BACKEND") {
+	case "memory", "postgres":
+	default:
+		return nil, fmt.Errorf("unsupported RATE_LIMIT_BACKEND: %s", viper.GetString("RATE_LIMIT_BACKEND"))
+	}
+
+	switch viper.GetString("DUPLICATE_UPLOAD_POLICY") {
+	case "reject", "reference":
+	default:
+		return nil, fmt.Errorf("unsupported DUPLICATE_UPLOAD_POLICY: %s", viper.GetString("DUPLICATE_UPLOAD_POLICY"))
 	}
 
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config: %v", err)
 	}
-	
+
 	return
 }
@@ -32,6 +32,16 @@ class ASRModel:
         soundfile.write(resampled_file_path, audio, self.sampling_rate)
         return resampled_file_path
 
+    def to_segments(self, chunks):
+        segments = []
+        for chunk in chunks:
+            start, end = chunk["timestamp"]
+            start = start or 0.0
+            # the last chunk can come back without an end timestamp
+            end = end if end is not None else start
+            segments.append({"start": start, "end": max(start, end), "text": chunk["text"]})
+        return segments
+
     def generate_transcript(self, model, processor, file):
         try:
             pipe = pipeline(
@@ -44,7 +54,7 @@ class ASRModel:
                 chunk_length_s=self.chunk_length,
             )
 
-            result = pipe(file)
-            return result["text"]
+            result = pipe(file, return_timestamps=True)
+            return result["text"], self.to_segments(result.get("chunks", []))
         except Exception as e:
             raise RuntimeError(f"failed to process audi file: {str(e)}")
@@ -0,0 +1,3 @@
+from callback.job_status import JobStatusReporter
+
+__all__ = ["JobStatusReporter"]
@@ -0,0 +1,60 @@
+import hashlib
+import hmac
+import json
+import time
+
+import requests
+from logger import logger
+
+
+class JobStatusReporter:
+    def __init__(self, backend_url: str, worker_secret: str):
+        self.backend_url = backend_url.rstrip("/")
+        self.worker_secret = worker_secret.encode()
+        self.timeout = 10
+
+    def _report(self, job_id: int, body: dict):
+        # messages published before jobs were tracked carry no job id
+        if not job_id:
+            return
+
+        data = json.dumps(body).encode()
+        path = f"/server/internal/jobs/{job_id}/status"
+        timestamp = str(int(time.time()))
+        # the method and path are signed too, so the callback cannot be replayed against another job
+        message = f"{timestamp}.POST.{path}.".encode() + data
+        signature = hmac.new(self.worker_secret, message, hashlib.sha256).hexdigest()
+
+        try:
+            response = requests.post(
+                f"{self.backend_url}{path}",
+                data=data,
+                headers={
+                    "Content-Type": "application/json",
+                    "X-Worker-Timestamp": timestamp,
+                    "X-Worker-Signature": signature,
+                },
+                timeout=self.timeout,
+            )
+            response.raise_for_status()
+        except requests.RequestException as e:
+            logger.error(f"failed to report status of job {job_id}: {str(e)}")
+
+    def started(self, job_id: int):
+        self._report(job_id, {"status": "started"})
+
+    def progress(self, job_id: int, progress: int):
+        self._report(job_id, {"status": "progress", "progress": progress})
+
+    def completed(self, job_id: int, transcript_object_key: str, segments: list):
+        self._report(
+            job_id,
+            {
+                "status": "completed",
+                "transcript_object_key": transcript_object_key,
+                "segments": segments,
+            },
+        )
+
+    def failed(self, job_id: int, reason: str):
+        self._report(job_id, {"status": "failed", "reason": reason})
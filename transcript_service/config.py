@@ -4,12 +4,28 @@ from pydantic_settings import BaseSettings, SettingsConfigDict
 class Settings(BaseSettings):
     model_config = SettingsConfigDict(case_sensitive=False)
 
-    bucket_name: str
-    project_id: str
-    subscription_id: str
     sender_email: str
     sender_password: str
     server_port: int
+    backend_url: str
+    worker_secret: str
+
+    # must match QUEUE_BACKEND and STORAGE_BACKEND of the backend
+    queue_backend: str = "pubsub"
+    storage_backend: str = "gcs"
+
+    # pubsub queue and gcs storage
+    project_id: str = ""
+    subscription_id: str = ""
+    bucket_name: str = ""
+
+    # postgres and nats queues, the topic defaults to the backend's QUEUE_TOPIC
+    queue_topic: str = "transcript_requests"
+    database_url: str = ""
+    nats_url: str = ""
+
+    # local storage, the LOCAL_STORAGE_PATH of the backend
+    local_storage_path: str = ""
 
 
 settings = Settings()
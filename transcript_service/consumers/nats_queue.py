@@ -0,0 +1,54 @@
+import asyncio
+from typing import Callable
+
+import nats
+from consumers.queue_message import QueueMessage
+from logger import logger
+
+
+class NATSQueue:
+    """Consumes the subject the backend publishes to with QUEUE_BACKEND=nats.
+
+    Workers join one queue group so every message is handled by a single worker. The backend publishes on core
+    nats, which delivers at most once: messages published while no worker is subscribed are lost, and ack or nack
+    have nothing to settle.
+    """
+
+    def __init__(self, url: str, subject: str):
+        self.url = url
+        self.subject = subject
+        self.queue_group = "transcript_service"
+        self.stop_event = None
+
+    def start_listening(self, callback: Callable[[QueueMessage], None]):
+        logger.info(f"listening message on nats subject {self.subject}..\n")
+
+        try:
+            asyncio.run(self._listen(callback))
+        except KeyboardInterrupt:
+            logger.info("keyboard interrupt detected within start listening")
+        except Exception as e:
+            logger.error(
+                f"listening for messages on nats subject {self.subject} threw an exception: {str(e)}"
+            )
+
+    async def _listen(self, callback: Callable[[QueueMessage], None]):
+        self.stop_event = asyncio.Event()
+        conn = await nats.connect(self.url)
+
+        try:
+            subscription = await conn.subscribe(self.subject, queue=self.queue_group)
+
+            async for msg in subscription.messages:
+                if self.stop_event.is_set():
+                    break
+
+                # transcription blocks for minutes, running it off the loop keeps the connection answering pings
+                await asyncio.to_thread(callback, QueueMessage(data=msg.data))
+        finally:
+            await conn.drain()
+
+    def stop_listening(self):
+        logger.info("subscription stopped")
+        if self.stop_event:
+            self.stop_event.set()
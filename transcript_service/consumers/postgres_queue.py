@@ -0,0 +1,90 @@
+from typing import Callable
+
+import psycopg
+from psycopg import sql
+from consumers.queue_message import QueueMessage
+from logger import logger
+
+
+class PostgresQueue:
+    """Consumes the message_queue table the backend writes to with QUEUE_BACKEND=postgres.
+
+    The backend raises a NOTIFY on the topic channel for every message, the consumer wakes up on it and claims the
+    oldest message with skip locked, so several workers can share a topic. Acking deletes the row and nacking
+    releases the claim, a claim left behind by a crashed worker is taken over once it is older than claim_timeout.
+    """
+
+    claim_query = """
+        update message_queue
+        set claimed_at = current_timestamp
+        where id = (
+            select pending.id from message_queue pending
+            where
+                pending.topic = %(topic)s
+                and (
+                    pending.claimed_at is null
+                    or pending.claimed_at < current_timestamp - make_interval(secs => %(claim_timeout)s)
+                )
+            order by pending.id
+            for update skip locked
+            limit 1
+        )
+        returning id, payload
+    """
+
+    def __init__(self, database_url: str, topic: str):
+        self.database_url = database_url
+        self.topic = topic
+        # messages are also polled for, which picks up stale claims and notifies sent while reconnecting
+        self.poll_interval = 30
+        self.claim_timeout = 600
+        self.running = False
+
+    def start_listening(self, callback: Callable[[QueueMessage], None]):
+        logger.info(f"listening message on postgres topic {self.topic}..\n")
+        self.running = True
+
+        try:
+            with psycopg.connect(self.database_url, autocommit=True) as conn:
+                conn.execute(sql.SQL("listen {}").format(sql.Identifier(self.topic)))
+
+                while self.running:
+                    message = self._claim(conn)
+                    if message is None:
+                        for _ in conn.notifies(timeout=self.poll_interval, stop_after=1):
+                            pass
+                        continue
+
+                    callback(message)
+        except KeyboardInterrupt:
+            logger.info("keyboard interrupt detected within start listening")
+            self.stop_listening()
+        except Exception as e:
+            logger.error(
+                f"listening for messages on postgres topic {self.topic} threw an exception: {str(e)}"
+            )
+
+    def _claim(self, conn: psycopg.Connection):
+        row = conn.execute(
+            self.claim_query,
+            {"topic": self.topic, "claim_timeout": self.claim_timeout},
+        ).fetchone()
+        if row is None:
+            return None
+
+        message_id, payload = row
+
+        return QueueMessage(
+            data=bytes(payload),
+            on_ack=lambda: conn.execute(
+                "delete from message_queue where id = %s", (message_id,)
+            ),
+            on_nack=lambda: conn.execute(
+                "update message_queue set claimed_at = null where id = %s",
+                (message_id,),
+            ),
+        )
+
+    def stop_listening(self):
+        logger.info("subscription stopped")
+        self.running = False
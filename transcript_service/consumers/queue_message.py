@@ -0,0 +1,31 @@
+from typing import Callable, Optional
+
+
+class QueueMessage:
+    """Mirrors the data, ack and nack of a pub/sub message, so the service callback runs unchanged on every backend."""
+
+    def __init__(
+        self,
+        data: bytes,
+        on_ack: Optional[Callable[[], None]] = None,
+        on_nack: Optional[Callable[[], None]] = None,
+    ):
+        self.data = data
+        self._on_ack = on_ack
+        self._on_nack = on_nack
+        self._settled = False
+
+    def ack(self):
+        self._settle(self._on_ack)
+
+    def nack(self):
+        self._settle(self._on_nack)
+
+    # like pub/sub only the first ack or nack counts, the callback nacks on failure even after acking
+    def _settle(self, action: Optional[Callable[[], None]]):
+        if self._settled:
+            return
+        self._settled = True
+
+        if action:
+            action()
@@ -33,3 +33,10 @@ class CloudStorage:
                 file_name = name.split("/", 1)[1]
 
         return file, file_name
+
+    def upload_transcript(self, source_file: str, object_key: str):
+        storage_client = Client(project=self.project_id)
+        bucket = storage_client.bucket(self.bucket_name)
+        blob = bucket.blob(object_key)
+        blob.upload_from_filename(source_file)
+        logger.info(f"uploaded transcript to {object_key}")
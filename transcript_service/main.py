@@ -10,13 +10,7 @@ from http.server import HTTPServer
 from server import HealthCheckHandler
 
 if __name__ == "__main__":
-    execute_service = Service(
-        project_id=settings.project_id,
-        bucket_name=settings.bucket_name,
-        subscription_id=settings.subscription_id,
-        sender_email=settings.sender_email,
-        sender_password=settings.sender_password,
-    )
+    execute_service = Service(settings=settings)
 
     signal.signal(signal.SIGINT, execute_service.signal_handler)
     signal.signal(signal.SIGTERM, execute_service.signal_handler)
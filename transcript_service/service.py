@@ -3,34 +3,65 @@ import shutil
 from pathlib import Path
 
 from audio_model.audio_model import ASRModel
+from callback.job_status import JobStatusReporter
 from constants import constants
+from config import Settings
 from custom_proto.message_pb2 import TopicMessage
-from gcp.cloud_pubsub import CloudPubSub
-from gcp.cloud_storage import CloudStorage
-from google.cloud import pubsub_v1
 from logger import logger
 from mail.transcript_email import TranscriptEmail
 from pdf.generate_pdf import PdfProcessor
 
 
-class Service:
-    def __init__(
-        self,
-        project_id: str,
-        bucket_name: str,
-        subscription_id: str,
-        sender_email: str,
-        sender_password: str,
-    ):
-        self.cloudPubSub = CloudPubSub(
-            project_id=project_id, subscription_id=subscription_id
+def new_consumer(settings: Settings):
+    # backends are imported on demand so a deployment only needs the client library of the one it uses
+    if settings.queue_backend == "pubsub":
+        from gcp.cloud_pubsub import CloudPubSub
+
+        return CloudPubSub(
+            project_id=settings.project_id, subscription_id=settings.subscription_id
+        )
+    if settings.queue_backend == "postgres":
+        from consumers.postgres_queue import PostgresQueue
+
+        return PostgresQueue(
+            database_url=settings.database_url, topic=settings.queue_topic
+        )
+    if settings.queue_backend == "nats":
+        from consumers.nats_queue import NATSQueue
+
+        return NATSQueue(url=settings.nats_url, subject=settings.queue_topic)
+
+    # the memory queue lives inside the backend process, there is nothing to consume from here
+    raise ValueError(f"unsupported queue backend: {settings.queue_backend}")
+
+
+def new_storage(settings: Settings):
+    if settings.storage_backend == "gcs":
+        from gcp.cloud_storage import CloudStorage
+
+        return CloudStorage(
+            project_id=settings.project_id, bucket_name=settings.bucket_name
         )
-        self.cloudStorage = CloudStorage(project_id=project_id, bucket_name=bucket_name)
+    if settings.storage_backend == "local":
+        from storage.local_storage import LocalStorage
+
+        return LocalStorage(root=settings.local_storage_path)
+
+    raise ValueError(f"unsupported storage backend: {settings.storage_backend}")
+
+
+class Service:
+    def __init__(self, settings: Settings):
+        self.queueConsumer = new_consumer(settings)
+        self.objectStorage = new_storage(settings)
         self.asrModel = ASRModel()
         self.pdfProcessor = PdfProcessor()
         self.emailProcessor = TranscriptEmail(
-            sender_email=sender_email,
-            sender_password=sender_password,
+            sender_email=settings.sender_email,
+            sender_password=settings.sender_password,
+        )
+        self.jobStatusReporter = JobStatusReporter(
+            backend_url=settings.backend_url, worker_secret=settings.worker_secret
         )
 
     def cleanup(self):
@@ -46,41 +77,61 @@ class Service:
                     elif sub_item.is_dir():
                         shutil.rmtree(sub_item)
 
-    def custom_callback(self, message: pubsub_v1.subscriber.message.Message):
+    # message is a pub/sub message or a QueueMessage, both offer data, ack and nack
+    def custom_callback(self, message):
+        job_id = 0
         try:
             topic_message = TopicMessage()
             topic_message.ParseFromString(message.data)
             message.ack()
 
+            job_id = topic_message.job_id
+            self.jobStatusReporter.started(job_id)
+
             object_key = topic_message.object_key
             objects_list = [object_key]
 
             user_email = topic_message.user_email
 
-            file, file_name = self.cloudStorage.download_audio_files(objects_list)
+            file, file_name = self.objectStorage.download_audio_files(objects_list)
 
             resample_file = self.asrModel.resample_file(file=file, file_name=file_name)
+            self.jobStatusReporter.progress(job_id, 25)
 
             model, processor = self.asrModel.instantiate_model()
 
-            transcript = self.asrModel.generate_transcript(
+            transcript, segments = self.asrModel.generate_transcript(
                 model=model, processor=processor, file=resample_file
             )
 
+            self.jobStatusReporter.progress(job_id, 75)
+
             self.pdfProcessor.generate_pdf(content=transcript)
+
+            transcript_object_key = (
+                f"transcripts/{topic_message.user_id}/{job_id}.pdf"
+            )
+            self.objectStorage.upload_transcript(
+                source_file=self.pdfProcessor.filename,
+                object_key=transcript_object_key,
+            )
+
             self.emailProcessor.send_email(recipient_email=user_email)
 
             logger.info("mail sent successfully")
 
+            self.jobStatusReporter.completed(job_id, transcript_object_key, segments)
+
             self.cleanup()
             logger.info("cleanup up is done successfully")
 
         except Exception as e:
             logger.error(f"error parsing the message {str(e)}")
+            self.jobStatusReporter.failed(job_id, str(e))
             message.nack()
 
     def run_service(self):
-        self.cloudPubSub.start_listening(callback=self.custom_callback)
+        self.queueConsumer.start_listening(callback=self.custom_callback)
 
     def signal_handler(self, sig, frame):
         logger.info("termination signal received cleaning up...")
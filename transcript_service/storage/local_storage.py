@@ -0,0 +1,48 @@
+import os
+import shutil
+from typing import List
+from logger import logger
+from constants import constants
+
+
+class LocalStorage:
+    """Reads and writes objects in the directory the backend uses with STORAGE_BACKEND=local, the object key is the
+    path relative to it. Both services have to see the same directory, e.g. through a shared volume."""
+
+    def __init__(self, root: str):
+        self.destination_directory = constants.download_file_path
+        self.root = os.path.abspath(root)
+
+    def _object_path(self, key: str) -> str:
+        path = os.path.abspath(os.path.join(self.root, key))
+        if not path.startswith(self.root + os.sep):
+            raise ValueError(f"object key escapes storage root: {key}")
+        return path
+
+    def download_audio_files(self, blob_names: List[str]) -> tuple[str, str]:
+        file = None
+        file_name = None
+
+        for name in blob_names:
+            destination = self.destination_directory + "/" + name
+            try:
+                os.makedirs(os.path.dirname(destination), exist_ok=True)
+                shutil.copyfile(self._object_path(name), destination)
+            except Exception as e:
+                logger.error(f"failed to download {name} due to exception: {e}")
+            else:
+                logger.info(f"downloaded {name} to {self.destination_directory}")
+                file = destination
+                file_name = name.split("/", 1)[1]
+
+        return file, file_name
+
+    def upload_transcript(self, source_file: str, object_key: str):
+        path = self._object_path(object_key)
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+
+        # copy next to the destination and rename, the backend never sees a partial transcript
+        temp_path = path + ".upload"
+        shutil.copyfile(source_file, temp_path)
+        os.replace(temp_path, path)
+        logger.info(f"uploaded transcript to {object_key}")